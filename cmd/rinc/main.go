@@ -2,31 +2,38 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
 	"log/slog"
 	"os"
+	"time"
 
+	"github.com/accuknox/rinc/internal/check"
 	"github.com/accuknox/rinc/internal/conf"
 	"github.com/accuknox/rinc/internal/db"
 	"github.com/accuknox/rinc/internal/job"
 	"github.com/accuknox/rinc/internal/kube"
+	"github.com/accuknox/rinc/internal/output"
+	"github.com/accuknox/rinc/internal/repl"
 	"github.com/accuknox/rinc/internal/schema"
+	"github.com/accuknox/rinc/internal/snapshot"
+	"github.com/accuknox/rinc/internal/telemetry"
 	"github.com/accuknox/rinc/internal/web"
+
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"k8s.io/client-go/rest"
 )
 
 func main() {
-	conf, err := conf.New(os.Args[1:]...)
+	// the schema doesn't depend on the config, which may well be missing or
+	// incomplete, so it's generated before loading any
+	generateSchema, err := conf.ParseGenerateSchema(os.Args[1:]...)
 	if err != nil {
 		log.Fatal(err)
 	}
-	err = conf.Validate()
-	if err != nil {
-		log.Fatalf("validating provided config: %s", err.Error())
-	}
-
-	if conf.GenerateSchema != "" {
-		schema, err := schema.Generate(conf.GenerateSchema)
+	if generateSchema != "" {
+		schema, err := schema.Generate(generateSchema)
 		if err != nil {
 			log.Fatalf("generating schema: %s", err.Error())
 		}
@@ -34,7 +41,40 @@ func main() {
 		return
 	}
 
-	mongo, err := db.NewMongoDBClient(conf.Mongodb)
+	c, err := conf.New(os.Args[1:]...)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	err = c.Validate()
+	if err != nil {
+		log.Fatalf("validating provided config: %s", err.Error())
+	}
+
+	if c.REPL.Enable && c.REPL.File != "" {
+		runREPL(nil, c.REPL)
+		return
+	}
+
+	shutdownTracing, err := telemetry.SetupTracing(context.Background(), c.Telemetry.Tracing)
+	if err != nil {
+		log.Fatalf("setting up tracing: %s", err.Error())
+	}
+	defer flushTraces(shutdownTracing)
+
+	if c.Check.Enable {
+		code := runCheck(context.Background(), c)
+		flushTraces(shutdownTracing)
+		os.Exit(code)
+	}
+
+	if c.Output.Format != "" {
+		code := scrapeToOutput(context.Background(), c)
+		flushTraces(shutdownTracing)
+		os.Exit(code)
+	}
+
+	mongo, err := db.NewMongoDBClient(c.Mongodb)
 	if err != nil {
 		log.Fatalf("creating mongo client: %s", err.Error())
 	}
@@ -56,26 +96,208 @@ func main() {
 		)
 	}()
 
-	if conf.RunAsScraper {
-		kubeClient, err := kube.NewClient(conf.KubernetesClient)
+	if c.REPL.Enable {
+		runREPL(mongo, c.REPL)
+		return
+	}
+
+	if c.Scrapes() {
+		err := scrape(context.Background(), c, mongo, nil)
 		if err != nil {
-			log.Fatalf("kubernetes client: %s", err.Error())
+			// the traces of a failed scrape are the most useful ones
+			flushTraces(shutdownTracing)
+			log.Fatal(err)
+		}
+		return
+	}
+
+	reloader := conf.NewReloader(c, os.Args[1:]...)
+	srv, err := web.NewSrv(reloader, mongo)
+	if err != nil {
+		log.Fatalf("creating web server instance: %s", err.Error())
+	}
+	srv.Run(context.Background())
+}
+
+// flushTraces exports the pending spans, see telemetry.SetupTracing.
+func flushTraces(shutdown func(context.Context) error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	err := shutdown(ctx)
+	if err != nil {
+		log.Printf("flushing traces: %s", err.Error())
+	}
+}
+
+const (
+	// exitCritical is the exit code of a scrape with --output, or of the
+	// check command, on which critical alerts fired.
+	exitCritical = 2
+	// exitWarning is the exit code of the check command on which warning
+	// alerts, but no critical ones, fired.
+	exitWarning = 3
+)
+
+// runCheck runs the health gate of the check command, and returns the exit
+// code reflecting the worst severity of the alerts that fired.
+func runCheck(ctx context.Context, c *conf.C) int {
+	restConf, err := kube.RESTConfig(c.KubernetesClient)
+	if err != nil {
+		log.Printf("kubernetes client config: %s", err.Error())
+		return 1
+	}
+	kubeClient, err := kube.NewClient(restConf)
+	if err != nil {
+		log.Printf("kubernetes client: %s", err.Error())
+		return 1
+	}
+	metricsClient, err := kube.NewMetricsClient(restConf)
+	if err != nil {
+		log.Printf("kubernetes metrics client: %s", err.Error())
+		return 1
+	}
+
+	ticker := time.NewTicker(c.Check.Interval)
+	defer ticker.Stop()
+	summary, err := check.Run(ctx, c.Check, ticker.C, func(ctx context.Context, out job.Output) (err error) {
+		ctx, span := telemetry.Start(ctx, "check")
+		defer func() {
+			telemetry.End(span, err)
+		}()
+		opts := job.Options{
+			Output:    out,
+			Reporters: c.Check.Reporters,
+		}
+		return job.New(*c, kubeClient, metricsClient, nil, opts).GenerateAll(ctx)
+	})
+	if err != nil {
+		log.Printf("checking: %s", err.Error())
+		return 1
+	}
+	err = summary.Print(os.Stdout)
+	if err != nil {
+		log.Print(err)
+		return 1
+	}
+	switch summary.Severity() {
+	case conf.SeverityCritical:
+		return exitCritical
+	case conf.SeverityWarning:
+		return exitWarning
+	default:
+		return 0
+	}
+}
+
+// scrapeToOutput generates all reports into the configured output instead of
+// MongoDB, and returns the exit code.
+func scrapeToOutput(ctx context.Context, c *conf.C) int {
+	w, err := output.New(c.Output, os.Stdout)
+	if err != nil {
+		log.Print(err)
+		return 1
+	}
+	err = scrape(ctx, c, nil, w)
+	// the reports generated before a failure are written regardless
+	err = errors.Join(err, w.Close())
+	if err != nil {
+		log.Print(err)
+		return 1
+	}
+	if w.Severity() == conf.SeverityCritical {
+		log.Print("critical alerts fired")
+		return exitCritical
+	}
+	return 0
+}
+
+// scrape generates all reports, which are written to out instead of MongoDB
+// if it is non-nil. The inputs of the reporters are recorded into a bundle,
+// or replayed from one, if requested. The scrape is traced as the parent span
+// of the reporters, and its metrics are pushed to the Pushgateway once it is
+// done, if one is configured.
+func scrape(ctx context.Context, c *conf.C, mongo *mongo.Client, out job.Output) (err error) {
+	ctx, span := telemetry.Start(ctx, "scrape")
+	defer func(start time.Time) {
+		telemetry.End(span, err)
+		telemetry.ObserveScrape(start, err)
+		if c.Telemetry.Pushgateway.URL == "" {
+			return
+		}
+		pushErr := telemetry.Push(ctx, c.Telemetry.Pushgateway)
+		if pushErr != nil {
+			slog.LogAttrs(
+				ctx,
+				slog.LevelError,
+				"pushing scrape metrics",
+				slog.String("error", pushErr.Error()),
+			)
 		}
-		metricsClient, err := kube.NewMetricsClient(conf.KubernetesClient)
+	}(time.Now())
+
+	var (
+		restConf *rest.Config
+		recorder *snapshot.Recorder
+		opts     job.Options
+	)
+	if c.Snapshot.Replay != "" {
+		bundle, err := snapshot.Open(c.Snapshot.Replay)
 		if err != nil {
-			log.Fatalf("kubernetes metrics client: %s", err.Error())
+			return fmt.Errorf("opening snapshot: %w", err)
+		}
+		restConf = bundle.RESTConfig()
+		opts = job.Options{
+			Network: bundle,
+			Now:     bundle.Timestamp(),
 		}
-		job := job.New(*conf, kubeClient, metricsClient, mongo)
-		err = job.GenerateAll(context.Background())
+	} else {
+		restConf, err = kube.RESTConfig(c.KubernetesClient)
 		if err != nil {
-			log.Fatalf("generating reports: %s", err.Error())
+			return fmt.Errorf("kubernetes client config: %w", err)
+		}
+		if c.Snapshot.Record != "" {
+			recorder = snapshot.NewRecorder()
+			restConf = recorder.RESTConfig(restConf)
+			opts = job.Options{
+				Network: recorder,
+				Now:     time.Now().UTC().Round(time.Second),
+			}
 		}
-		return
 	}
 
-	srv, err := web.NewSrv(*conf, mongo)
+	opts.Output = out
+	restConf.Wrap(telemetry.KubeTransport)
+
+	kubeClient, err := kube.NewClient(restConf)
 	if err != nil {
-		log.Fatalf("creating web server instance: %s", err.Error())
+		return fmt.Errorf("kubernetes client: %w", err)
+	}
+	metricsClient, err := kube.NewMetricsClient(restConf)
+	if err != nil {
+		return fmt.Errorf("kubernetes metrics client: %w", err)
+	}
+	err = job.New(*c, kubeClient, metricsClient, mongo, opts).GenerateAll(ctx)
+	if err != nil {
+		err = fmt.Errorf("generating reports: %w", err)
+	}
+	if recorder != nil {
+		// a partial snapshot helps debugging the failed reports
+		saveErr := recorder.Save(c.Snapshot.Record, opts.Now)
+		if saveErr != nil {
+			err = errors.Join(err, fmt.Errorf("saving snapshot: %w", saveErr))
+		}
+	}
+	return err
+}
+
+func runREPL(mongo *mongo.Client, c conf.REPL) {
+	ctx := context.Background()
+	metrics, err := repl.Load(ctx, mongo, c)
+	if err != nil {
+		log.Fatalf("loading report: %s", err.Error())
+	}
+	err = repl.Run(ctx, os.Stdin, os.Stdout, metrics)
+	if err != nil {
+		log.Fatalf("running expression prompt: %s", err.Error())
 	}
-	srv.Run(context.Background())
 }
@@ -0,0 +1,175 @@
+// Package check implements the health gate of the `check` subcommand, which
+// generates the selected reports repeatedly until a timeout and summarizes
+// the alerts that fired on them.
+package check
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"slices"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	"github.com/accuknox/rinc/internal/conf"
+	"github.com/accuknox/rinc/internal/db"
+	"github.com/accuknox/rinc/internal/job"
+)
+
+// Run generates reports by calling run once, and again on every tick until
+// the timeout, or until a critical alert fires since the outcome can't get
+// any worse. ticks is usually the channel of a time.Ticker with the interval
+// of c. A run writes the generated reports and their firing alerts to the
+// provided output. Failed runs are logged and skipped; an error is only
+// returned if no run succeeded.
+func Run(ctx context.Context, c conf.Check, ticks <-chan time.Time, run func(context.Context, job.Output) error) (Summary, error) {
+	for _, r := range c.Reporters {
+		if !slices.Contains(db.Collections, r) {
+			return Summary{}, fmt.Errorf("unknown reporter %q, must be one of %v", r, db.Collections)
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, c.Timeout)
+	defer cancel()
+
+	s := Summary{seen: make(map[Alert]int)}
+	var lastErr error
+	for {
+		out := new(collector)
+		err := run(ctx, out)
+		switch {
+		case err != nil && ctx.Err() != nil:
+			// the run was interrupted by the timeout
+		case err != nil:
+			slog.LogAttrs(
+				ctx,
+				slog.LevelWarn,
+				"check run failed",
+				slog.String("error", err.Error()),
+			)
+			lastErr = err
+		default:
+			s.add(out.reports)
+		}
+		if s.Severity() == conf.SeverityCritical {
+			return s, nil
+		}
+		select {
+		case <-ctx.Done():
+		case <-ticks:
+		}
+		// checked after the select, which picks either case at random if
+		// both are ready
+		if ctx.Err() != nil {
+			if s.Runs == 0 {
+				if lastErr == nil {
+					lastErr = ctx.Err()
+				}
+				return s, fmt.Errorf("no successful run within %s: %w", c.Timeout, lastErr)
+			}
+			return s, nil
+		}
+	}
+}
+
+// Alert is an alert that fired on a report.
+type Alert struct {
+	// Reporter is the collection of the report, e.g., dass.
+	Reporter string
+	Severity conf.Severity
+	Message  string
+}
+
+// Summary summarizes the runs of a check.
+type Summary struct {
+	// Runs is the number of successful runs.
+	Runs int
+	// Reporters are the collections of the generated reports, in the order
+	// they were first generated.
+	Reporters []string
+	// Alerts are the distinct alerts that fired, in the order they first
+	// fired.
+	Alerts []Alert
+	// seen is the number of runs each alert fired in.
+	seen map[Alert]int
+}
+
+func (s *Summary) add(reports []report) {
+	s.Runs++
+	for _, r := range reports {
+		if !slices.Contains(s.Reporters, r.collection) {
+			s.Reporters = append(s.Reporters, r.collection)
+		}
+		// an alert firing more than once on a report counts once
+		fired := make(map[Alert]bool)
+		for _, a := range r.alerts {
+			alert := Alert{Reporter: r.collection, Severity: a.Severity, Message: a.Message}
+			if fired[alert] {
+				continue
+			}
+			fired[alert] = true
+			if s.seen[alert] == 0 {
+				s.Alerts = append(s.Alerts, alert)
+			}
+			s.seen[alert]++
+		}
+	}
+}
+
+// Severity returns the highest severity of the alerts that fired, or an
+// empty severity if none did.
+func (s Summary) Severity() conf.Severity {
+	var severity conf.Severity
+	for _, a := range s.Alerts {
+		if a.Severity.Rank() > severity.Rank() {
+			severity = a.Severity
+		}
+	}
+	return severity
+}
+
+// Print writes the summary as a table with a row per alert, ordered from
+// critical to informational, and a row per reporter on which none fired.
+func (s Summary) Print(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "REPORTER\tSEVERITY\tRUNS\tMESSAGE")
+	alerts := slices.Clone(s.Alerts)
+	slices.SortStableFunc(alerts, func(a, b Alert) int {
+		return b.Severity.Rank() - a.Severity.Rank()
+	})
+	for _, a := range alerts {
+		fmt.Fprintf(tw, "%s\t%s\t%d/%d\t%s\n", a.Reporter, a.Severity, s.seen[a], s.Runs, a.Message)
+	}
+	for _, r := range s.Reporters {
+		if slices.ContainsFunc(s.Alerts, func(a Alert) bool { return a.Reporter == r }) {
+			continue
+		}
+		fmt.Fprintf(tw, "%s\tok\t%d/%d\n", r, s.Runs, s.Runs)
+	}
+	err := tw.Flush()
+	if err != nil {
+		return fmt.Errorf("writing summary: %w", err)
+	}
+	return nil
+}
+
+type report struct {
+	collection string
+	alerts     []db.Alert
+}
+
+// collector is the output of a run, which keeps the alerts of the generated
+// reports.
+type collector struct {
+	mu      sync.Mutex
+	reports []report
+}
+
+func (c *collector) Write(collection string, _ time.Time, _ any, alerts []db.Alert) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.reports = append(c.reports, report{collection: collection, alerts: alerts})
+	return nil
+}
@@ -0,0 +1,156 @@
+package check_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/accuknox/rinc/internal/check"
+	"github.com/accuknox/rinc/internal/conf"
+	"github.com/accuknox/rinc/internal/db"
+	"github.com/accuknox/rinc/internal/job"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// runs returns a run function that generates the provided rounds of alerts
+// by collection, repeating the last round, and the number of calls. The
+// third call cancels the check, as if it timed out right after the run.
+func runs(cancel context.CancelFunc, rounds ...map[string][]db.Alert) (func(context.Context, job.Output) error, *int) {
+	calls := new(int)
+	return func(_ context.Context, out job.Output) error {
+		round := rounds[min(*calls, len(rounds)-1)]
+		*calls++
+		if *calls == 3 {
+			cancel()
+		}
+		if round == nil {
+			return errors.New("api server unavailable")
+		}
+		for _, collection := range []string{db.CollectionDass, db.CollectionPodStatus} {
+			alerts, ok := round[collection]
+			if !ok {
+				continue
+			}
+			err := out.Write(collection, time.Now(), nil, alerts)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	}, calls
+}
+
+func TestRun(t *testing.T) {
+	warning := db.Alert{Message: "api is unavailable", Severity: conf.SeverityWarning}
+	critical := db.Alert{Message: "ui is crashlooping", Severity: conf.SeverityCritical}
+	healthy := map[string][]db.Alert{
+		db.CollectionDass:      nil,
+		db.CollectionPodStatus: nil,
+	}
+	tests := []struct {
+		name      string
+		rounds    []map[string][]db.Alert
+		wantCalls int
+		want      conf.Severity
+		wantErr   bool
+		wantTable string
+	}{
+		{
+			name:      "healthy",
+			rounds:    []map[string][]db.Alert{healthy},
+			wantCalls: 3,
+			wantTable: `REPORTER   SEVERITY  RUNS  MESSAGE
+dass       ok        3/3
+podstatus  ok        3/3
+`,
+		},
+		{
+			name: "warning",
+			rounds: []map[string][]db.Alert{
+				{db.CollectionDass: {warning, warning}, db.CollectionPodStatus: nil},
+				healthy,
+			},
+			wantCalls: 3,
+			want:      conf.SeverityWarning,
+			wantTable: `REPORTER   SEVERITY  RUNS  MESSAGE
+dass       warning   1/3   api is unavailable
+podstatus  ok        3/3
+`,
+		},
+		{
+			name: "stops on critical",
+			rounds: []map[string][]db.Alert{
+				{db.CollectionDass: {warning}, db.CollectionPodStatus: nil},
+				{db.CollectionDass: {warning}, db.CollectionPodStatus: {critical}},
+			},
+			wantCalls: 2,
+			want:      conf.SeverityCritical,
+			wantTable: `REPORTER   SEVERITY  RUNS  MESSAGE
+podstatus  critical  1/2   ui is crashlooping
+dass       warning   2/2   api is unavailable
+`,
+		},
+		{
+			name:      "failed runs are skipped",
+			rounds:    []map[string][]db.Alert{nil, healthy},
+			wantCalls: 3,
+			wantTable: `REPORTER   SEVERITY  RUNS  MESSAGE
+dass       ok        2/2
+podstatus  ok        2/2
+`,
+		},
+		{
+			name:      "no successful run",
+			rounds:    []map[string][]db.Alert{nil},
+			wantCalls: 3,
+			wantErr:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := assert.New(t)
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			run, calls := runs(cancel, tt.rounds...)
+			c := conf.Check{
+				Reporters: []string{db.CollectionDass, db.CollectionPodStatus},
+				Timeout:   time.Hour,
+				Interval:  time.Minute,
+			}
+			// ticks right away, so that the runs are only limited by the
+			// cancellation on the third run
+			ticks := make(chan time.Time)
+			close(ticks)
+			summary, err := check.Run(ctx, c, ticks, run)
+			a.Equal(tt.wantCalls, *calls)
+			if tt.wantErr {
+				a.Error(err)
+				return
+			}
+			if !a.NoError(err) {
+				return
+			}
+			a.Equal(tt.want, summary.Severity())
+			var out bytes.Buffer
+			a.NoError(summary.Print(&out))
+			a.Equal(tt.wantTable, out.String())
+		})
+	}
+}
+
+func TestRunUnknownReporter(t *testing.T) {
+	a := assert.New(t)
+	run, calls := runs(func() {}, nil)
+	c := conf.Check{
+		Reporters: []string{"deployments"},
+		Timeout:   time.Second,
+		Interval:  time.Second,
+	}
+	_, err := check.Run(context.Background(), c, nil, run)
+	a.ErrorContains(err, `unknown reporter "deployments"`)
+	a.Zero(*calls)
+}
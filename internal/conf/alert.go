@@ -33,6 +33,21 @@ const (
 	SeverityCritical Severity = "critical" // critical level alert
 )
 
+// Rank orders severities from informational to critical, starting at 1. An
+// unknown or empty severity ranks 0.
+func (s Severity) Rank() int {
+	switch s {
+	case SeverityInfo:
+		return 1
+	case SeverityWarning:
+		return 2
+	case SeverityCritical:
+		return 3
+	default:
+		return 0
+	}
+}
+
 // Expr consists of an evaluable gval expression. It implements the
 // encoding.TextUnmarshaler interface.
 type Expr struct {
@@ -47,7 +62,7 @@ func (e *Expr) UnmarshalText(text []byte) error {
 		return nil
 	}
 	s := strings.TrimSpace(string(text))
-	ev, err := gval.Full(expr.Full()...).NewEvaluable(s)
+	ev, err := expr.Parse(s)
 	if err != nil {
 		return fmt.Errorf("invalid expression %q: %w", s, err)
 	}
@@ -76,7 +91,11 @@ func (e StringExpr) Evaluate(ctx context.Context, data any) (string, error) {
 	for _, e := range expressions {
 		e = strings.TrimPrefix(e, "`")
 		e = strings.TrimSuffix(e, "`")
-		res, err := gval.Full(expr.Full()...).EvaluateWithContext(ctx, e, data)
+		ev, err := expr.Parse(e)
+		if err != nil {
+			return "", fmt.Errorf("parsing expr %q: %w", e, err)
+		}
+		res, err := ev(ctx, data)
 		if err != nil {
 			return "", fmt.Errorf("evaluating expr %q: %w", e, err)
 		}
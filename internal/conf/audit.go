@@ -0,0 +1,64 @@
+package conf
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// unknownKeys returns the sorted keys of the nested configuration map m that
+// don't map to a field of C. Keys are matched case-insensitively, the same
+// way they are when the configuration is unmarshalled.
+func unknownKeys(m map[string]any) []string {
+	var unknown []string
+	auditKeys(reflect.TypeOf(C{}), m, "", &unknown)
+	sort.Strings(unknown)
+	return unknown
+}
+
+func auditKeys(typ reflect.Type, v any, path string, unknown *[]string) {
+	for typ.Kind() == reflect.Pointer {
+		typ = typ.Elem()
+	}
+	switch typ.Kind() {
+	case reflect.Struct:
+		m, ok := v.(map[string]any)
+		if !ok {
+			// e.g., types implementing encoding.TextUnmarshaler
+			return
+		}
+		for key, val := range m {
+			kpath := key
+			if path != "" {
+				kpath = path + "." + key
+			}
+			f, ok := fieldByKey(typ, key)
+			if !ok {
+				*unknown = append(*unknown, kpath)
+				continue
+			}
+			auditKeys(f.Type, val, kpath, unknown)
+		}
+	case reflect.Slice, reflect.Array:
+		items, ok := v.([]any)
+		if !ok {
+			return
+		}
+		for idx, item := range items {
+			auditKeys(typ.Elem(), item, fmt.Sprintf("%s[%d]", path, idx), unknown)
+		}
+	}
+}
+
+// fieldByKey returns the field of the struct type typ with the configuration
+// key key.
+func fieldByKey(typ reflect.Type, key string) (reflect.StructField, bool) {
+	for idx := 0; idx < typ.NumField(); idx++ {
+		f := typ.Field(idx)
+		if fkey, ok := fieldKey(f); ok && strings.EqualFold(fkey, key) {
+			return f, true
+		}
+	}
+	return reflect.StructField{}, false
+}
@@ -0,0 +1,61 @@
+package conf
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUnknownKeys(t *testing.T) {
+	a := assert.New(t)
+	m := map[string]any{
+		"log": map[string]any{"level": "info", "formt": "json"},
+		"ceph": map[string]any{
+			"enable":       true,
+			"dashboardAPI": map[string]any{"url": "x", "passwd": "y"},
+			"alerts": []any{
+				map[string]any{"message": "x", "when": "true", "severity": "info"},
+				map[string]any{"message": "x", "when": "true", "severty": "info"},
+			},
+		},
+		"rabbitmq": map[string]any{
+			"management": map[string]any{"url": "x", "username": "u"},
+		},
+		"longRunningJobs": map[string]any{"olderthan": "1h"},
+		"runAsScraper":    true,
+		"foo":             "bar",
+	}
+	a.Equal([]string{
+		"ceph.alerts[1].severty",
+		"ceph.dashboardAPI.passwd",
+		"foo",
+		"log.formt",
+		"runAsScraper",
+	}, unknownKeys(m))
+}
+
+func TestNewStrict(t *testing.T) {
+	a := assert.New(t)
+	config := filepath.Join(t.TempDir(), "config.yaml")
+	err := os.WriteFile(config, []byte(`
+mongodb:
+  uri: mongodb://localhost:27017
+  pasword: p
+rabbitmq:
+  management:
+    url: http://rabbitmq:15672
+`), 0o600)
+	if !a.NoError(err) {
+		return
+	}
+
+	c, err := New("--conf", config)
+	if a.NoError(err) {
+		a.Equal("http://rabbitmq:15672", c.RabbitMQ.Management.URL)
+	}
+
+	_, err = New("--conf", config, "--strict")
+	a.ErrorContains(err, "`mongodb.pasword`")
+}
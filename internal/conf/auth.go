@@ -0,0 +1,89 @@
+package conf
+
+import "time"
+
+// Auth contains configuration related to authenticating the users of the
+// web server. Authenticated users are remembered in signed session cookies.
+type Auth struct {
+	// Method is the authentication method. Users aren't authenticated if it
+	// is empty.
+	// Possible values: "", "basic", "oidc".
+	Method string `koanf:"method"`
+	// Basic contains the configuration of the basic auth method.
+	Basic BasicAuth `koanf:"basic"`
+	// OIDC contains the configuration of the OpenID Connect login method.
+	OIDC OIDC `koanf:"oidc"`
+	// Session contains configuration related to the session cookies.
+	Session Session `koanf:"session"`
+}
+
+const (
+	AuthBasic = "basic"
+	AuthOIDC  = "oidc"
+)
+
+// BasicAuth contains the configuration of the basic auth method.
+type BasicAuth struct {
+	// Users are the users allowed to log in.
+	Users []BasicAuthUser `koanf:"users"`
+}
+
+// BasicAuthUser is a user allowed to log in with basic auth.
+type BasicAuthUser struct {
+	Username string `koanf:"username"`
+	// PasswordHash is the bcrypt hash of the password of the user, e.g.,
+	// generated with `htpasswd -nbB <username> <password>`.
+	PasswordHash string `koanf:"passwordHash"`
+}
+
+// OIDC contains the configuration of the OpenID Connect login method, using
+// the authorization code flow.
+type OIDC struct {
+	// IssuerURL is the URL of the OpenID provider, which must serve its
+	// configuration at /.well-known/openid-configuration.
+	//
+	// E.g., https://keycloak.example.com/realms/accuknox
+	IssuerURL string `koanf:"issuerUrl"`
+	// ClientID is the ID of the client registered with the provider.
+	ClientID string `koanf:"clientId"`
+	// ClientSecret is the secret of the client registered with the
+	// provider.
+	ClientSecret string `koanf:"clientSecret"`
+	// ClientSecretFile is the path to a file containing the client secret.
+	// It takes precedence over ClientSecret.
+	ClientSecretFile string `koanf:"clientSecretFile"`
+	// RedirectURL is the URL of the login callback of the web server, which
+	// must be registered with the provider.
+	//
+	// E.g., https://rinc.example.com/auth/callback
+	RedirectURL string `koanf:"redirectUrl"`
+	// Scopes are the scopes requested besides "openid".
+	//
+	// Default: ["profile", "email", "groups"]
+	Scopes []string `koanf:"scopes"`
+	// GroupsClaim is the claim of the ID token containing the groups of the
+	// user.
+	//
+	// Default: "groups"
+	GroupsClaim string `koanf:"groupsClaim"`
+	// AllowedGroups are the groups whose members are allowed to log in. All
+	// users authenticated by the provider are allowed if it is empty.
+	AllowedGroups []string `koanf:"allowedGroups"`
+}
+
+// Session contains configuration related to the session cookies.
+type Session struct {
+	// Secret is the key the session cookies are signed with. It must be at
+	// least 32 bytes long, and shared by all replicas of the web server.
+	Secret string `koanf:"secret"`
+	// SecretFile is the path to a file containing the secret. It takes
+	// precedence over Secret.
+	SecretFile string `koanf:"secretFile"`
+	// MaxAge is the duration after which users must log in again.
+	//
+	// Default: 12h
+	MaxAge time.Duration `koanf:"maxAge"`
+	// Insecure allows the session cookies to be sent over plain HTTP, e.g.,
+	// when the web server isn't served over HTTPS.
+	Insecure bool `koanf:"insecure"`
+}
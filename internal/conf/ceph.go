@@ -7,7 +7,7 @@ type Ceph struct {
 	// DashboardAPI contains configuration to access the ceph dashboard api.
 	//
 	// Required.
-	DashboardAPI CephDashboardAPI `kaonf:"dashboardAPI"`
+	DashboardAPI CephDashboardAPI `koanf:"dashboardAPI"`
 	// Alerts contain a message template, a severity level, and a conditional
 	// expression to trigger the respective alert.
 	Alerts []Alert `koanf:"alerts"`
@@ -26,8 +26,14 @@ type CephDashboardAPI struct {
 	//
 	// Required.
 	Username string `koanf:"username"`
+	// UsernameFile is the path to a file containing the username. It takes
+	// precedence over Username.
+	UsernameFile string `koanf:"usernameFile"`
 	// Password to authenticate with ceph dashboard API.
 	//
 	// Required.
 	Password string `koanf:"password"`
+	// PasswordFile is the path to a file containing the password. It takes
+	// precedence over Password.
+	PasswordFile string `koanf:"passwordFile"`
 }
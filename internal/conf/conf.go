@@ -1,8 +1,11 @@
 package conf
 
 import (
+	"context"
 	"fmt"
+	"log/slog"
 	"os"
+	"strings"
 	"time"
 
 	"github.com/knadh/koanf/parsers/yaml"
@@ -16,19 +19,39 @@ var defaultConfig = "/etc/rinc/config.yaml"
 
 // C contains all configuration data that can be passed to the reporter.
 type C struct {
-	RunAsScraper   bool
-	RunAsWebServer bool
-	GenerateSchema string
+	RunAsScraper   bool `koanf:"-"`
+	RunAsWebServer bool `koanf:"-"`
+	// REPL contains the options of the `expr` subcommand.
+	REPL REPL `koanf:"-"`
+	// Snapshot contains the options to record or replay a snapshot.
+	Snapshot Snapshot `koanf:"-"`
+	// Check contains the options of the `check` subcommand.
+	Check Check `koanf:"-"`
+	// Output contains the options to write the scraped reports to files or
+	// stdout instead of MongoDB.
+	Output Output `koanf:"-"`
+	// ConfFiles are the configuration files the configuration was loaded
+	// from.
+	ConfFiles []string `koanf:"-"`
 	// Log contains configuration for logs.
 	Log Log `koanf:"log"`
 	// TerminationGracePeriod is the period after which the web server
 	// must be forcefully terminated. A value of 0 implies no forceful
 	// termination.
 	TerminationGracePeriod time.Duration `koanf:"terminationGracePeriod"`
+	// Readiness contains configuration related to the readiness endpoint of
+	// the web server.
+	Readiness Readiness `koanf:"readiness"`
+	// Auth contains configuration related to authenticating the users of
+	// the web server.
+	Auth Auth `koanf:"auth"`
 	// KubernetesClient contains the configuration needed to communicate with
 	// the Kubernetes API server.
 	KubernetesClient KubernetesClient `koanf:"kubernetesClient"`
-	Mongodb          Mongodb          `koanf:"mongodb"`
+	// Vault contains the configuration needed to resolve configuration
+	// values referencing secrets stored in vault.
+	Vault   Vault   `koanf:"vault"`
+	Mongodb Mongodb `koanf:"mongodb"`
 	// RabbitMQ contains the rabbitmq configuration.
 	RabbitMQ RabbitMQ `koanf:"rabbitmq"`
 	// LongJobs contains configuration related to the long-running job
@@ -53,19 +76,110 @@ type C struct {
 	Connectivity Connectivity `koanf:"connectivity"`
 	// PodStatus contains configuration related to the pod status reporter.
 	PodStatus PodStatus `koanf:"podStatus"`
+	// Telemetry contains configuration related to the instrumentation of
+	// RINC itself.
+	Telemetry Telemetry `koanf:"telemetry"`
+}
+
+// Readiness contains configuration related to the readiness endpoint of the
+// web server, which fails if MongoDB is unreachable or the latest scrape is
+// stale.
+type Readiness struct {
+	// MaxScrapeAge is the age after which the latest scrape is stale, e.g.,
+	// because the scraper CronJob is broken. A value of 0 disables the
+	// check, which otherwise also fails until the first scrape.
+	MaxScrapeAge time.Duration `koanf:"maxScrapeAge"`
+}
+
+// Snapshot contains the options to record the inputs of the reporters into a
+// bundle, or to replay a bundle through the reporters. Both scrape.
+type Snapshot struct {
+	// Record is the path of the bundle to record the inputs of the reporters
+	// into.
+	Record string
+	// Replay is the path of the bundle to replay through the reporters
+	// instead of fetching their inputs from the live services.
+	Replay string
+}
+
+// Output contains the options to write the scraped reports, i.e., the
+// metrics document of every reporter and the firing alerts, to files or
+// stdout instead of MongoDB.
+type Output struct {
+	// Format is the format of the written reports, either "json" or "yaml".
+	// Reports are stored in MongoDB if it is empty.
+	Format string
+	// Dir is the directory to write a file per report into. The reports are
+	// written to stdout if it is empty.
+	Dir string
+}
+
+const (
+	OutputJSON = "json"
+	OutputYAML = "yaml"
+)
+
+// Scrapes reports whether the reports are generated, i.e., whether the
+// scraper runs or a snapshot is recorded or replayed.
+func (c C) Scrapes() bool {
+	return c.RunAsScraper || c.Snapshot.Record != "" || c.Snapshot.Replay != ""
+}
+
+// Check contains the options of the health gate started with the `check`
+// subcommand, which generates the selected reports repeatedly and exits with
+// a code reflecting the worst severity of the alerts that fired.
+type Check struct {
+	// Enable is set when the `check` subcommand is used.
+	Enable bool
+	// Reporters are the collections of the reports to generate, e.g., dass
+	// and podstatus. All enabled reporters are run if it is empty.
+	Reporters []string
+	// Timeout is the duration to check for.
+	Timeout time.Duration
+	// Interval is the duration between the starts of two runs.
+	Interval time.Duration
+}
+
+// REPL contains the options of the interactive expression prompt started
+// with the `expr` subcommand.
+type REPL struct {
+	// Enable is set when the `expr` subcommand is used.
+	Enable bool
+	// Collection is the collection of the stored report to load, e.g., ceph.
+	Collection string
+	// At is the isosec timestamp of the stored report to load. The latest
+	// report is loaded if it is empty.
+	At string
+	// File is the path to a JSON file to load the report from instead of
+	// MongoDB.
+	File string
+}
+
+// Defaults returns the default configuration values by key.
+func Defaults() map[string]any {
+	return map[string]any{
+		"log.level":                                     "info",
+		"log.format":                                    "text",
+		"terminationGracePeriod":                        time.Second * 10,
+		"longRunningJobs.olderThan":                     time.Hour * 12,
+		"connectivity.postgres.port":                    5432,
+		"vault.auth.method":                             "kubernetes",
+		"vault.auth.kubernetes.mountPath":               "kubernetes",
+		"vault.auth.kubernetes.serviceAccountTokenFile": "/var/run/secrets/kubernetes.io/serviceaccount/token",
+		"telemetry.pushgateway.job":                     "rinc",
+		"telemetry.tracing.sampleRatio":                 1.0,
+		"telemetry.tracing.serviceName":                 "rinc",
+		"auth.oidc.scopes":                              []string{"profile", "email", "groups"},
+		"auth.oidc.groupsClaim":                         "groups",
+		"auth.session.maxAge":                           time.Hour * 12,
+	}
 }
 
 // New creates a configuration using the provided arguments and config file.
 func New(args ...string) (*C, error) {
 	k := koanf.New(".")
 
-	err := k.Load(confmap.Provider(map[string]any{
-		"log.level":                  "info",
-		"log.format":                 "text",
-		"terminationGracePeriod":     time.Second * 10,
-		"longRunningJobs.olderThan":  time.Hour * 12,
-		"connectivity.postgres.port": 5432,
-	}, "."), nil)
+	err := k.Load(confmap.Provider(Defaults(), "."), nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to load default configuration: %w", err)
 	}
@@ -86,16 +200,72 @@ func New(args ...string) (*C, error) {
 		return nil, fmt.Errorf("failed to parse flags: %w", err)
 	}
 
-	generateSchema, err := f.GetString("generate-schema")
+	repl, err := parseREPLFlags(f)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse flags: %w", err)
+	}
+
+	check, err := parseCheckFlags(f)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse flags: %w", err)
+	}
+
+	record, err := f.GetString("record")
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse flags: %w", err)
+	}
+
+	replay, err := f.GetString("replay")
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse flags: %w", err)
 	}
 
+	output, err := f.GetString("output")
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse flags: %w", err)
+	}
+
+	outDir, err := f.GetString("out-dir")
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse flags: %w", err)
+	}
+
+	strict, err := f.GetBool("strict")
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse flags: %w", err)
+	}
+
+	var unknown []string
 	for _, c := range confF {
-		err := k.Load(file.Provider(c), yaml.Parser())
+		fk := koanf.New(".")
+		err := fk.Load(file.Provider(c), yaml.Parser())
 		if err != nil {
 			return nil, fmt.Errorf("failed to load config %q: %w", c, err)
 		}
+		for _, key := range unknownKeys(fk.Raw()) {
+			if !strict {
+				slog.LogAttrs(
+					context.TODO(),
+					slog.LevelWarn,
+					"unknown configuration key",
+					slog.String("key", key),
+					slog.String("file", c),
+				)
+			}
+			unknown = append(unknown, fmt.Sprintf("`%s` in %q", key, c))
+		}
+		err = k.Merge(fk)
+		if err != nil {
+			return nil, fmt.Errorf("failed to merge config %q: %w", c, err)
+		}
+	}
+	if strict && len(unknown) != 0 {
+		return nil, fmt.Errorf("unknown configuration keys: %s", strings.Join(unknown, ", "))
+	}
+
+	err = k.Load(envProvider(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load environment variables: %w", err)
 	}
 
 	conf := new(C)
@@ -104,13 +274,42 @@ func New(args ...string) (*C, error) {
 		return nil, fmt.Errorf("failed to unmarshal configuration: %w", err)
 	}
 
+	err = conf.readSecretFiles()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read secret files: %w", err)
+	}
+
+	err = conf.resolveVaultRefs(context.TODO())
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve vault references: %w", err)
+	}
+
+	conf.migrateNamespaces()
+
 	conf.RunAsScraper = asScraper
 	conf.RunAsWebServer = asWebServer
-	conf.GenerateSchema = generateSchema
+	conf.REPL = repl
+	conf.Check = check
+	conf.Snapshot = Snapshot{Record: record, Replay: replay}
+	conf.Output = Output{Format: output, Dir: outDir}
+	conf.ConfFiles = confF
 
 	return conf, nil
 }
 
+// ParseGenerateSchema returns the name of the schema to generate with the
+// --generate-schema flag in args, if any. Unlike New, it doesn't load any
+// config, secret files or vault references, since the schema doesn't depend
+// on them and they may well be missing, e.g., when generating the schema of a
+// config that doesn't exist yet.
+func ParseGenerateSchema(args ...string) (string, error) {
+	name, err := parseFlags(args).GetString("generate-schema")
+	if err != nil {
+		return "", fmt.Errorf("failed to parse flags: %w", err)
+	}
+	return name, nil
+}
+
 func parseFlags(args []string) *flag.FlagSet {
 	f := flag.NewFlagSet("config", flag.ContinueOnError)
 	f.Usage = func() {
@@ -121,6 +320,59 @@ func parseFlags(args []string) *flag.FlagSet {
 	f.String("generate-schema", "", "generate json schema")
 	f.Bool("scrape", false, "scrape & store metrics")
 	f.Bool("serve", false, "serve static reports")
+	f.String("record", "", "scrape & store metrics, and record the fetched inputs into a bundle, e.g., bundle.tar.gz")
+	f.String("replay", "", "scrape & store metrics from a bundle recorded with --record instead of live services")
+	f.String("output", "", "write the scraped reports as json or yaml to stdout, or to --out-dir, instead of storing them")
+	f.String("out-dir", "", "directory to write a file per report into with --output")
+	f.Bool("strict", false, "fail on unknown configuration keys instead of warning")
+	f.String("collection", "", "collection of the report to load with the expr command")
+	f.String("at", "", "isosec timestamp of the report to load with the expr command (default latest)")
+	f.String("file", "", "JSON file to load the report from with the expr command")
+	f.StringSlice("reporters", nil, "comma-separated collections of the reports to generate with the check command, e.g., dass,podstatus (default all enabled)")
+	f.Duration("timeout", 5*time.Minute, "duration to check for with the check command")
+	f.Duration("interval", 30*time.Second, "duration between the runs of the check command")
 	f.Parse(args)
 	return f
 }
+
+func parseCheckFlags(f *flag.FlagSet) (Check, error) {
+	var (
+		c   Check
+		err error
+	)
+	c.Enable = f.Arg(0) == "check"
+	c.Reporters, err = f.GetStringSlice("reporters")
+	if err != nil {
+		return c, err
+	}
+	c.Timeout, err = f.GetDuration("timeout")
+	if err != nil {
+		return c, err
+	}
+	c.Interval, err = f.GetDuration("interval")
+	if err != nil {
+		return c, err
+	}
+	return c, nil
+}
+
+func parseREPLFlags(f *flag.FlagSet) (REPL, error) {
+	var (
+		r   REPL
+		err error
+	)
+	r.Enable = f.Arg(0) == "expr"
+	r.Collection, err = f.GetString("collection")
+	if err != nil {
+		return r, err
+	}
+	r.At, err = f.GetString("at")
+	if err != nil {
+		return r, err
+	}
+	r.File, err = f.GetString("file")
+	if err != nil {
+		return r, err
+	}
+	return r, nil
+}
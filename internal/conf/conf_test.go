@@ -0,0 +1,25 @@
+package conf
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseGenerateSchema(t *testing.T) {
+	a := assert.New(t)
+	missing := filepath.Join(t.TempDir(), "config.yaml")
+
+	// the config isn't loaded, so it may be missing
+	name, err := ParseGenerateSchema("--generate-schema", "config", "--conf", missing)
+	a.NoError(err)
+	a.Equal("config", name)
+
+	name, err = ParseGenerateSchema("--conf", missing)
+	a.NoError(err)
+	a.Empty(name)
+
+	_, err = New("--generate-schema", "config", "--conf", missing)
+	a.Error(err)
+}
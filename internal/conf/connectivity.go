@@ -53,8 +53,14 @@ type Neo4jCheck struct {
 	URI string `koanf:"uri"`
 	// Username is the neo4j basic auth username.
 	Username string `koanf:"username"`
+	// UsernameFile is the path to a file containing the username. It takes
+	// precedence over Username.
+	UsernameFile string `koanf:"usernameFile"`
 	// Password is the neo4j basic auth password.
 	Password string `koanf:"password"`
+	// PasswordFile is the path to a file containing the password. It takes
+	// precedence over Password.
+	PasswordFile string `koanf:"passwordFile"`
 }
 
 // Postgres contains all configuration related to postgres connectivity check.
@@ -71,8 +77,14 @@ type PostgresCheck struct {
 	Port uint16 `koanf:"port"`
 	// Username is the postgres auth username.
 	Username string `koanf:"username"`
+	// UsernameFile is the path to a file containing the username. It takes
+	// precedence over Username.
+	UsernameFile string `koanf:"usernameFile"`
 	// Password is the postgres auth password.
 	Password string `koanf:"password"`
+	// PasswordFile is the path to a file containing the password. It takes
+	// precedence over Password.
+	PasswordFile string `koanf:"passwordFile"`
 }
 
 // Redis contains all configuration related to redis connectivity check. Also
@@ -6,9 +6,18 @@ type DaSS struct {
 	// Enable specifies whether the deployment and statefulset status (DaSS)
 	// reporter is enabled.
 	Enable bool `koanf:"enable"`
-	// Namespace is the Kubernetes namespace that the DaSS reporter will be
-	// limited to. Leave blank for all namespaces.
+	// Namespace is the single Kubernetes namespace that the DaSS reporter
+	// will be limited to.
+	//
+	// Deprecated: use Namespaces.
 	Namespace string `koanf:"namespace"`
+	// Namespaces selects the Kubernetes namespaces that the DaSS reporter
+	// will be limited to. Leave blank for all namespaces.
+	Namespaces NamespaceSelector `koanf:"namespaces"`
+	// LabelSelector limits the reporter to the deployments and statefulsets
+	// matching the label selector, e.g., "app.kubernetes.io/part-of=accuknox".
+	// Leave blank for all deployments and statefulsets.
+	LabelSelector string `koanf:"labelSelector"`
 	// Alerts contain a message template, a severity level, and a conditional
 	// expression to trigger the respective alert.
 	Alerts []Alert `koanf:"alerts"`
@@ -0,0 +1,85 @@
+package conf
+
+import (
+	"reflect"
+	"strings"
+
+	"github.com/knadh/koanf/providers/env"
+)
+
+// EnvPrefix is the prefix of the environment variables that override
+// configuration values. Nested keys are separated by a double underscore,
+// e.g., RINC_MONGODB__PASSWORD sets `mongodb.password`.
+const EnvPrefix = "RINC_"
+
+// envDelim separates nested keys in environment variable names.
+const envDelim = "__"
+
+// envProvider returns a koanf provider that reads configuration values from
+// the environment variables prefixed with EnvPrefix.
+func envProvider() *env.Env {
+	return env.Provider(EnvPrefix, ".", envKey)
+}
+
+// envKey converts the name of an environment variable into a configuration
+// key. Since environment variables are conventionally upper case, each
+// segment of the name is matched case-insensitively, ignoring underscores,
+// against the keys of C, e.g., RINC_CEPH__DASHBOARD_API__PASSWORD becomes
+// `ceph.dashboardAPI.password`. Segments that don't match a known key are
+// lower cased.
+func envKey(name string) string {
+	segments := strings.Split(strings.TrimPrefix(name, EnvPrefix), envDelim)
+	typ := reflect.TypeOf(C{})
+	for idx, seg := range segments {
+		if typ == nil || typ.Kind() != reflect.Struct {
+			segments[idx] = strings.ToLower(seg)
+			typ = nil
+			continue
+		}
+		key, ftyp, ok := keyByName(typ, seg)
+		if !ok {
+			segments[idx] = strings.ToLower(seg)
+			typ = nil
+			continue
+		}
+		segments[idx] = key
+		typ = ftyp
+	}
+	return strings.Join(segments, ".")
+}
+
+// keyByName returns the configuration key and type of the field of the
+// struct type typ whose key matches name.
+func keyByName(typ reflect.Type, name string) (string, reflect.Type, bool) {
+	want := normalizeKey(name)
+	for idx := 0; idx < typ.NumField(); idx++ {
+		f := typ.Field(idx)
+		key, ok := fieldKey(f)
+		if !ok || normalizeKey(key) != want {
+			continue
+		}
+		ftyp := f.Type
+		for ftyp.Kind() == reflect.Pointer {
+			ftyp = ftyp.Elem()
+		}
+		return key, ftyp, true
+	}
+	return "", nil, false
+}
+
+// fieldKey returns the configuration key of a struct field. Fields without a
+// koanf tag, e.g., the ones set from flags, have no configuration key.
+func fieldKey(f reflect.StructField) (string, bool) {
+	if !f.IsExported() {
+		return "", false
+	}
+	key, ok := f.Tag.Lookup("koanf")
+	if !ok || key == "" || key == "-" {
+		return "", false
+	}
+	return key, true
+}
+
+func normalizeKey(key string) string {
+	return strings.ToLower(strings.ReplaceAll(key, "_", ""))
+}
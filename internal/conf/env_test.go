@@ -0,0 +1,27 @@
+package conf
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEnvKey(t *testing.T) {
+	a := assert.New(t)
+	inputs := map[string]string{
+		"RINC_MONGODB__PASSWORD":                      "mongodb.password",
+		"RINC_MONGODB__PASSWORD_FILE":                 "mongodb.passwordFile",
+		"RINC_CEPH__DASHBOARD_API__PASSWORD":          "ceph.dashboardAPI.password",
+		"RINC_CEPH__DASHBOARDAPI__URL":                "ceph.dashboardAPI.url",
+		"RINC_RABBITMQ__MANAGEMENT__PASSWORD":         "rabbitmq.management.password",
+		"RINC_CONNECTIVITY__NEO4J__PASSWORD":          "connectivity.neo4j.password",
+		"RINC_LONG_RUNNING_JOBS__OLDER_THAN":          "longRunningJobs.olderThan",
+		"RINC_DEPLOYMENTANDSTATEFULSETSTATUS__ENABLE": "deploymentAndStatefulsetStatus.enable",
+		"RINC_LOG__LEVEL":                             "log.level",
+		"RINC_FOO__BAR":                               "foo.bar",
+		"RINC_CONNECTIVITY__ALERTS__FOO":              "connectivity.alerts.foo",
+	}
+	for input, want := range inputs {
+		a.Equal(want, envKey(input), "INPUT=%s", input)
+	}
+}
@@ -4,9 +4,18 @@ package conf
 type ImageTag struct {
 	// Enable specifies whether the image tag reporter is enabled.
 	Enable bool `koanf:"enable"`
-	// Namespace is the Kubernetes namespace that the image tag reporter
+	// Namespace is the single Kubernetes namespace that the image tag reporter
 	// will be limited to.
+	//
+	// Deprecated: use Namespaces.
 	Namespace string `koanf:"namespace"`
+	// Namespaces selects the Kubernetes namespaces that the image tag reporter
+	// will be limited to. Leave blank for all namespaces.
+	Namespaces NamespaceSelector `koanf:"namespaces"`
+	// LabelSelector limits the reporter to the deployments and statefulsets
+	// matching the label selector, e.g., "app.kubernetes.io/part-of=accuknox".
+	// Leave blank for all deployments and statefulsets.
+	LabelSelector string `koanf:"labelSelector"`
 	// Alerts contain a message template, a severity level, and a conditional
 	// expression to trigger the respective alert.
 	Alerts []Alert `koanf:"alerts"`
@@ -8,9 +8,18 @@ type LongJobs struct {
 	// Enable specifies whether the long-running job reporter should be
 	// enabled.
 	Enable bool `koanf:"enable"`
-	// Namespace is the namespace in which the long-running jobs will be
-	// reported.
+	// Namespace is the single Kubernetes namespace that the long-running job
+	// reporter will be limited to.
+	//
+	// Deprecated: use Namespaces.
 	Namespace string `koanf:"namespace"`
+	// Namespaces selects the Kubernetes namespaces that the long-running job
+	// reporter will be limited to. Leave blank for all namespaces.
+	Namespaces NamespaceSelector `koanf:"namespaces"`
+	// LabelSelector limits the reporter to the jobs matching the label
+	// selector, e.g., "app.kubernetes.io/part-of=accuknox". Leave blank for
+	// all jobs.
+	LabelSelector string `koanf:"labelSelector"`
 	// OlderThan defines the duration threshold; jobs older than this
 	// value will be reported.
 	OlderThan time.Duration `koanf:"olderThan"`
@@ -3,5 +3,11 @@ package conf
 type Mongodb struct {
 	URI      string `koanf:"uri"`
 	Username string `koanf:"username"`
-	Password string `koanf:"password"`
+	// UsernameFile is the path to a file containing the username. It takes
+	// precedence over Username.
+	UsernameFile string `koanf:"usernameFile"`
+	Password     string `koanf:"password"`
+	// PasswordFile is the path to a file containing the password. It takes
+	// precedence over Password.
+	PasswordFile string `koanf:"passwordFile"`
 }
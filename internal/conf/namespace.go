@@ -0,0 +1,53 @@
+package conf
+
+// NamespaceSelector selects the Kubernetes namespaces a reporter is limited
+// to. An empty selector selects all namespaces.
+type NamespaceSelector struct {
+	// Include lists the namespaces to include. Entries can be glob patterns,
+	// e.g., "accuknox-*". Leave blank to include all namespaces.
+	Include []string `koanf:"include"`
+	// Exclude lists the namespaces to exclude. Entries can be glob patterns.
+	// Exclusions take precedence over inclusions.
+	Exclude []string `koanf:"exclude"`
+	// LabelSelector limits the namespaces to the ones matching the label
+	// selector, e.g., "team=platform".
+	LabelSelector string `koanf:"labelSelector"`
+}
+
+// IsEmpty returns true if the selector selects all namespaces.
+func (s NamespaceSelector) IsEmpty() bool {
+	return len(s.Include) == 0 && len(s.Exclude) == 0 && s.LabelSelector == ""
+}
+
+// namespaced is the namespace configuration of a Kubernetes reporter.
+type namespaced struct {
+	// key is the configuration key of the reporter.
+	key string
+	// namespace is the deprecated single namespace.
+	namespace *string
+	// selector is the namespace selector.
+	selector *NamespaceSelector
+}
+
+// namespaced returns the namespace configuration of each Kubernetes
+// reporter.
+func (c *C) namespaced() []namespaced {
+	return []namespaced{
+		{"longRunningJobs", &c.LongJobs.Namespace, &c.LongJobs.Namespaces},
+		{"imageTag", &c.ImageTag.Namespace, &c.ImageTag.Namespaces},
+		{"deploymentAndStatefulsetStatus", &c.DaSS.Namespace, &c.DaSS.Namespaces},
+		{"resourceUtilization", &c.ResourceUtilization.Namespace, &c.ResourceUtilization.Namespaces},
+		{"podStatus", &c.PodStatus.Namespace, &c.PodStatus.Namespaces},
+	}
+}
+
+// migrateNamespaces moves the deprecated `namespace` of each Kubernetes
+// reporter into its namespace selector. Setting both is reported by Validate.
+func (c *C) migrateNamespaces() {
+	for _, n := range c.namespaced() {
+		if *n.namespace != "" && n.selector.IsEmpty() {
+			n.selector.Include = []string{*n.namespace}
+			*n.namespace = ""
+		}
+	}
+}
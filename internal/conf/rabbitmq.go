@@ -7,7 +7,7 @@ type RabbitMQ struct {
 	// Management contains configuration to access the rabbitmq management api.
 	//
 	// Required.
-	Management RabbitMQManagement `kaonf:"management"`
+	Management RabbitMQManagement `koanf:"management"`
 	// HeadlessSvcAddr is the Kubernetes headless address pointing to
 	// rabbitmq nodes. On a DNS lookup, this address must resolve to
 	// rabbitmq node ips.
@@ -32,8 +32,14 @@ type RabbitMQManagement struct {
 	//
 	// Required.
 	Username string `koanf:"username"`
+	// UsernameFile is the path to a file containing the username. It takes
+	// precedence over Username.
+	UsernameFile string `koanf:"usernameFile"`
 	// Password is the basic auth password credential for the management api.
 	//
 	// Required.
 	Password string `koanf:"password"`
+	// PasswordFile is the path to a file containing the password. It takes
+	// precedence over Password.
+	PasswordFile string `koanf:"passwordFile"`
 }
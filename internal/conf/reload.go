@@ -0,0 +1,209 @@
+package conf
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// reloadDelay is the time to wait for further changes to the configuration
+// files before reloading them. Kubernetes updates mounted ConfigMaps and
+// Secrets with a burst of file system events.
+const reloadDelay = time.Second
+
+// ReloadStatus describes the configuration reloads of a Reloader.
+type ReloadStatus struct {
+	// Reloads is the number of successful reloads.
+	Reloads uint64 `json:"reloads"`
+	// Failures is the number of failed reloads.
+	Failures uint64 `json:"failures"`
+	// LastReload is the time of the last successful reload.
+	LastReload *time.Time `json:"lastReload,omitempty"`
+	// LastError is the error of the last failed reload. It is cleared on the
+	// next successful reload.
+	LastError string `json:"lastError,omitempty"`
+	// LastErrorTime is the time of the last failed reload.
+	LastErrorTime *time.Time `json:"lastErrorTime,omitempty"`
+}
+
+// Reloader keeps a configuration up to date with the configuration files it
+// was loaded from. The configuration is only replaced if the new one is
+// valid.
+type Reloader struct {
+	args    []string
+	current atomic.Pointer[C]
+	// reloading serializes the reloads, so that a configuration never
+	// replaces a newer one. It is held while loading the configuration,
+	// which may take a while, e.g., to read secrets from Vault.
+	reloading sync.Mutex
+
+	mu        sync.Mutex
+	status    ReloadStatus
+	listeners []func(*C)
+}
+
+// NewReloader creates a Reloader for c, which must have been created by New
+// with the provided arguments.
+func NewReloader(c *C, args ...string) *Reloader {
+	r := &Reloader{args: args}
+	r.current.Store(c)
+	return r
+}
+
+// Current returns the current configuration. It must not be modified.
+func (r *Reloader) Current() *C {
+	return r.current.Load()
+}
+
+// Status returns the reload status.
+func (r *Reloader) Status() ReloadStatus {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.status
+}
+
+// OnReload registers fn to be called with the new configuration after each
+// successful reload.
+func (r *Reloader) OnReload(fn func(*C)) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.listeners = append(r.listeners, fn)
+}
+
+// Reload loads and validates the configuration files, and replaces the
+// current configuration with the result. The current configuration is kept
+// if the new one can't be loaded or is invalid.
+func (r *Reloader) Reload() error {
+	r.reloading.Lock()
+	defer r.reloading.Unlock()
+
+	c, err := New(r.args...)
+	if err == nil {
+		err = c.Validate()
+	}
+	now := time.Now().UTC()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err != nil {
+		r.status.Failures++
+		r.status.LastError = err.Error()
+		r.status.LastErrorTime = &now
+		return err
+	}
+
+	r.current.Store(c)
+	r.status.Reloads++
+	r.status.LastReload = &now
+	r.status.LastError = ""
+	r.status.LastErrorTime = nil
+	for _, fn := range r.listeners {
+		fn(c)
+	}
+	return nil
+}
+
+// files returns the paths of the files c is loaded from, i.e., the
+// configuration files and the secret files.
+func (c *C) files() []string {
+	files := append([]string(nil), c.ConfFiles...)
+	for _, s := range c.secretFiles() {
+		if s.path != "" {
+			files = append(files, s.path)
+		}
+	}
+	return files
+}
+
+// Watch reloads the configuration whenever one of its files changes, until
+// ctx is done. The files are the configuration files and the secret files,
+// e.g., `mongodb.passwordFile`; those of a reloaded configuration are watched
+// too.
+func (r *Reloader) Watch(ctx context.Context) error {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("creating file watcher: %w", err)
+	}
+	defer watcher.Close()
+
+	// The directories are watched instead of the files themselves, since
+	// files are usually replaced rather than written to, e.g., Kubernetes
+	// swaps the `..data` symlink of ConfigMap and Secret volumes.
+	files := make(map[string]bool)
+	watch := func(c *C) error {
+		for _, f := range c.files() {
+			path, err := filepath.Abs(f)
+			if err != nil {
+				return fmt.Errorf("resolving %q: %w", f, err)
+			}
+			if files[path] {
+				continue
+			}
+			files[path] = true
+			err = watcher.Add(filepath.Dir(path))
+			if err != nil {
+				return fmt.Errorf("watching %q: %w", filepath.Dir(path), err)
+			}
+		}
+		return nil
+	}
+	err = watch(r.Current())
+	if err != nil {
+		return err
+	}
+
+	timer := time.NewTimer(reloadDelay)
+	timer.Stop()
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case ev, ok := <-watcher.Events:
+			if !ok {
+				return nil
+			}
+			if files[ev.Name] || filepath.Base(ev.Name) == "..data" {
+				timer.Reset(reloadDelay)
+			}
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return nil
+			}
+			slog.LogAttrs(
+				ctx,
+				slog.LevelError,
+				"watching configuration files",
+				slog.String("error", err.Error()),
+			)
+		case <-timer.C:
+			err := r.Reload()
+			if err != nil {
+				slog.LogAttrs(
+					ctx,
+					slog.LevelError,
+					"reloading configuration, keeping the current one",
+					slog.String("error", err.Error()),
+				)
+				continue
+			}
+			slog.LogAttrs(ctx, slog.LevelInfo, "reloaded configuration")
+			err = watch(r.Current())
+			if err != nil {
+				slog.LogAttrs(
+					ctx,
+					slog.LevelError,
+					"watching configuration files",
+					slog.String("error", err.Error()),
+				)
+			}
+		}
+	}
+}
@@ -0,0 +1,142 @@
+package conf
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const reloadTestConfig = `
+kubernetesClient:
+  inCluster: true
+mongodb:
+  uri: mongodb://localhost:27017
+  username: rinc
+  password: rinc
+deploymentAndStatefulsetStatus:
+  enable: true
+  alerts:
+  - message: not ready
+    severity: warning
+    when: %s
+`
+
+func writeReloadTestConfig(t *testing.T, path, when string) {
+	t.Helper()
+	err := os.WriteFile(path, []byte(fmt.Sprintf(reloadTestConfig, when)), 0o600)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestReload(t *testing.T) {
+	a := assert.New(t)
+	config := filepath.Join(t.TempDir(), "config.yaml")
+	writeReloadTestConfig(t, config, "len(Deployments) > 0")
+
+	args := []string{"--conf", config}
+	c, err := New(args...)
+	if !a.NoError(err) {
+		return
+	}
+	r := NewReloader(c, args...)
+
+	var reloaded *C
+	r.OnReload(func(c *C) { reloaded = c })
+
+	writeReloadTestConfig(t, config, "len(Deployments) > 1")
+	a.NoError(r.Reload())
+	a.Equal("len(Deployments) > 1", r.Current().DaSS.Alerts[0].When.Text)
+	a.Same(r.Current(), reloaded)
+	a.Equal(uint64(1), r.Status().Reloads)
+
+	// an invalid alert keeps the current configuration
+	writeReloadTestConfig(t, config, "len(Deployments) >")
+	a.Error(r.Reload())
+	a.Equal("len(Deployments) > 1", r.Current().DaSS.Alerts[0].When.Text)
+	status := r.Status()
+	a.Equal(uint64(1), status.Reloads)
+	a.Equal(uint64(1), status.Failures)
+	a.NotEmpty(status.LastError)
+	a.NotNil(status.LastErrorTime)
+}
+
+func TestReloaderWatch(t *testing.T) {
+	a := assert.New(t)
+	config := filepath.Join(t.TempDir(), "config.yaml")
+	writeReloadTestConfig(t, config, "len(Deployments) > 0")
+
+	args := []string{"--conf", config}
+	c, err := New(args...)
+	if !a.NoError(err) {
+		return
+	}
+	r := NewReloader(c, args...)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- r.Watch(ctx) }()
+	defer func() {
+		cancel()
+		a.NoError(<-done)
+	}()
+
+	// give the watcher time to start
+	time.Sleep(100 * time.Millisecond)
+	writeReloadTestConfig(t, config, "len(Deployments) > 1")
+	a.Eventually(func() bool {
+		return r.Status().Reloads == 1
+	}, 5*time.Second, 50*time.Millisecond)
+	a.Equal("len(Deployments) > 1", r.Current().DaSS.Alerts[0].When.Text)
+}
+
+func TestReloaderWatchSecretFiles(t *testing.T) {
+	a := assert.New(t)
+	// secrets are usually mounted in another directory than the config
+	password := filepath.Join(t.TempDir(), "password")
+	if err := os.WriteFile(password, []byte("old\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	config := filepath.Join(t.TempDir(), "config.yaml")
+	yaml := strings.Replace(
+		fmt.Sprintf(reloadTestConfig, "len(Deployments) > 0"),
+		"password: rinc",
+		"passwordFile: "+password,
+		1,
+	)
+	if err := os.WriteFile(config, []byte(yaml), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	args := []string{"--conf", config}
+	c, err := New(args...)
+	if !a.NoError(err) {
+		return
+	}
+	a.Equal("old", c.Mongodb.Password)
+	r := NewReloader(c, args...)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- r.Watch(ctx) }()
+	defer func() {
+		cancel()
+		a.NoError(<-done)
+	}()
+
+	// give the watcher time to start
+	time.Sleep(100 * time.Millisecond)
+	if err := os.WriteFile(password, []byte("new\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	a.Eventually(func() bool {
+		return r.Status().Reloads == 1
+	}, 5*time.Second, 50*time.Millisecond)
+	a.Equal("new", r.Current().Mongodb.Password)
+}
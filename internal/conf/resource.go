@@ -5,9 +5,14 @@ package conf
 type ResourceUtilization struct {
 	// Enable specifies whether the resource utilization reporter is enabled.
 	Enable bool `koanf:"enable"`
-	// Namespace is the Kubernetes namespace that the resource utilization
+	// Namespace is the single Kubernetes namespace that the resource utilization
 	// reporter will be limited to.
+	//
+	// Deprecated: use Namespaces.
 	Namespace string `koanf:"namespace"`
+	// Namespaces selects the Kubernetes namespaces that the resource utilization
+	// reporter will be limited to. Leave blank for all namespaces.
+	Namespaces NamespaceSelector `koanf:"namespaces"`
 	// Alerts contain a message template, a severity level, and a conditional
 	// expression to trigger the respective alert.
 	Alerts []Alert `koanf:"alerts"`
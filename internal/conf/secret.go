@@ -0,0 +1,55 @@
+package conf
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// secretFile is a secret configuration value that can be read from a file.
+type secretFile struct {
+	// key is the configuration key of the file path.
+	key string
+	// path is the path to the file containing the secret.
+	path string
+	// value is the secret configuration value that is set to the contents
+	// of the file.
+	value *string
+}
+
+// secretFiles returns the secret configuration values that have a `*File`
+// variant.
+func (c *C) secretFiles() []secretFile {
+	return []secretFile{
+		{"vault.auth.tokenFile", c.Vault.Auth.TokenFile, &c.Vault.Auth.Token},
+		{"mongodb.usernameFile", c.Mongodb.UsernameFile, &c.Mongodb.Username},
+		{"mongodb.passwordFile", c.Mongodb.PasswordFile, &c.Mongodb.Password},
+		{"rabbitmq.management.usernameFile", c.RabbitMQ.Management.UsernameFile, &c.RabbitMQ.Management.Username},
+		{"rabbitmq.management.passwordFile", c.RabbitMQ.Management.PasswordFile, &c.RabbitMQ.Management.Password},
+		{"ceph.dashboardAPI.usernameFile", c.Ceph.DashboardAPI.UsernameFile, &c.Ceph.DashboardAPI.Username},
+		{"ceph.dashboardAPI.passwordFile", c.Ceph.DashboardAPI.PasswordFile, &c.Ceph.DashboardAPI.Password},
+		{"connectivity.neo4j.usernameFile", c.Connectivity.Neo4j.UsernameFile, &c.Connectivity.Neo4j.Username},
+		{"connectivity.neo4j.passwordFile", c.Connectivity.Neo4j.PasswordFile, &c.Connectivity.Neo4j.Password},
+		{"connectivity.postgres.usernameFile", c.Connectivity.Postgres.UsernameFile, &c.Connectivity.Postgres.Username},
+		{"connectivity.postgres.passwordFile", c.Connectivity.Postgres.PasswordFile, &c.Connectivity.Postgres.Password},
+		{"auth.oidc.clientSecretFile", c.Auth.OIDC.ClientSecretFile, &c.Auth.OIDC.ClientSecret},
+		{"auth.session.secretFile", c.Auth.Session.SecretFile, &c.Auth.Session.Secret},
+	}
+}
+
+// readSecretFiles sets the secret configuration values whose `*File` variant
+// is set to the contents of the respective file. Trailing newlines are
+// removed. A value read from a file takes precedence over the value itself.
+func (c *C) readSecretFiles() error {
+	for _, s := range c.secretFiles() {
+		if s.path == "" {
+			continue
+		}
+		data, err := os.ReadFile(s.path)
+		if err != nil {
+			return fmt.Errorf("`%s`: reading %q: %w", s.key, s.path, err)
+		}
+		*s.value = strings.TrimRight(string(data), "\r\n")
+	}
+	return nil
+}
@@ -0,0 +1,55 @@
+package conf
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewWithSecrets(t *testing.T) {
+	a := assert.New(t)
+	dir := t.TempDir()
+	config := filepath.Join(dir, "config.yaml")
+	password := filepath.Join(dir, "password")
+
+	err := os.WriteFile(config, []byte(`
+mongodb:
+  uri: mongodb://localhost:27017
+  username: yaml
+  password: yaml
+ceph:
+  dashboardAPI:
+    url: https://ceph
+    password: yaml
+`), 0o600)
+	if !a.NoError(err) {
+		return
+	}
+	err = os.WriteFile(password, []byte("from-file\n"), 0o600)
+	if !a.NoError(err) {
+		return
+	}
+
+	t.Setenv("RINC_MONGODB__USERNAME", "from-env")
+	t.Setenv("RINC_MONGODB__PASSWORD_FILE", password)
+	t.Setenv("RINC_CEPH__DASHBOARD_API__PASSWORD", "from-env")
+	t.Setenv("RINC_LONG_RUNNING_JOBS__OLDER_THAN", "1h")
+
+	c, err := New("--conf", config)
+	if !a.NoError(err) {
+		return
+	}
+	a.Equal("mongodb://localhost:27017", c.Mongodb.URI)
+	a.Equal("from-env", c.Mongodb.Username)
+	a.Equal("from-file", c.Mongodb.Password)
+	a.Equal("https://ceph", c.Ceph.DashboardAPI.URL)
+	a.Equal("from-env", c.Ceph.DashboardAPI.Password)
+	a.Equal(time.Hour, c.LongJobs.OlderThan)
+
+	t.Setenv("RINC_MONGODB__PASSWORD_FILE", filepath.Join(dir, "missing"))
+	_, err = New("--conf", config)
+	a.ErrorContains(err, "`mongodb.passwordFile`")
+}
@@ -0,0 +1,47 @@
+package conf
+
+// Telemetry contains configuration related to the instrumentation of RINC
+// itself.
+type Telemetry struct {
+	// Pushgateway contains configuration related to pushing the metrics of
+	// a scrape to a Prometheus Pushgateway.
+	Pushgateway Pushgateway `koanf:"pushgateway"`
+	// Tracing contains configuration related to exporting traces of scrapes
+	// and web requests to an OTLP collector.
+	Tracing Tracing `koanf:"tracing"`
+}
+
+// Pushgateway contains configuration related to pushing the metrics of a
+// scrape to a Prometheus Pushgateway once it is done, e.g., at the end of a
+// CronJob run.
+type Pushgateway struct {
+	// URL is the Pushgateway URL, e.g., http://pushgateway.monitoring:9091.
+	// Metrics aren't pushed if it is empty.
+	URL string `koanf:"url"`
+	// Job is the job label of the pushed metrics.
+	//
+	// Default: "rinc"
+	Job string `koanf:"job"`
+}
+
+// Tracing contains configuration related to exporting traces over OTLP/HTTP,
+// e.g., to an OpenTelemetry collector. A scrape is traced with a span per
+// reporter, and each web request with a span per handler.
+type Tracing struct {
+	// Endpoint is the URL of the OTLP/HTTP traces endpoint, e.g.,
+	// http://otel-collector.monitoring:4318/v1/traces. Traces aren't exported
+	// if it is empty.
+	Endpoint string `koanf:"endpoint"`
+	// Headers are sent with every export request, e.g., for authentication.
+	Headers map[string]string `koanf:"headers"`
+	// SampleRatio is the ratio of traces to sample, between 0 and 1. Spans
+	// of web requests follow the sampling decision of the caller, if any.
+	//
+	// Default: 1
+	SampleRatio float64 `koanf:"sampleRatio"`
+	// ServiceName is the service.name resource attribute of the exported
+	// spans.
+	//
+	// Default: "rinc"
+	ServiceName string `koanf:"serviceName"`
+}
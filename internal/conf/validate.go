@@ -1,31 +1,168 @@
 package conf
 
 import (
+	"errors"
 	"fmt"
 	"net"
+	"net/url"
+	"path"
+
+	"golang.org/x/crypto/bcrypt"
+	"k8s.io/apimachinery/pkg/labels"
 )
 
-// Validate validates the provided configuration.
+// FieldError is a validation error of a configuration field.
+type FieldError struct {
+	// Path is the configuration key of the field, e.g., `ceph.alerts[0].when`.
+	Path string
+	// Err describes what is wrong with the field.
+	Err error
+}
+
+func (e FieldError) Error() string {
+	return fmt.Sprintf("`%s`: %s", e.Path, e.Err)
+}
+
+func (e FieldError) Unwrap() error {
+	return e.Err
+}
+
+var errMissing = errors.New("missing value")
+
+// Validate validates the provided configuration. It collects the errors of
+// all fields, each as a FieldError, instead of returning on the first one.
 func (c C) Validate() error {
-	if err := validateLogLevel(c.Log.Level); err != nil {
-		return fmt.Errorf("`log.level`: %w", err)
+	v := new(validator)
+
+	v.check("log.level", validateLogLevel(c.Log.Level))
+	v.check("log.format", validateLogFormat(c.Log.Format))
+	if c.TerminationGracePeriod < 0 {
+		v.fail("terminationGracePeriod", fmt.Errorf("must not be negative, got %s", c.TerminationGracePeriod))
+	}
+	if c.Readiness.MaxScrapeAge < 0 {
+		v.fail("readiness.maxScrapeAge", fmt.Errorf("must not be negative, got %s", c.Readiness.MaxScrapeAge))
+	}
+	if c.REPL.Enable && c.REPL.Collection == "" {
+		v.fail("--collection", fmt.Errorf("required by `expr`"))
+	}
+
+	if c.Check.Enable {
+		if c.Check.Timeout <= 0 {
+			v.fail("--timeout", fmt.Errorf("must be positive, got %s", c.Check.Timeout))
+		}
+		if c.Check.Interval <= 0 {
+			v.fail("--interval", fmt.Errorf("must be positive, got %s", c.Check.Interval))
+		}
 	}
-	if err := validateLogFormat(c.Log.Format); err != nil {
-		return fmt.Errorf("`log.format`: %w", err)
+
+	if c.Snapshot.Record != "" && c.Snapshot.Replay != "" {
+		v.fail("--replay", fmt.Errorf("cannot be combined with --record"))
 	}
-	if err := validateKubernetesClient(c.KubernetesClient); err != nil {
-		return fmt.Errorf("`kubernetesClient`: %w", err)
+
+	switch c.Output.Format {
+	case "", OutputJSON, OutputYAML:
+	default:
+		v.fail("--output", fmt.Errorf("must be one of %q or %q, got %q", OutputJSON, OutputYAML, c.Output.Format))
 	}
-	if err := validateMongodb(c.Mongodb); err != nil {
-		return fmt.Errorf("`mongodb`: %w", err)
+	if c.Output.Format != "" && !c.Scrapes() {
+		v.fail("--output", fmt.Errorf("requires --scrape, --record or --replay"))
 	}
-	if err := validateRabbitMQ(c.RabbitMQ); err != nil {
-		return fmt.Errorf("rabbitmq: %w", err)
+	if c.Output.Dir != "" && c.Output.Format == "" {
+		v.fail("--out-dir", fmt.Errorf("requires --output"))
 	}
-	if err := validateCeph(c.Ceph); err != nil {
-		return fmt.Errorf("ceph: %w", err)
+
+	// reports loaded from a file by `expr` are neither fetched from the
+	// Kubernetes API server nor from MongoDB
+	fromFile := c.REPL.Enable && c.REPL.File != ""
+	// replayed snapshots don't reach the Kubernetes API server
+	if c.Snapshot.Replay == "" && !fromFile {
+		validateKubernetesClient(v, c.KubernetesClient)
+	}
+	validateVault(v, c.Vault)
+	// reports written with --output or checked aren't stored in MongoDB
+	if c.Output.Format == "" && !c.Check.Enable && !fromFile {
+		validateMongodb(v, c.Mongodb)
+	}
+	// replayed snapshots don't resolve the RabbitMQ nodes either
+	validateRabbitMQ(v, c.RabbitMQ, c.Snapshot.Replay == "")
+	validateLongJobs(v, c.LongJobs)
+	validateCeph(v, c.Ceph)
+	validatePVUtilization(v, c.PVUtilization)
+	validateConnectivity(v, c.Connectivity)
+	validateTelemetry(v, c.Telemetry)
+	validateAuth(v, c.Auth)
+	for _, n := range c.namespaced() {
+		validateNamespaces(v, n)
+	}
+	v.check("longRunningJobs.labelSelector", validateLabelSelector(c.LongJobs.LabelSelector))
+	v.check("imageTag.labelSelector", validateLabelSelector(c.ImageTag.LabelSelector))
+	v.check("deploymentAndStatefulsetStatus.labelSelector", validateLabelSelector(c.DaSS.LabelSelector))
+	v.check("podStatus.labelSelector", validateLabelSelector(c.PodStatus.LabelSelector))
+
+	v.alerts("rabbitmq.alerts", c.RabbitMQ.Alerts)
+	v.alerts("longRunningJobs.alerts", c.LongJobs.Alerts)
+	v.alerts("imageTag.alerts", c.ImageTag.Alerts)
+	v.alerts("deploymentAndStatefulsetStatus.alerts", c.DaSS.Alerts)
+	v.alerts("ceph.alerts", c.Ceph.Alerts)
+	v.alerts("pvUtilization.alerts", c.PVUtilization.Alerts)
+	v.alerts("resourceUtilization.alerts", c.ResourceUtilization.Alerts)
+	v.alerts("connectivity.alerts", c.Connectivity.Alerts)
+	v.alerts("podStatus.alerts", c.PodStatus.Alerts)
+
+	return errors.Join(v.errs...)
+}
+
+// validator collects the validation errors of configuration fields.
+type validator struct {
+	errs []error
+}
+
+// fail records err for the field at path.
+func (v *validator) fail(path string, err error) {
+	v.errs = append(v.errs, FieldError{Path: path, Err: err})
+}
+
+// check records err for the field at path if it isn't nil.
+func (v *validator) check(path string, err error) {
+	if err != nil {
+		v.fail(path, err)
+	}
+}
+
+// required records an error for the field at path if value is empty.
+func (v *validator) required(path, value string) {
+	if value == "" {
+		v.fail(path, errMissing)
+	}
+}
+
+// url records an error for the field at path if value isn't an absolute
+// URL.
+func (v *validator) url(path, value string) {
+	if value == "" {
+		v.fail(path, errMissing)
+		return
+	}
+	u, err := url.Parse(value)
+	if err != nil {
+		v.fail(path, err)
+		return
+	}
+	if u.Scheme == "" || u.Host == "" {
+		v.fail(path, fmt.Errorf("want an absolute url, got %q", value))
+	}
+}
+
+// alerts validates the alerts of the list at path.
+func (v *validator) alerts(path string, alerts []Alert) {
+	for idx, a := range alerts {
+		prefix := fmt.Sprintf("%s[%d]", path, idx)
+		v.required(prefix+".message", a.Message.Text)
+		v.check(prefix+".severity", validateSeverity(a.Severity))
+		if a.When.Evaluable == nil {
+			v.fail(prefix+".when", errMissing)
+		}
 	}
-	return nil
 }
 
 func validateLogLevel(level string) error {
@@ -35,7 +172,7 @@ func validateLogLevel(level string) error {
 	case "warn":
 	case "error":
 	default:
-		return fmt.Errorf("invalid value for `log.level`: %q", level)
+		return fmt.Errorf("invalid value %q, want one of \"debug\", \"info\", \"warn\", \"error\"", level)
 	}
 	return nil
 }
@@ -45,66 +182,196 @@ func validateLogFormat(format string) error {
 	case "text":
 	case "json":
 	default:
-		return fmt.Errorf("invalid value for `log.format`: %q", format)
+		return fmt.Errorf("invalid value %q, want one of \"text\", \"json\"", format)
 	}
 	return nil
 }
 
-func validateKubernetesClient(c KubernetesClient) error {
-	if c.InCluster || c.Kubeconfig != "" {
-		return nil
+func validateSeverity(s Severity) error {
+	switch s {
+	case SeverityInfo:
+	case SeverityWarning:
+	case SeverityCritical:
+	default:
+		return fmt.Errorf("invalid value %q, want one of %q, %q, %q",
+			s,
+			SeverityInfo,
+			SeverityWarning,
+			SeverityCritical,
+		)
 	}
-	return fmt.Errorf("either `inCluster` or `kubeconfig` must be set")
+	return nil
 }
 
-func validateMongodb(c Mongodb) error {
-	if c.URI == "" {
-		return fmt.Errorf("missing `mongodb.uri`")
-	}
-	if c.Username == "" {
-		return fmt.Errorf("missing `mongodb.username`")
+func validateKubernetesClient(v *validator, c KubernetesClient) {
+	if c.InCluster || c.Kubeconfig != "" {
+		return
 	}
-	if c.Password == "" {
-		return fmt.Errorf("missing `mongodb.password`")
+	v.fail("kubernetesClient", fmt.Errorf("either `inCluster` or `kubeconfig` must be set"))
+}
+
+func validateVault(v *validator, c Vault) {
+	switch c.Auth.Method {
+	case "kubernetes":
+	case "token":
+	default:
+		v.fail("vault.auth.method", fmt.Errorf("invalid value %q, want one of \"kubernetes\", \"token\"", c.Auth.Method))
 	}
-	return nil
 }
 
-func validateRabbitMQ(rmq RabbitMQ) error {
+func validateMongodb(v *validator, c Mongodb) {
+	v.required("mongodb.uri", c.URI)
+	v.required("mongodb.username", c.Username)
+	v.required("mongodb.password", c.Password)
+}
+
+// validateRabbitMQ validates rmq, and checks that its headless service
+// resolves if resolve is set.
+func validateRabbitMQ(v *validator, rmq RabbitMQ, resolve bool) {
 	if !rmq.Enable {
-		return nil
-	}
-	if rmq.Management.URL == "" {
-		return fmt.Errorf("missing `rabbitmq.management.url`")
-	}
-	if rmq.Management.Username == "" {
-		return fmt.Errorf("missing `rabbitmq.management.username`")
-	}
-	if rmq.Management.Password == "" {
-		return fmt.Errorf("missing `rabbitmq.management.password`")
+		return
 	}
+	v.url("rabbitmq.management.url", rmq.Management.URL)
+	v.required("rabbitmq.management.username", rmq.Management.Username)
+	v.required("rabbitmq.management.password", rmq.Management.Password)
 	if rmq.HeadlessSvcAddr == "" {
-		return fmt.Errorf("missing `rabbitmq.headlessSvcAddr`")
+		v.fail("rabbitmq.headlessSvcAddr", errMissing)
+		return
+	}
+	if !resolve {
+		return
 	}
 	_, err := net.LookupIP(rmq.HeadlessSvcAddr)
 	if err != nil {
-		return fmt.Errorf("failed to resolve %q: %w", rmq.HeadlessSvcAddr, err)
+		v.fail("rabbitmq.headlessSvcAddr", fmt.Errorf("failed to resolve %q: %w", rmq.HeadlessSvcAddr, err))
 	}
-	return nil
 }
 
-func validateCeph(c Ceph) error {
+func validateLongJobs(v *validator, c LongJobs) {
+	if !c.Enable {
+		return
+	}
+	if c.OlderThan <= 0 {
+		v.fail("longRunningJobs.olderThan", fmt.Errorf("must be greater than 0, got %s", c.OlderThan))
+	}
+}
+
+func validateCeph(v *validator, c Ceph) {
+	if !c.Enable {
+		return
+	}
+	v.url("ceph.dashboardAPI.url", c.DashboardAPI.URL)
+	v.required("ceph.dashboardAPI.username", c.DashboardAPI.Username)
+	v.required("ceph.dashboardAPI.password", c.DashboardAPI.Password)
+}
+
+func validatePVUtilization(v *validator, c PVUtilization) {
 	if !c.Enable {
-		return nil
+		return
+	}
+	v.url("pvUtilization.prometheusUrl", c.PrometheusURL)
+}
+
+func validateConnectivity(v *validator, c Connectivity) {
+	if c.Vault.Enable {
+		v.url("connectivity.vault.addr", c.Vault.Addr)
+	}
+	if c.Mongodb.Enable {
+		v.required("connectivity.mongodb.uri", c.Mongodb.URI)
+	}
+	if c.Neo4j.Enable {
+		v.required("connectivity.neo4j.uri", c.Neo4j.URI)
 	}
-	if c.DashboardAPI.URL == "" {
-		return fmt.Errorf("missing `ceph.dashboardAPI.url`")
+	if c.Postgres.Enable {
+		v.required("connectivity.postgres.host", c.Postgres.Host)
+		if c.Postgres.Port == 0 {
+			v.fail("connectivity.postgres.port", errMissing)
+		}
 	}
-	if c.DashboardAPI.Username == "" {
-		return fmt.Errorf("missing `ceph.dashboardAPI.username`")
+	if c.Redis.Enable {
+		v.required("connectivity.redis.addr", c.Redis.Addr)
+	}
+	if c.Metabase.Enable {
+		v.url("connectivity.metabase.baseUrl", c.Metabase.BaseURL)
+	}
+}
+
+func validateTelemetry(v *validator, c Telemetry) {
+	if c.Pushgateway.URL != "" {
+		v.url("telemetry.pushgateway.url", c.Pushgateway.URL)
+		v.required("telemetry.pushgateway.job", c.Pushgateway.Job)
+	}
+	if c.Tracing.Endpoint != "" {
+		v.url("telemetry.tracing.endpoint", c.Tracing.Endpoint)
+		v.required("telemetry.tracing.serviceName", c.Tracing.ServiceName)
+	}
+	if c.Tracing.SampleRatio < 0 || c.Tracing.SampleRatio > 1 {
+		v.fail("telemetry.tracing.sampleRatio", fmt.Errorf("must be between 0 and 1, got %v", c.Tracing.SampleRatio))
+	}
+}
+
+// minSessionSecretLen is the minimum length of the key the session cookies
+// are signed with, i.e., the size of a HMAC-SHA256 hash.
+const minSessionSecretLen = 32
+
+func validateAuth(v *validator, c Auth) {
+	switch c.Method {
+	case "":
+		return
+	case AuthBasic:
+		if len(c.Basic.Users) == 0 {
+			v.fail("auth.basic.users", errMissing)
+		}
+		for idx, u := range c.Basic.Users {
+			v.required(fmt.Sprintf("auth.basic.users[%d].username", idx), u.Username)
+			if _, err := bcrypt.Cost([]byte(u.PasswordHash)); err != nil {
+				v.fail(fmt.Sprintf("auth.basic.users[%d].passwordHash", idx), fmt.Errorf("want a bcrypt hash: %w", err))
+			}
+		}
+	case AuthOIDC:
+		v.url("auth.oidc.issuerUrl", c.OIDC.IssuerURL)
+		v.required("auth.oidc.clientId", c.OIDC.ClientID)
+		v.required("auth.oidc.clientSecret", c.OIDC.ClientSecret)
+		v.url("auth.oidc.redirectUrl", c.OIDC.RedirectURL)
+		if len(c.OIDC.AllowedGroups) != 0 {
+			v.required("auth.oidc.groupsClaim", c.OIDC.GroupsClaim)
+		}
+	default:
+		v.fail("auth.method", fmt.Errorf("must be one of %q or %q, got %q", AuthBasic, AuthOIDC, c.Method))
+		return
+	}
+	if len(c.Session.Secret) < minSessionSecretLen {
+		v.fail("auth.session.secret", fmt.Errorf("must be at least %d bytes long", minSessionSecretLen))
+	}
+	if c.Session.MaxAge <= 0 {
+		v.fail("auth.session.maxAge", fmt.Errorf("must be positive, got %s", c.Session.MaxAge))
+	}
+}
+
+func validateNamespaces(v *validator, n namespaced) {
+	if *n.namespace != "" && !n.selector.IsEmpty() {
+		v.fail(n.key+".namespace", fmt.Errorf("cannot be combined with `namespaces`, move it to `namespaces.include`"))
+	}
+	for idx, pattern := range n.selector.Include {
+		v.check(fmt.Sprintf("%s.namespaces.include[%d]", n.key, idx), validateGlob(pattern))
+	}
+	for idx, pattern := range n.selector.Exclude {
+		v.check(fmt.Sprintf("%s.namespaces.exclude[%d]", n.key, idx), validateGlob(pattern))
+	}
+	v.check(n.key+".namespaces.labelSelector", validateLabelSelector(n.selector.LabelSelector))
+}
+
+func validateLabelSelector(selector string) error {
+	_, err := labels.Parse(selector)
+	return err
+}
+
+func validateGlob(pattern string) error {
+	if pattern == "" {
+		return errMissing
 	}
-	if c.DashboardAPI.Password == "" {
-		return fmt.Errorf("missing `ceph.dashboardAPI.password`")
+	if _, err := path.Match(pattern, ""); err != nil {
+		return fmt.Errorf("invalid glob pattern %q: %w", pattern, err)
 	}
 	return nil
 }
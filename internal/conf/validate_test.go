@@ -2,6 +2,7 @@ package conf
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -46,3 +47,376 @@ func TestValidateLogFormat(t *testing.T) {
 		a.Errorf(err, "INPUT=%s", input)
 	}
 }
+
+func TestValidateSeverity(t *testing.T) {
+	a := assert.New(t)
+	inputs := map[Severity]bool{
+		SeverityInfo:     true,
+		SeverityWarning:  true,
+		SeverityCritical: true,
+		"error":          false,
+		"Warning":        false,
+		"":               false,
+	}
+	for input, isValid := range inputs {
+		err := validateSeverity(input)
+		if isValid {
+			a.NoErrorf(err, "INPUT=%s", input)
+			continue
+		}
+		a.Errorf(err, "INPUT=%s", input)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	a := assert.New(t)
+	valid := C{
+		Log:              Log{Level: "info", Format: "text"},
+		KubernetesClient: KubernetesClient{InCluster: true},
+		Vault:            Vault{Auth: VaultAuth{Method: "kubernetes"}},
+		Mongodb:          Mongodb{URI: "mongodb://localhost", Username: "u", Password: "p"},
+	}
+	a.NoError(valid.Validate())
+
+	var when Expr
+	if !a.NoError(when.UnmarshalText([]byte("true"))) {
+		return
+	}
+
+	c := valid
+	c.Readiness.MaxScrapeAge = -time.Hour
+	c.Mongodb.Password = ""
+	c.LongJobs = LongJobs{Enable: true}
+	c.PVUtilization = PVUtilization{Enable: true}
+	c.Connectivity = Connectivity{
+		Vault:    VaultCheck{Enable: true, Addr: "accuknox-vault:8200"},
+		Postgres: PostgresCheck{Enable: true, Host: "postgres"},
+	}
+	c.Ceph = Ceph{
+		Alerts: []Alert{
+			{Message: StringExpr{Text: "ok"}, Severity: SeverityInfo, When: when},
+			{Message: StringExpr{Text: "foo"}, Severity: "error", When: when},
+			{Severity: SeverityWarning},
+		},
+	}
+
+	err := c.Validate()
+	want := []string{
+		"readiness.maxScrapeAge",
+		"mongodb.password",
+		"longRunningJobs.olderThan",
+		"pvUtilization.prometheusUrl",
+		"connectivity.vault.addr",
+		"connectivity.postgres.port",
+		"ceph.alerts[1].severity",
+		"ceph.alerts[2].message",
+		"ceph.alerts[2].when",
+	}
+	joined, ok := err.(interface{ Unwrap() []error })
+	if !a.True(ok, "err=%v", err) {
+		return
+	}
+	var got []string
+	for _, err := range joined.Unwrap() {
+		var fieldErr FieldError
+		if a.ErrorAs(err, &fieldErr) {
+			got = append(got, fieldErr.Path)
+		}
+	}
+	a.Equal(want, got)
+}
+
+func TestValidateSelectors(t *testing.T) {
+	a := assert.New(t)
+	c := C{
+		Log:              Log{Level: "info", Format: "text"},
+		KubernetesClient: KubernetesClient{InCluster: true},
+		Vault:            Vault{Auth: VaultAuth{Method: "kubernetes"}},
+		Mongodb:          Mongodb{URI: "mongodb://localhost", Username: "u", Password: "p"},
+		DaSS:             DaSS{Namespace: "accuknox"},
+		LongJobs:         LongJobs{LabelSelector: "app in (rinc"},
+		ImageTag: ImageTag{
+			Namespace:  "accuknox",
+			Namespaces: NamespaceSelector{Exclude: []string{"kube-system"}},
+		},
+		PodStatus: PodStatus{
+			Namespaces: NamespaceSelector{
+				Include:       []string{"accuknox-[", ""},
+				LabelSelector: "team in (platform",
+			},
+		},
+	}
+	c.migrateNamespaces()
+	a.Equal([]string{"accuknox"}, c.DaSS.Namespaces.Include)
+	a.Empty(c.DaSS.Namespace)
+
+	err := c.Validate()
+	var got []string
+	for _, err := range err.(interface{ Unwrap() []error }).Unwrap() {
+		var fieldErr FieldError
+		if a.ErrorAs(err, &fieldErr) {
+			got = append(got, fieldErr.Path)
+		}
+	}
+	a.Equal([]string{
+		"imageTag.namespace",
+		"podStatus.namespaces.include[0]",
+		"podStatus.namespaces.include[1]",
+		"podStatus.namespaces.labelSelector",
+		"longRunningJobs.labelSelector",
+	}, got)
+}
+
+func TestValidateSnapshot(t *testing.T) {
+	a := assert.New(t)
+	c := C{
+		Log:     Log{Level: "info", Format: "text"},
+		Vault:   Vault{Auth: VaultAuth{Method: "kubernetes"}},
+		Mongodb: Mongodb{URI: "mongodb://localhost", Username: "u", Password: "p"},
+	}
+	// the kubernetes client isn't used on replay, nor are the RabbitMQ nodes
+	// resolved
+	c.Snapshot = Snapshot{Replay: "bundle.tar.gz"}
+	c.RabbitMQ = RabbitMQ{
+		Enable:          true,
+		HeadlessSvcAddr: "rabbitmq-nodes.invalid",
+		Management: RabbitMQManagement{
+			URL:      "http://rabbitmq:15672",
+			Username: "u",
+			Password: "p",
+		},
+	}
+	a.NoError(c.Validate())
+
+	c.Snapshot.Record = "other.tar.gz"
+	var fieldErr FieldError
+	if a.ErrorAs(c.Validate(), &fieldErr) {
+		a.Equal("--replay", fieldErr.Path)
+	}
+}
+
+func TestValidateOutput(t *testing.T) {
+	base := C{
+		Log:              Log{Level: "info", Format: "text"},
+		KubernetesClient: KubernetesClient{InCluster: true},
+		Vault:            Vault{Auth: VaultAuth{Method: "kubernetes"}},
+	}
+	tests := []struct {
+		name    string
+		scrape  bool
+		output  Output
+		wantErr string
+	}{
+		{
+			name:   "mongodb isn't required",
+			scrape: true,
+			output: Output{Format: OutputYAML, Dir: "reports"},
+		},
+		{
+			name:    "mongodb is required without output",
+			scrape:  true,
+			wantErr: "mongodb.uri",
+		},
+		{
+			name:    "unknown format",
+			scrape:  true,
+			output:  Output{Format: "xml"},
+			wantErr: "--output",
+		},
+		{
+			name:    "without scrape",
+			output:  Output{Format: OutputJSON},
+			wantErr: "--output",
+		},
+		{
+			name:    "dir without format",
+			scrape:  true,
+			output:  Output{Dir: "reports"},
+			wantErr: "--out-dir",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := assert.New(t)
+			c := base
+			c.RunAsScraper = tt.scrape
+			c.Output = tt.output
+			err := c.Validate()
+			if tt.wantErr == "" {
+				a.NoError(err)
+				return
+			}
+			var fieldErr FieldError
+			if a.ErrorAs(err, &fieldErr) {
+				a.Equal(tt.wantErr, fieldErr.Path)
+			}
+		})
+	}
+}
+
+func TestValidateREPL(t *testing.T) {
+	a := assert.New(t)
+	c := C{
+		Log:   Log{Level: "info", Format: "text"},
+		Vault: Vault{Auth: VaultAuth{Method: "kubernetes"}},
+		REPL:  REPL{Enable: true, Collection: "dass", File: "report.json"},
+	}
+	// reports loaded from a file need neither kubernetes nor mongodb
+	a.NoError(c.Validate())
+
+	c.REPL.File = ""
+	var got []string
+	for _, err := range c.Validate().(interface{ Unwrap() []error }).Unwrap() {
+		var fieldErr FieldError
+		if a.ErrorAs(err, &fieldErr) {
+			got = append(got, fieldErr.Path)
+		}
+	}
+	a.Equal([]string{
+		"kubernetesClient",
+		"mongodb.uri",
+		"mongodb.username",
+		"mongodb.password",
+	}, got)
+}
+
+func TestValidateCheck(t *testing.T) {
+	a := assert.New(t)
+	c := C{
+		Log:              Log{Level: "info", Format: "text"},
+		KubernetesClient: KubernetesClient{InCluster: true},
+		Vault:            Vault{Auth: VaultAuth{Method: "kubernetes"}},
+		Check:            Check{Enable: true, Timeout: time.Minute, Interval: time.Second},
+	}
+	// checks aren't stored in MongoDB
+	a.NoError(c.Validate())
+
+	c.Check.Interval = 0
+	var fieldErr FieldError
+	if a.ErrorAs(c.Validate(), &fieldErr) {
+		a.Equal("--interval", fieldErr.Path)
+	}
+}
+
+func TestValidateTelemetry(t *testing.T) {
+	tests := []struct {
+		telemetry Telemetry
+		want      []string
+	}{
+		{},
+		{telemetry: Telemetry{Pushgateway: Pushgateway{URL: "http://pushgateway:9091", Job: "rinc"}}},
+		{
+			telemetry: Telemetry{Pushgateway: Pushgateway{URL: "pushgateway", Job: "rinc"}},
+			want:      []string{"telemetry.pushgateway.url"},
+		},
+		{
+			telemetry: Telemetry{Pushgateway: Pushgateway{URL: "http://pushgateway:9091"}},
+			want:      []string{"telemetry.pushgateway.job"},
+		},
+		{telemetry: Telemetry{Tracing: Tracing{
+			Endpoint:    "http://otel-collector:4318/v1/traces",
+			SampleRatio: 0.1,
+			ServiceName: "rinc",
+		}}},
+		{
+			telemetry: Telemetry{Tracing: Tracing{Endpoint: "otel-collector:4318", ServiceName: "rinc"}},
+			want:      []string{"telemetry.tracing.endpoint"},
+		},
+		{
+			telemetry: Telemetry{Tracing: Tracing{SampleRatio: 1.5}},
+			want:      []string{"telemetry.tracing.sampleRatio"},
+		},
+	}
+	for _, tt := range tests {
+		a := assert.New(t)
+		v := new(validator)
+		validateTelemetry(v, tt.telemetry)
+		var got []string
+		for _, err := range v.errs {
+			got = append(got, err.(FieldError).Path)
+		}
+		a.Equal(tt.want, got, "telemetry=%+v", tt.telemetry)
+	}
+}
+
+func TestValidateAuth(t *testing.T) {
+	// bcrypt hash of "secret"
+	hash := "$2a$10$.3Z5pe5FFQK4XhdyZqQee.tC9TQWLhFM8MtTful9hI/QtAYiSIo6a"
+	session := Session{Secret: "0123456789abcdef0123456789abcdef", MaxAge: time.Hour}
+	oidc := OIDC{
+		IssuerURL:    "https://keycloak.example.com/realms/accuknox",
+		ClientID:     "rinc",
+		ClientSecret: "s3cr3t",
+		RedirectURL:  "https://rinc.example.com/auth/callback",
+		GroupsClaim:  "groups",
+	}
+	noClientSecret := oidc
+	noClientSecret.ClientSecret = ""
+	tests := []struct {
+		name string
+		auth Auth
+		want []string
+	}{
+		{name: "disabled"},
+		{
+			name: "basic",
+			auth: Auth{
+				Method:  AuthBasic,
+				Basic:   BasicAuth{Users: []BasicAuthUser{{Username: "alice", PasswordHash: hash}}},
+				Session: session,
+			},
+		},
+		{
+			name: "basic without users",
+			auth: Auth{Method: AuthBasic, Session: session},
+			want: []string{"auth.basic.users"},
+		},
+		{
+			name: "basic plaintext password",
+			auth: Auth{
+				Method:  AuthBasic,
+				Basic:   BasicAuth{Users: []BasicAuthUser{{PasswordHash: "secret"}}},
+				Session: session,
+			},
+			want: []string{"auth.basic.users[0].username", "auth.basic.users[0].passwordHash"},
+		},
+		{
+			name: "oidc",
+			auth: Auth{
+				Method:  AuthOIDC,
+				OIDC:    oidc,
+				Session: session,
+			},
+		},
+		{
+			name: "oidc without client secret",
+			auth: Auth{Method: AuthOIDC, OIDC: noClientSecret, Session: session},
+			want: []string{"auth.oidc.clientSecret"},
+		},
+		{
+			name: "short session secret",
+			auth: Auth{
+				Method:  AuthOIDC,
+				OIDC:    oidc,
+				Session: Session{Secret: "secret"},
+			},
+			want: []string{"auth.session.secret", "auth.session.maxAge"},
+		},
+		{
+			name: "unknown method",
+			auth: Auth{Method: "ldap"},
+			want: []string{"auth.method"},
+		},
+	}
+	for _, tt := range tests {
+		a := assert.New(t)
+		v := new(validator)
+		validateAuth(v, tt.auth)
+		var got []string
+		for _, err := range v.errs {
+			got = append(got, err.(FieldError).Path)
+		}
+		a.Equal(tt.want, got, tt.name)
+	}
+}
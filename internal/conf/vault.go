@@ -0,0 +1,215 @@
+package conf
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/vault/api"
+)
+
+// VaultRefPrefix is the prefix of configuration values that reference a
+// secret stored in vault, e.g., "vault:secret/data/rinc#mongoPassword".
+const VaultRefPrefix = "vault:"
+
+// Vault contains the configuration needed to resolve configuration values
+// referencing secrets stored in vault.
+type Vault struct {
+	// Addr is the vault address. Defaults to the VAULT_ADDR environment
+	// variable.
+	//
+	// E.g., http://accuknox-vault.accuknox-vault.svc.cluster.local:8200
+	Addr string `koanf:"addr"`
+	// Auth contains the configuration to authenticate with vault.
+	Auth VaultAuth `koanf:"auth"`
+}
+
+// VaultAuth contains the configuration to authenticate with vault.
+type VaultAuth struct {
+	// Method is the authentication method.
+	// Possible values: "kubernetes", "token".
+	//
+	// Default: "kubernetes"
+	Method string `koanf:"method"`
+	// Token is the vault token used by the token auth method. Defaults to
+	// the VAULT_TOKEN environment variable.
+	Token string `koanf:"token"`
+	// TokenFile is the path to a file containing the vault token. It takes
+	// precedence over Token.
+	TokenFile string `koanf:"tokenFile"`
+	// Kubernetes contains the configuration of the kubernetes auth method.
+	Kubernetes VaultKubernetesAuth `koanf:"kubernetes"`
+}
+
+// VaultKubernetesAuth contains the configuration of the vault kubernetes auth
+// method.
+type VaultKubernetesAuth struct {
+	// Role is the vault role to log in with.
+	Role string `koanf:"role"`
+	// MountPath is the path the kubernetes auth method is mounted at.
+	//
+	// Default: "kubernetes"
+	MountPath string `koanf:"mountPath"`
+	// ServiceAccountTokenFile is the path to the service account token used
+	// to log in.
+	//
+	// Default: "/var/run/secrets/kubernetes.io/serviceaccount/token"
+	ServiceAccountTokenFile string `koanf:"serviceAccountTokenFile"`
+}
+
+// vaultRef is a configuration value referencing a secret stored in vault.
+type vaultRef struct {
+	// key is the configuration key of the value.
+	key string
+	// value is the configuration value that is set to the secret.
+	value *string
+}
+
+// resolveVaultRefs replaces configuration values referencing secrets stored
+// in vault with the secrets. Vault is only contacted if there is at least one
+// reference.
+func (c *C) resolveVaultRefs(ctx context.Context) error {
+	var refs []vaultRef
+	collectVaultRefs(reflect.ValueOf(c).Elem(), "", &refs)
+	if len(refs) == 0 {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, time.Minute)
+	defer cancel()
+
+	client, err := newVaultClient(ctx, c.Vault)
+	if err != nil {
+		return err
+	}
+
+	secrets := make(map[string]map[string]any)
+	for _, r := range refs {
+		path, field, ok := strings.Cut(strings.TrimPrefix(*r.value, VaultRefPrefix), "#")
+		if !ok || path == "" || field == "" {
+			return fmt.Errorf("`%s`: invalid vault reference %q, want \"vault:<path>#<key>\"", r.key, *r.value)
+		}
+		data, ok := secrets[path]
+		if !ok {
+			data, err = readVaultSecret(ctx, client, path)
+			if err != nil {
+				return fmt.Errorf("`%s`: %w", r.key, err)
+			}
+			secrets[path] = data
+		}
+		value, ok := data[field]
+		if !ok {
+			return fmt.Errorf("`%s`: key %q not found in vault secret %q", r.key, field, path)
+		}
+		*r.value = fmt.Sprint(value)
+	}
+	return nil
+}
+
+// collectVaultRefs appends the string values within v that reference a vault
+// secret to refs.
+func collectVaultRefs(v reflect.Value, key string, refs *[]vaultRef) {
+	switch v.Kind() {
+	case reflect.Struct:
+		for idx := 0; idx < v.NumField(); idx++ {
+			fkey, ok := fieldKey(v.Type().Field(idx))
+			if !ok {
+				continue
+			}
+			if key != "" {
+				fkey = key + "." + fkey
+			}
+			collectVaultRefs(v.Field(idx), fkey, refs)
+		}
+	case reflect.Slice, reflect.Array:
+		for idx := 0; idx < v.Len(); idx++ {
+			collectVaultRefs(v.Index(idx), fmt.Sprintf("%s[%d]", key, idx), refs)
+		}
+	case reflect.Pointer:
+		if !v.IsNil() {
+			collectVaultRefs(v.Elem(), key, refs)
+		}
+	case reflect.String:
+		if v.CanSet() && v.Type() == reflect.TypeOf("") &&
+			strings.HasPrefix(v.String(), VaultRefPrefix) {
+			*refs = append(*refs, vaultRef{
+				key:   key,
+				value: v.Addr().Interface().(*string),
+			})
+		}
+	}
+}
+
+// newVaultClient creates a vault client authenticated using the configured
+// auth method.
+func newVaultClient(ctx context.Context, c Vault) (*api.Client, error) {
+	config := api.DefaultConfig()
+	if config.Error != nil {
+		return nil, fmt.Errorf("`vault`: reading environment: %w", config.Error)
+	}
+	if c.Addr != "" {
+		config.Address = c.Addr
+	}
+	client, err := api.NewClient(config)
+	if err != nil {
+		return nil, fmt.Errorf("`vault`: creating api client: %w", err)
+	}
+
+	switch c.Auth.Method {
+	case "token":
+		if c.Auth.Token != "" {
+			client.SetToken(c.Auth.Token)
+		}
+		if client.Token() == "" {
+			return nil, fmt.Errorf("`vault.auth`: missing `token`")
+		}
+	case "kubernetes":
+		k := c.Auth.Kubernetes
+		if k.Role == "" {
+			return nil, fmt.Errorf("`vault.auth.kubernetes`: missing `role`")
+		}
+		jwt, err := os.ReadFile(k.ServiceAccountTokenFile)
+		if err != nil {
+			return nil, fmt.Errorf("`vault.auth.kubernetes`: reading service account token: %w", err)
+		}
+		secret, err := client.Logical().WriteWithContext(
+			ctx,
+			fmt.Sprintf("auth/%s/login", strings.Trim(k.MountPath, "/")),
+			map[string]any{
+				"role": k.Role,
+				"jwt":  strings.TrimSpace(string(jwt)),
+			},
+		)
+		if err != nil {
+			return nil, fmt.Errorf("`vault.auth.kubernetes`: logging in: %w", err)
+		}
+		if secret == nil || secret.Auth == nil || secret.Auth.ClientToken == "" {
+			return nil, fmt.Errorf("`vault.auth.kubernetes`: login returned no token")
+		}
+		client.SetToken(secret.Auth.ClientToken)
+	default:
+		return nil, fmt.Errorf("`vault.auth.method`: invalid value %q", c.Auth.Method)
+	}
+	return client, nil
+}
+
+// readVaultSecret reads the data of the secret stored at path. The data of
+// KV version 2 secrets is unwrapped.
+func readVaultSecret(ctx context.Context, client *api.Client, path string) (map[string]any, error) {
+	secret, err := client.Logical().ReadWithContext(ctx, path)
+	if err != nil {
+		return nil, fmt.Errorf("reading vault secret %q: %w", path, err)
+	}
+	if secret == nil || secret.Data == nil {
+		return nil, fmt.Errorf("vault secret %q not found", path)
+	}
+	if _, ok := secret.Data["metadata"]; ok {
+		if data, ok := secret.Data["data"].(map[string]any); ok {
+			return data, nil
+		}
+	}
+	return secret.Data, nil
+}
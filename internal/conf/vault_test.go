@@ -0,0 +1,111 @@
+package conf
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newVaultServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	mux := http.NewServeMux()
+	mux.HandleFunc("PUT /v1/auth/kubernetes/login", func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]string
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		if body["role"] != "rinc" || body["jwt"] != "sa-token" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		_, _ = w.Write([]byte(`{"auth": {"client_token": "k8s-token"}}`))
+	})
+	mux.HandleFunc("GET /v1/secret/data/rinc", func(w http.ResponseWriter, r *http.Request) {
+		token := r.Header.Get("X-Vault-Token")
+		if token != "k8s-token" && token != "root" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		_, _ = w.Write([]byte(`{"data": {"data": {"mongoPassword": "s3cr3t", "port": 5432}, "metadata": {"version": 1}}}`))
+	})
+	mux.HandleFunc("GET /v1/kv/rinc", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"data": {"cephPassword": "p4ss"}}`))
+	})
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestResolveVaultRefs(t *testing.T) {
+	a := assert.New(t)
+	srv := newVaultServer(t)
+	saToken := filepath.Join(t.TempDir(), "token")
+	if !a.NoError(os.WriteFile(saToken, []byte("sa-token\n"), 0o600)) {
+		return
+	}
+	t.Setenv("VAULT_TOKEN", "")
+
+	kubernetesAuth := VaultAuth{
+		Method: "kubernetes",
+		Kubernetes: VaultKubernetesAuth{
+			Role:                    "rinc",
+			MountPath:               "kubernetes",
+			ServiceAccountTokenFile: saToken,
+		},
+	}
+
+	c := C{
+		Vault:   Vault{Addr: srv.URL, Auth: kubernetesAuth},
+		Mongodb: Mongodb{URI: "mongodb://localhost", Password: "vault:secret/data/rinc#mongoPassword"},
+		Ceph: Ceph{
+			DashboardAPI: CephDashboardAPI{Password: "vault:kv/rinc#cephPassword"},
+		},
+		Connectivity: Connectivity{
+			Postgres: PostgresCheck{Password: "vault:secret/data/rinc#port"},
+		},
+	}
+	if a.NoError(c.resolveVaultRefs(context.TODO())) {
+		a.Equal("mongodb://localhost", c.Mongodb.URI)
+		a.Equal("s3cr3t", c.Mongodb.Password)
+		a.Equal("p4ss", c.Ceph.DashboardAPI.Password)
+		a.Equal("5432", c.Connectivity.Postgres.Password)
+	}
+
+	c = C{
+		Vault:   Vault{Addr: srv.URL, Auth: VaultAuth{Method: "token", Token: "root"}},
+		Mongodb: Mongodb{Password: "vault:secret/data/rinc#mongoPassword"},
+	}
+	if a.NoError(c.resolveVaultRefs(context.TODO())) {
+		a.Equal("s3cr3t", c.Mongodb.Password)
+	}
+
+	errs := map[string]C{
+		"`mongodb.password`: key \"foo\" not found": {
+			Vault:   Vault{Addr: srv.URL, Auth: kubernetesAuth},
+			Mongodb: Mongodb{Password: "vault:secret/data/rinc#foo"},
+		},
+		"`mongodb.password`: invalid vault reference": {
+			Vault:   Vault{Addr: srv.URL, Auth: kubernetesAuth},
+			Mongodb: Mongodb{Password: "vault:secret/data/rinc"},
+		},
+		"`vault.auth`: missing `token`": {
+			Vault:   Vault{Addr: srv.URL, Auth: VaultAuth{Method: "token"}},
+			Mongodb: Mongodb{Password: "vault:secret/data/rinc#mongoPassword"},
+		},
+		"`vault.auth.method`: invalid value": {
+			Vault:   Vault{Addr: srv.URL, Auth: VaultAuth{Method: "foo"}},
+			Mongodb: Mongodb{Password: "vault:secret/data/rinc#mongoPassword"},
+		},
+	}
+	for want, c := range errs {
+		a.ErrorContains(c.resolveVaultRefs(context.TODO()), want)
+	}
+
+	// vault is not contacted without references
+	c = C{Vault: Vault{Auth: VaultAuth{Method: "foo"}}}
+	a.NoError(c.resolveVaultRefs(context.TODO()))
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 
 	"github.com/accuknox/rinc/internal/conf"
+	"github.com/accuknox/rinc/internal/telemetry"
 
 	"go.mongodb.org/mongo-driver/v2/mongo"
 	"go.mongodb.org/mongo-driver/v2/mongo/options"
@@ -17,6 +18,7 @@ func NewMongoDBClient(conf conf.Mongodb) (*mongo.Client, error) {
 	opts := options.
 		Client().
 		ApplyURI(conf.URI).
+		SetMonitor(telemetry.MongoMonitor).
 		SetAuth(options.Credential{
 			Username: conf.Username,
 			Password: conf.Password,
@@ -1,9 +1,19 @@
 package db
 
 import (
+	"fmt"
 	"time"
 
 	"github.com/accuknox/rinc/internal/conf"
+	"github.com/accuknox/rinc/types/ceph"
+	"github.com/accuknox/rinc/types/connectivity"
+	"github.com/accuknox/rinc/types/dass"
+	"github.com/accuknox/rinc/types/imagetag"
+	"github.com/accuknox/rinc/types/longjobs"
+	"github.com/accuknox/rinc/types/pod"
+	"github.com/accuknox/rinc/types/pv"
+	"github.com/accuknox/rinc/types/rabbitmq"
+	"github.com/accuknox/rinc/types/resource"
 )
 
 // AlertDocument defines the schema that should be stored in the
@@ -47,3 +57,30 @@ var Collections = []string{
 	CollectionConnectivity,
 	CollectionPodStatus,
 }
+
+// NewMetrics returns a pointer to a new zero value of the metrics type stored
+// in the provided collection.
+func NewMetrics(collection string) (any, error) {
+	switch collection {
+	case CollectionRabbitmq:
+		return new(rabbitmq.Metrics), nil
+	case CollectionCeph:
+		return new(ceph.Metrics), nil
+	case CollectionImageTag:
+		return new(imagetag.Metrics), nil
+	case CollectionDass:
+		return new(dass.Metrics), nil
+	case CollectionLongJobs:
+		return new(longjobs.Metrics), nil
+	case CollectionPVUtilizaton:
+		return new(pv.Metrics), nil
+	case CollectionResourceUtilization:
+		return new(resource.Metrics), nil
+	case CollectionConnectivity:
+		return new(connectivity.Metrics), nil
+	case CollectionPodStatus:
+		return new(pod.Metrics), nil
+	default:
+		return nil, fmt.Errorf("invalid collection: %q", collection)
+	}
+}
@@ -0,0 +1,348 @@
+// Package exporter exposes the latest stored reports as Prometheus metrics,
+// e.g., for Grafana dashboards and Alertmanager rules.
+package exporter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"time"
+
+	"github.com/accuknox/rinc/internal/conf"
+	"github.com/accuknox/rinc/internal/db"
+	"github.com/accuknox/rinc/types/ceph"
+	"github.com/accuknox/rinc/types/connectivity"
+	"github.com/accuknox/rinc/types/dass"
+	"github.com/accuknox/rinc/types/pv"
+	"github.com/accuknox/rinc/types/rabbitmq"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// timeout bounds the time spent reading the latest reports on a scrape.
+const timeout = 10 * time.Second
+
+// severities are the severities of which the number of firing alerts is
+// always exposed, even if none fired.
+var severities = []conf.Severity{
+	conf.SeverityInfo,
+	conf.SeverityWarning,
+	conf.SeverityCritical,
+}
+
+// cephStatuses are the ceph health statuses that are always exposed.
+var cephStatuses = []string{"HEALTH_OK", "HEALTH_WARN", "HEALTH_ERR"}
+
+var (
+	descReportTimestamp = prometheus.NewDesc(
+		"rinc_report_timestamp_seconds",
+		"Time of the latest report of the reporter.",
+		[]string{"reporter"}, nil,
+	)
+	descAlertsFiring = prometheus.NewDesc(
+		"rinc_alerts_firing",
+		"Number of alerts that fired on the latest report of the reporter.",
+		[]string{"reporter", "severity"}, nil,
+	)
+	descRabbitMQUp = prometheus.NewDesc(
+		"rinc_rabbitmq_up",
+		"Whether the RabbitMQ cluster is up.",
+		nil, nil,
+	)
+	descRabbitMQQueueMessages = prometheus.NewDesc(
+		"rinc_rabbitmq_queue_messages",
+		"Number of messages in the RabbitMQ queue.",
+		[]string{"vhost", "queue"}, nil,
+	)
+	descRabbitMQQueueMessagesReady = prometheus.NewDesc(
+		"rinc_rabbitmq_queue_messages_ready",
+		"Number of messages in the RabbitMQ queue ready to be delivered.",
+		[]string{"vhost", "queue"}, nil,
+	)
+	descRabbitMQQueueMessagesUnacked = prometheus.NewDesc(
+		"rinc_rabbitmq_queue_messages_unacknowledged",
+		"Number of messages in the RabbitMQ queue delivered but not yet acknowledged.",
+		[]string{"vhost", "queue"}, nil,
+	)
+	descCephHealthStatus = prometheus.NewDesc(
+		"rinc_ceph_health_status",
+		"Whether the Ceph cluster has the health status, e.g., HEALTH_OK.",
+		[]string{"status"}, nil,
+	)
+	descDaSSReplicasDesired = prometheus.NewDesc(
+		"rinc_dass_replicas_desired",
+		"Number of desired replicas of the deployment or statefulset.",
+		[]string{"kind", "namespace", "name"}, nil,
+	)
+	descDaSSReplicasReady = prometheus.NewDesc(
+		"rinc_dass_replicas_ready",
+		"Number of ready replicas of the deployment or statefulset.",
+		[]string{"kind", "namespace", "name"}, nil,
+	)
+	descDaSSReplicasAvailable = prometheus.NewDesc(
+		"rinc_dass_replicas_available",
+		"Number of available replicas of the deployment or statefulset.",
+		[]string{"kind", "namespace", "name"}, nil,
+	)
+	descPVCapacity = prometheus.NewDesc(
+		"rinc_pv_capacity_bytes",
+		"Capacity of the persistent volume bound to the claim.",
+		[]string{"namespace", "pvc"}, nil,
+	)
+	descPVUsed = prometheus.NewDesc(
+		"rinc_pv_used_bytes",
+		"Used bytes of the persistent volume bound to the claim.",
+		[]string{"namespace", "pvc"}, nil,
+	)
+	descPVUtilization = prometheus.NewDesc(
+		"rinc_pv_utilization_percent",
+		"Utilization of the persistent volume bound to the claim in percent.",
+		[]string{"namespace", "pvc"}, nil,
+	)
+	descConnectivityReachable = prometheus.NewDesc(
+		"rinc_connectivity_reachable",
+		"Whether the service was reachable, or healthy for metabase.",
+		[]string{"service"}, nil,
+	)
+)
+
+// Report is the latest report of a reporter.
+type Report struct {
+	// Collection is the collection of the report, e.g., dass.
+	Collection string
+	Timestamp  time.Time
+	// Metrics is a pointer to the metrics document of the collection, as
+	// returned by db.NewMetrics.
+	Metrics any
+	// Alerts are the alerts that fired on the report.
+	Alerts []db.Alert
+}
+
+// Source returns the latest report of every reporter.
+type Source interface {
+	Latest(ctx context.Context) ([]Report, error)
+}
+
+// Collector is a prometheus.Collector that reads the latest reports from its
+// source on every scrape.
+type Collector struct {
+	source Source
+	// conf returns the current configuration.
+	conf func() *conf.C
+}
+
+// NewCollector returns a Collector of the reports of source. conf returns
+// the current configuration, which determines the connectivity checks to
+// expose.
+func NewCollector(source Source, conf func() *conf.C) *Collector {
+	return &Collector{
+		source: source,
+		conf:   conf,
+	}
+}
+
+// Describe satisfies the prometheus.Collector interface.
+func (c *Collector) Describe(ch chan<- *prometheus.Desc) {
+	for _, d := range []*prometheus.Desc{
+		descReportTimestamp,
+		descAlertsFiring,
+		descRabbitMQUp,
+		descRabbitMQQueueMessages,
+		descRabbitMQQueueMessagesReady,
+		descRabbitMQQueueMessagesUnacked,
+		descCephHealthStatus,
+		descDaSSReplicasDesired,
+		descDaSSReplicasReady,
+		descDaSSReplicasAvailable,
+		descPVCapacity,
+		descPVUsed,
+		descPVUtilization,
+		descConnectivityReachable,
+	} {
+		ch <- d
+	}
+}
+
+// Collect satisfies the prometheus.Collector interface.
+func (c *Collector) Collect(ch chan<- prometheus.Metric) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	reports, err := c.source.Latest(ctx)
+	if err != nil {
+		slog.LogAttrs(
+			ctx,
+			slog.LevelError,
+			"reading latest reports",
+			slog.String("error", err.Error()),
+		)
+		ch <- prometheus.NewInvalidMetric(descReportTimestamp, err)
+		return
+	}
+
+	for _, r := range reports {
+		ch <- gauge(descReportTimestamp, float64(r.Timestamp.Unix()), r.Collection)
+		counts := make(map[conf.Severity]int)
+		for _, a := range r.Alerts {
+			counts[a.Severity]++
+		}
+		for _, sev := range severities {
+			ch <- gauge(descAlertsFiring, float64(counts[sev]), r.Collection, string(sev))
+		}
+
+		switch m := r.Metrics.(type) {
+		case *rabbitmq.Metrics:
+			collectRabbitMQ(ch, m)
+		case *ceph.Metrics:
+			collectCeph(ch, m)
+		case *dass.Metrics:
+			collectDaSS(ch, m)
+		case *pv.Metrics:
+			collectPV(ch, m)
+		case *connectivity.Metrics:
+			collectConnectivity(ch, m, c.conf().Connectivity)
+		}
+	}
+}
+
+func collectRabbitMQ(ch chan<- prometheus.Metric, m *rabbitmq.Metrics) {
+	ch <- gauge(descRabbitMQUp, boolFloat(m.IsClusterUp))
+	for _, q := range m.Queues {
+		ch <- gauge(descRabbitMQQueueMessages, float64(q.Messages), q.Vhost, q.Name)
+		ch <- gauge(descRabbitMQQueueMessagesReady, float64(q.ReadyMessages), q.Vhost, q.Name)
+		ch <- gauge(descRabbitMQQueueMessagesUnacked, float64(q.UnacknowledgedMessages), q.Vhost, q.Name)
+	}
+}
+
+func collectCeph(ch chan<- prometheus.Metric, m *ceph.Metrics) {
+	status := m.Status.Health.Status
+	for _, s := range cephStatuses {
+		ch <- gauge(descCephHealthStatus, boolFloat(s == status), s)
+	}
+	if status != "" && !slices.Contains(cephStatuses, status) {
+		ch <- gauge(descCephHealthStatus, 1, status)
+	}
+}
+
+func collectDaSS(ch chan<- prometheus.Metric, m *dass.Metrics) {
+	for _, k := range []struct {
+		kind      string
+		resources []dass.Resource
+	}{
+		{"deployment", m.Deployments},
+		{"statefulset", m.Statefulsets},
+	} {
+		for _, r := range k.resources {
+			ch <- gauge(descDaSSReplicasDesired, float64(r.DesiredReplicas), k.kind, r.Namespace, r.Name)
+			ch <- gauge(descDaSSReplicasReady, float64(r.ReadyReplicas), k.kind, r.Namespace, r.Name)
+			ch <- gauge(descDaSSReplicasAvailable, float64(r.AvailableReplicas), k.kind, r.Namespace, r.Name)
+		}
+	}
+}
+
+func collectPV(ch chan<- prometheus.Metric, m *pv.Metrics) {
+	for _, p := range m.PVs {
+		ch <- gauge(descPVCapacity, p.Capacity, p.PVCNamespace, p.PVC)
+		ch <- gauge(descPVUsed, p.Used, p.PVCNamespace, p.PVC)
+		ch <- gauge(descPVUtilization, p.UtilizationPercent, p.PVCNamespace, p.PVC)
+	}
+}
+
+// collectConnectivity exposes the checks enabled in c, since the stored
+// report doesn't tell disabled checks apart from unreachable services.
+func collectConnectivity(ch chan<- prometheus.Metric, m *connectivity.Metrics, c conf.Connectivity) {
+	for _, s := range []struct {
+		name      string
+		enabled   bool
+		reachable bool
+	}{
+		{"vault", c.Vault.Enable, m.Vault.Reachable},
+		{"mongodb", c.Mongodb.Enable, m.Mongodb.Reachable},
+		{"neo4j", c.Neo4j.Enable, m.Neo4j.Reachable},
+		{"postgres", c.Postgres.Enable, m.Postgres.Reachable},
+		{"redis", c.Redis.Enable, m.Redis.Reachable},
+		{"metabase", c.Metabase.Enable, m.Metabase.Healthy},
+	} {
+		if s.enabled {
+			ch <- gauge(descConnectivityReachable, boolFloat(s.reachable), s.name)
+		}
+	}
+}
+
+func gauge(desc *prometheus.Desc, v float64, labels ...string) prometheus.Metric {
+	return prometheus.MustNewConstMetric(desc, prometheus.GaugeValue, v, labels...)
+}
+
+func boolFloat(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+// MongoSource reads the latest reports from MongoDB.
+type MongoSource struct {
+	Client *mongo.Client
+}
+
+// Latest satisfies the Source interface by reading the latest document of
+// every collection, and the alerts that fired on it.
+func (s MongoSource) Latest(ctx context.Context) ([]Report, error) {
+	var reports []Report
+	opts := options.FindOne().SetSort(bson.D{{Key: "timestamp", Value: -1}})
+	for _, coll := range db.Collections {
+		result := db.
+			Database(s.Client).
+			Collection(coll).
+			FindOne(ctx, bson.M{}, opts)
+		if err := result.Err(); err != nil {
+			if errors.Is(err, mongo.ErrNoDocuments) {
+				continue
+			}
+			return nil, fmt.Errorf("fetching latest %s report: %w", coll, err)
+		}
+		metrics, err := db.NewMetrics(coll)
+		if err != nil {
+			return nil, err
+		}
+		if err := result.Decode(metrics); err != nil {
+			return nil, fmt.Errorf("decoding latest %s report: %w", coll, err)
+		}
+		var stamp struct {
+			Timestamp time.Time `bson:"timestamp"`
+		}
+		if err := result.Decode(&stamp); err != nil {
+			return nil, fmt.Errorf("decoding latest %s report: %w", coll, err)
+		}
+
+		alerts := new(db.AlertDocument)
+		result = db.
+			Database(s.Client).
+			Collection(db.CollectionAlerts).
+			FindOne(ctx, bson.M{
+				"timestamp": stamp.Timestamp,
+				"from":      coll,
+			})
+		err = result.Err()
+		if err != nil && !errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, fmt.Errorf("fetching alerts of the latest %s report: %w", coll, err)
+		}
+		if err == nil {
+			if err := result.Decode(alerts); err != nil {
+				return nil, fmt.Errorf("decoding alerts of the latest %s report: %w", coll, err)
+			}
+		}
+
+		reports = append(reports, Report{
+			Collection: coll,
+			Timestamp:  stamp.Timestamp,
+			Metrics:    metrics,
+			Alerts:     alerts.Alerts,
+		})
+	}
+	return reports, nil
+}
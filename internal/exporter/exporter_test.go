@@ -0,0 +1,179 @@
+package exporter_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/accuknox/rinc/internal/conf"
+	"github.com/accuknox/rinc/internal/db"
+	"github.com/accuknox/rinc/internal/exporter"
+	"github.com/accuknox/rinc/types/ceph"
+	"github.com/accuknox/rinc/types/connectivity"
+	"github.com/accuknox/rinc/types/dass"
+	"github.com/accuknox/rinc/types/pv"
+	"github.com/accuknox/rinc/types/rabbitmq"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+type source struct {
+	reports []exporter.Report
+	err     error
+}
+
+func (s source) Latest(context.Context) ([]exporter.Report, error) {
+	return s.reports, s.err
+}
+
+func TestCollector(t *testing.T) {
+	a := assert.New(t)
+	at := time.Date(2024, 10, 18, 12, 0, 0, 0, time.UTC)
+	reports := []exporter.Report{
+		{
+			Collection: db.CollectionRabbitmq,
+			Timestamp:  at,
+			Metrics: &rabbitmq.Metrics{
+				IsClusterUp: true,
+				Queues: rabbitmq.Queues{{
+					Name:                   "events",
+					Vhost:                  "/",
+					Messages:               12,
+					ReadyMessages:          10,
+					UnacknowledgedMessages: 2,
+				}},
+			},
+		},
+		{
+			Collection: db.CollectionCeph,
+			Timestamp:  at,
+			Metrics: &ceph.Metrics{Status: ceph.Status{
+				Health: ceph.Health{Status: "HEALTH_WARN"},
+			}},
+			Alerts: []db.Alert{{Severity: conf.SeverityWarning}},
+		},
+		{
+			Collection: db.CollectionDass,
+			Timestamp:  at,
+			Metrics: &dass.Metrics{Deployments: []dass.Resource{{
+				Name:              "api",
+				Namespace:         "accuknox",
+				DesiredReplicas:   3,
+				ReadyReplicas:     1,
+				AvailableReplicas: 1,
+			}}},
+			Alerts: []db.Alert{
+				{Severity: conf.SeverityWarning},
+				{Severity: conf.SeverityCritical},
+			},
+		},
+		{
+			Collection: db.CollectionPVUtilizaton,
+			Timestamp:  at,
+			Metrics: &pv.Metrics{PVs: pv.PVs{{
+				PVC:                "data-mongodb-0",
+				PVCNamespace:       "accuknox",
+				Capacity:           1000,
+				Used:               250,
+				UtilizationPercent: 25,
+			}}},
+		},
+		{
+			Collection: db.CollectionConnectivity,
+			Timestamp:  at,
+			Metrics: &connectivity.Metrics{
+				Vault:   connectivity.Vault{Reachable: true},
+				Mongodb: connectivity.Mongodb{Reachable: false},
+			},
+		},
+	}
+	c := &conf.C{}
+	c.Connectivity.Vault.Enable = true
+	c.Connectivity.Mongodb.Enable = true
+	collector := exporter.NewCollector(
+		source{reports: reports},
+		func() *conf.C { return c },
+	)
+
+	want := `
+# HELP rinc_alerts_firing Number of alerts that fired on the latest report of the reporter.
+# TYPE rinc_alerts_firing gauge
+rinc_alerts_firing{reporter="ceph",severity="critical"} 0
+rinc_alerts_firing{reporter="ceph",severity="info"} 0
+rinc_alerts_firing{reporter="ceph",severity="warning"} 1
+rinc_alerts_firing{reporter="connectivity",severity="critical"} 0
+rinc_alerts_firing{reporter="connectivity",severity="info"} 0
+rinc_alerts_firing{reporter="connectivity",severity="warning"} 0
+rinc_alerts_firing{reporter="dass",severity="critical"} 1
+rinc_alerts_firing{reporter="dass",severity="info"} 0
+rinc_alerts_firing{reporter="dass",severity="warning"} 1
+rinc_alerts_firing{reporter="pv_utilization",severity="critical"} 0
+rinc_alerts_firing{reporter="pv_utilization",severity="info"} 0
+rinc_alerts_firing{reporter="pv_utilization",severity="warning"} 0
+rinc_alerts_firing{reporter="rabbitmq",severity="critical"} 0
+rinc_alerts_firing{reporter="rabbitmq",severity="info"} 0
+rinc_alerts_firing{reporter="rabbitmq",severity="warning"} 0
+# HELP rinc_ceph_health_status Whether the Ceph cluster has the health status, e.g., HEALTH_OK.
+# TYPE rinc_ceph_health_status gauge
+rinc_ceph_health_status{status="HEALTH_ERR"} 0
+rinc_ceph_health_status{status="HEALTH_OK"} 0
+rinc_ceph_health_status{status="HEALTH_WARN"} 1
+# HELP rinc_connectivity_reachable Whether the service was reachable, or healthy for metabase.
+# TYPE rinc_connectivity_reachable gauge
+rinc_connectivity_reachable{service="mongodb"} 0
+rinc_connectivity_reachable{service="vault"} 1
+# HELP rinc_dass_replicas_available Number of available replicas of the deployment or statefulset.
+# TYPE rinc_dass_replicas_available gauge
+rinc_dass_replicas_available{kind="deployment",name="api",namespace="accuknox"} 1
+# HELP rinc_dass_replicas_desired Number of desired replicas of the deployment or statefulset.
+# TYPE rinc_dass_replicas_desired gauge
+rinc_dass_replicas_desired{kind="deployment",name="api",namespace="accuknox"} 3
+# HELP rinc_dass_replicas_ready Number of ready replicas of the deployment or statefulset.
+# TYPE rinc_dass_replicas_ready gauge
+rinc_dass_replicas_ready{kind="deployment",name="api",namespace="accuknox"} 1
+# HELP rinc_pv_capacity_bytes Capacity of the persistent volume bound to the claim.
+# TYPE rinc_pv_capacity_bytes gauge
+rinc_pv_capacity_bytes{namespace="accuknox",pvc="data-mongodb-0"} 1000
+# HELP rinc_pv_used_bytes Used bytes of the persistent volume bound to the claim.
+# TYPE rinc_pv_used_bytes gauge
+rinc_pv_used_bytes{namespace="accuknox",pvc="data-mongodb-0"} 250
+# HELP rinc_pv_utilization_percent Utilization of the persistent volume bound to the claim in percent.
+# TYPE rinc_pv_utilization_percent gauge
+rinc_pv_utilization_percent{namespace="accuknox",pvc="data-mongodb-0"} 25
+# HELP rinc_rabbitmq_queue_messages Number of messages in the RabbitMQ queue.
+# TYPE rinc_rabbitmq_queue_messages gauge
+rinc_rabbitmq_queue_messages{queue="events",vhost="/"} 12
+# HELP rinc_rabbitmq_queue_messages_ready Number of messages in the RabbitMQ queue ready to be delivered.
+# TYPE rinc_rabbitmq_queue_messages_ready gauge
+rinc_rabbitmq_queue_messages_ready{queue="events",vhost="/"} 10
+# HELP rinc_rabbitmq_queue_messages_unacknowledged Number of messages in the RabbitMQ queue delivered but not yet acknowledged.
+# TYPE rinc_rabbitmq_queue_messages_unacknowledged gauge
+rinc_rabbitmq_queue_messages_unacknowledged{queue="events",vhost="/"} 2
+# HELP rinc_rabbitmq_up Whether the RabbitMQ cluster is up.
+# TYPE rinc_rabbitmq_up gauge
+rinc_rabbitmq_up 1
+# HELP rinc_report_timestamp_seconds Time of the latest report of the reporter.
+# TYPE rinc_report_timestamp_seconds gauge
+rinc_report_timestamp_seconds{reporter="ceph"} 1.7292528e+09
+rinc_report_timestamp_seconds{reporter="connectivity"} 1.7292528e+09
+rinc_report_timestamp_seconds{reporter="dass"} 1.7292528e+09
+rinc_report_timestamp_seconds{reporter="pv_utilization"} 1.7292528e+09
+rinc_report_timestamp_seconds{reporter="rabbitmq"} 1.7292528e+09
+`
+	a.NoError(testutil.CollectAndCompare(collector, strings.NewReader(want)))
+}
+
+func TestCollectorSourceError(t *testing.T) {
+	a := assert.New(t)
+	collector := exporter.NewCollector(
+		source{err: errors.New("mongodb unavailable")},
+		func() *conf.C { return &conf.C{} },
+	)
+	a.ErrorContains(
+		testutil.CollectAndCompare(collector, strings.NewReader("")),
+		"mongodb unavailable",
+	)
+}
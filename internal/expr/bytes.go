@@ -0,0 +1,68 @@
+package expr
+
+import (
+	"fmt"
+	"reflect"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// Bytes parses a byte size string such as "10Gi", "512Mi" or "1G" into the
+// number of bytes. It accepts the same suffixes as Kubernetes resource
+// quantities.
+func Bytes(x any) (int64, error) {
+	xval := reflect.ValueOf(x)
+	if xval.Kind() != reflect.String {
+		return 0, ErrUnexpectedKind[reflect.Kind]{
+			arg:  0,
+			want: reflect.String,
+			got:  xval.Kind(),
+		}
+	}
+	q, err := resource.ParseQuantity(xval.String())
+	if err != nil {
+		return 0, fmt.Errorf("parsing byte size %q: %w", xval.String(), err)
+	}
+	return q.Value(), nil
+}
+
+// HumanBytes formats a number of bytes into a human readable string using
+// binary (IEC) units, e.g., "1.5 GiB".
+func HumanBytes(x any) (string, error) {
+	var b float64
+	xval := reflect.ValueOf(x)
+	switch xval.Kind() {
+	case reflect.Int,
+		reflect.Int8,
+		reflect.Int16,
+		reflect.Int32,
+		reflect.Int64:
+		b = float64(xval.Int())
+	case reflect.Uint,
+		reflect.Uint8,
+		reflect.Uint16,
+		reflect.Uint32,
+		reflect.Uint64:
+		b = float64(xval.Uint())
+	case reflect.Float32, reflect.Float64:
+		b = xval.Float()
+	default:
+		return "", ErrUnexpectedKind[string]{
+			arg:  0,
+			want: "int|uint|float",
+			got:  xval.Kind().String(),
+		}
+	}
+
+	const unit = 1024
+	if b < unit && b > -unit {
+		return fmt.Sprintf("%.0f B", b), nil
+	}
+	units := []string{"KiB", "MiB", "GiB", "TiB", "PiB", "EiB"}
+	idx := -1
+	for (b >= unit || b <= -unit) && idx < len(units)-1 {
+		b /= unit
+		idx++
+	}
+	return fmt.Sprintf("%.1f %s", b, units[idx]), nil
+}
@@ -0,0 +1,87 @@
+package expr_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/accuknox/rinc/internal/expr"
+
+	"github.com/PaesslerAG/gval"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBytes(t *testing.T) {
+	a := assert.New(t)
+	inputs := map[any]int64{
+		"1024":  1024,
+		"1Ki":   1024,
+		"512Mi": 512 * 1024 * 1024,
+		"10Gi":  10 * 1024 * 1024 * 1024,
+		"1G":    1000 * 1000 * 1000,
+		"2T":    2 * 1000 * 1000 * 1000 * 1000,
+		"foo":   -1,
+		"10GB":  -1,
+		10:      -1,
+	}
+	for input, want := range inputs {
+		got, err := expr.Bytes(input)
+		if want == -1 {
+			a.Error(err, "x=%v", input)
+			continue
+		}
+		a.NoError(err, "x=%v", input)
+		a.Equal(want, got, "x=%v", input)
+	}
+}
+
+func TestHumanBytes(t *testing.T) {
+	a := assert.New(t)
+	inputs := []data{
+		{x: 0, value: "0 B"},
+		{x: 512, value: "512 B"},
+		{x: uint64(1536), value: "1.5 KiB"},
+		{x: int64(10 * 1024 * 1024 * 1024), value: "10.0 GiB"},
+		{x: float64(3 * 1024 * 1024), value: "3.0 MiB"},
+		{x: "foo", wantErr: true},
+	}
+	for _, i := range inputs {
+		got, err := expr.HumanBytes(i.x)
+		if i.wantErr {
+			a.Error(err, "x=%v", i.x)
+			continue
+		}
+		a.NoError(err, "x=%v", i.x)
+		a.Equal(i.value, got, "x=%v", i.x)
+	}
+}
+
+func TestBytesExpressions(t *testing.T) {
+	a := assert.New(t)
+	type stats struct {
+		TotalBytes     uint64
+		TotalUsedBytes uint64
+		Capacity       float64
+	}
+	data := stats{
+		TotalBytes:     100 * 1024 * 1024 * 1024,
+		TotalUsedBytes: 20 * 1024 * 1024 * 1024,
+		Capacity:       5 * 1024 * 1024 * 1024,
+	}
+	inputs := map[string]bool{
+		`TotalUsedBytes > bytes("10Gi")`:                        true,
+		`TotalUsedBytes > bytes("30Gi")`:                        false,
+		`Capacity < bytes("10Gi")`:                              true,
+		`humanBytes(TotalBytes) == "100.0 GiB"`:                 true,
+		`humanBytes(TotalBytes - TotalUsedBytes) == "80.0 GiB"`: true,
+	}
+	for input, want := range inputs {
+		got, err := gval.Full(expr.Full()...).EvaluateWithContext(
+			context.TODO(),
+			input,
+			data,
+		)
+		if a.NoError(err, "expr=%s", input) {
+			a.Equal(want, got, "expr=%s", input)
+		}
+	}
+}
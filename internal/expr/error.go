@@ -3,6 +3,8 @@ package expr
 import (
 	"fmt"
 	"reflect"
+	"strings"
+	"unicode/utf8"
 )
 
 type ErrUnexpectedKind[T string | reflect.Kind] struct {
@@ -16,10 +18,111 @@ func (e ErrUnexpectedKind[T]) Error() string {
 }
 
 type ErrFieldNotExist struct {
-	field string
-	on    string
+	field       string
+	on          string
+	suggestions []string
 }
 
 func (e ErrFieldNotExist) Error() string {
-	return fmt.Sprintf("field %q does not exist on %q", e.field, e.on)
+	msg := fmt.Sprintf("field %q does not exist on %q", e.field, e.on)
+	return msg + didYouMean(e.suggestions)
+}
+
+// ErrUnknownFunction is returned when an expression calls a function that
+// does not exist.
+type ErrUnknownFunction struct {
+	name        string
+	suggestions []string
+}
+
+func (e ErrUnknownFunction) Error() string {
+	return fmt.Sprintf("unknown function %q", e.name) + didYouMean(e.suggestions)
+}
+
+func didYouMean(suggestions []string) string {
+	switch len(suggestions) {
+	case 0:
+		return ""
+	case 1:
+		return fmt.Sprintf(", did you mean %q?", suggestions[0])
+	default:
+		quoted := make([]string, len(suggestions))
+		for idx, s := range suggestions {
+			quoted[idx] = fmt.Sprintf("%q", s)
+		}
+		return fmt.Sprintf(", did you mean one of %s?", strings.Join(quoted, ", "))
+	}
+}
+
+// Span is the byte range [Start, End) of a part of an expression.
+type Span struct {
+	Start int
+	End   int
+}
+
+// Error is returned when parsing or evaluating an expression fails. It
+// points to the part of the expression that caused the failure.
+type Error struct {
+	// Expr is the complete expression text.
+	Expr string
+	// Span marks the part of Expr that failed.
+	Span Span
+	// Path is the field path that was evaluated before the failure, e.g.,
+	// "Status.OSDMap". It is empty if the failure is not related to a field
+	// access.
+	Path string
+	// Err is the underlying error.
+	Err error
+}
+
+func (e *Error) Error() string {
+	line, col := e.Position()
+	return fmt.Sprintf("%d:%d: %q: %s", line, col, e.Text(), e.Err)
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Text returns the part of the expression that failed.
+func (e *Error) Text() string {
+	start := min(max(e.Span.Start, 0), len(e.Expr))
+	end := min(max(e.Span.End, start), len(e.Expr))
+	return e.Expr[start:end]
+}
+
+// Position returns the 1-based line and column of the start of the failing
+// part of the expression.
+func (e *Error) Position() (line, col int) {
+	start := min(max(e.Span.Start, 0), len(e.Expr))
+	before := e.Expr[:start]
+	line = strings.Count(before, "\n") + 1
+	if idx := strings.LastIndex(before, "\n"); idx >= 0 {
+		before = before[idx+1:]
+	}
+	return line, utf8.RuneCountInString(before) + 1
+}
+
+// Snippet returns the line of the expression that contains the failure with
+// the failing part underlined, e.g.,
+//
+//	sumUint(Status.OSDMap.OSD, "In") > 0
+//	        ^^^^^^^^^^^^^^^^^
+func (e *Error) Snippet() string {
+	start := min(max(e.Span.Start, 0), len(e.Expr))
+	end := min(max(e.Span.End, start), len(e.Expr))
+
+	lineStart := strings.LastIndex(e.Expr[:start], "\n") + 1
+	lineEnd := strings.Index(e.Expr[start:], "\n")
+	if lineEnd < 0 {
+		lineEnd = len(e.Expr)
+	} else {
+		lineEnd += start
+	}
+	end = min(end, lineEnd)
+
+	pad := utf8.RuneCountInString(e.Expr[lineStart:start])
+	width := max(utf8.RuneCountInString(e.Expr[start:end]), 1)
+	return e.Expr[lineStart:lineEnd] + "\n" +
+		strings.Repeat(" ", pad) + strings.Repeat("^", width)
 }
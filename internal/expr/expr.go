@@ -12,28 +12,61 @@ import (
 	"github.com/PaesslerAG/gval"
 )
 
+// Full returns the custom functions and operators of the expression
+// language as gval language extensions.
 func Full() []gval.Language {
+	langs := operators()
+	for name, fn := range functions() {
+		langs = append(langs, gval.Function(name, fn))
+	}
+	return langs
+}
+
+// functions returns the custom functions of the expression language by name.
+func functions() map[string]any {
+	return map[string]any{
+		"has":           Has,
+		"len":           Len,
+		"fieldsEq":      FieldsEq,
+		"sumInt":        Sum[int],
+		"sumInt8":       Sum[int8],
+		"sumInt16":      Sum[int16],
+		"sumInt32":      Sum[int32],
+		"sumInt64":      Sum[int64],
+		"sumUint":       Sum[uint],
+		"sumUint8":      Sum[uint8],
+		"sumUint16":     Sum[uint16],
+		"sumUint32":     Sum[uint32],
+		"sumUint64":     Sum[uint64],
+		"sumFloat32":    Sum[float32],
+		"sumFloat64":    Sum[float64],
+		"findOne":       FindOne,
+		"findMany":      FindMany,
+		"findOneRegex":  FindOneRegex,
+		"findManyRegex": FindManyRegex,
+		"evalOnEach":    EvalOnEach,
+		"duration":      Duration,
+		"now":           now,
+		"since":         since,
+		"humanDuration": HumanDuration,
+		"bytes":         Bytes,
+		"humanBytes":    HumanBytes,
+		"startsWith":    StartsWith,
+		"endsWith":      EndsWith,
+		"matches":       Matches,
+		"split":         Split,
+		"lower":         Lower,
+		"upper":         Upper,
+		"join":          Join,
+		"sprintf":       Sprintf,
+		"imageRepo":     ImageRepo,
+		"imageTag":      ImageTag,
+	}
+}
+
+// operators returns the custom operators of the expression language.
+func operators() []gval.Language {
 	return []gval.Language{
-		gval.Function("has", Has),
-		gval.Function("len", Len),
-		gval.Function("fieldsEq", FieldsEq),
-		gval.Function("sumInt", Sum[int]),
-		gval.Function("sumInt8", Sum[int8]),
-		gval.Function("sumInt16", Sum[int16]),
-		gval.Function("sumInt32", Sum[int32]),
-		gval.Function("sumInt64", Sum[int64]),
-		gval.Function("sumUint", Sum[uint]),
-		gval.Function("sumUint8", Sum[uint8]),
-		gval.Function("sumUint16", Sum[uint16]),
-		gval.Function("sumUint32", Sum[uint32]),
-		gval.Function("sumUint64", Sum[uint64]),
-		gval.Function("sumFloat32", Sum[float32]),
-		gval.Function("sumFloat64", Sum[float64]),
-		gval.Function("findOne", FindOne),
-		gval.Function("findMany", FindMany),
-		gval.Function("findOneRegex", FindOneRegex),
-		gval.Function("findManyRegex", FindManyRegex),
-		gval.Function("evalOnEach", EvalOnEach),
 		gval.InfixOperator("->", AccessOp),
 		gval.InfixOperator("~>", AccessSpreadOp),
 		gval.PostfixOperator("|", pipeOp),
@@ -109,22 +142,12 @@ func FieldsEq(list any, field string, value any) (bool, error) {
 		}
 	}
 	for idx := 0; idx < rlist.Len(); idx++ {
-		item := reflect.ValueOf(rlist.Index(idx).Interface())
-		if item.Kind() == reflect.Ptr {
-			item = item.Elem()
-		}
-		if item.Kind() != reflect.Struct {
-			return false, ErrUnexpectedKind[reflect.Kind]{
-				arg:  "list[] -> item",
-				want: reflect.Struct,
-				got:  item.Kind(),
-			}
-		}
-		fval := item.FieldByName(field)
-		if !fval.IsValid() {
-			return false, fmt.Errorf("field %q does not exist", field)
+		fval, err := fieldByPath(rlist.Index(idx), field, "list(arg 0)[]")
+		if err != nil {
+			return false, err
 		}
-		if !reflect.DeepEqual(fval.Interface(), value) {
+		fval = indirect(fval)
+		if !reflect.DeepEqual(valueOf(fval), value) {
 			return false, nil
 		}
 	}
@@ -171,24 +194,12 @@ func Find(list any, field string, value any, opts *FindOpts) (any, error) {
 		if opts.One && len(matches) > 0 {
 			break
 		}
-		item := reflect.ValueOf(rlist.Index(idx).Interface())
-		if item.Kind() == reflect.Ptr {
-			item = item.Elem()
-		}
-		if item.Kind() != reflect.Struct {
-			return nil, ErrUnexpectedKind[reflect.Kind]{
-				arg:  "list[] -> item",
-				want: reflect.Struct,
-				got:  item.Kind(),
-			}
-		}
-		fval := item.FieldByName(field)
-		if !fval.IsValid() {
-			return nil, ErrFieldNotExist{
-				field: field,
-				on:    "list(arg 0)",
-			}
+		item := indirect(rlist.Index(idx))
+		fval, err := fieldByPath(item, field, "list(arg 0)[]")
+		if err != nil {
+			return nil, err
 		}
+		fval = indirect(fval)
 		if opts.MatchAsStr {
 			if fval.Kind() != reflect.String {
 				return nil, ErrUnexpectedKind[reflect.Kind]{
@@ -216,7 +227,7 @@ func Find(list any, field string, value any, opts *FindOpts) (any, error) {
 			matches = append(matches, item.Interface())
 			continue
 		}
-		if reflect.DeepEqual(fval.Interface(), value) {
+		if reflect.DeepEqual(valueOf(fval), value) {
 			matches = append(matches, item.Interface())
 		}
 	}
@@ -244,18 +255,15 @@ func EvalOnEach(list any, expr, ret string) (any, error) {
 	var postivies []any
 
 	for idx := 0; idx < rlist.Len(); idx++ {
-		item := reflect.ValueOf(rlist.Index(idx).Interface())
-		if item.Kind() == reflect.Ptr {
-			item = item.Elem()
-		}
-		if item.Kind() != reflect.Struct {
-			return nil, ErrUnexpectedKind[reflect.Kind]{
+		item := indirect(rlist.Index(idx))
+		if item.Kind() != reflect.Struct && item.Kind() != reflect.Map {
+			return nil, ErrUnexpectedKind[string]{
 				arg:  "list[] -> item",
-				want: reflect.Struct,
-				got:  item.Kind(),
+				want: fmt.Sprintf("%s|%s", reflect.Struct, reflect.Map),
+				got:  item.Kind().String(),
 			}
 		}
-		ev, err := gval.Full(Full()...).NewEvaluable(expr)
+		ev, err := Parse(expr)
 		if err != nil {
 			return nil, fmt.Errorf("parsing expression %q: %w", expr, err)
 		}
@@ -266,14 +274,11 @@ func EvalOnEach(list any, expr, ret string) (any, error) {
 		if !isTrue {
 			continue
 		}
-		fval := item.FieldByName(ret)
-		if !fval.IsValid() {
-			return nil, ErrFieldNotExist{
-				field: ret,
-				on:    "list(arg 0)",
-			}
+		fval, err := fieldByPath(item, ret, "list(arg 0)[]")
+		if err != nil {
+			return nil, err
 		}
-		postivies = append(postivies, fval.Interface())
+		postivies = append(postivies, valueOf(fval))
 	}
 
 	return postivies, nil
@@ -292,24 +297,11 @@ func Sum[T types.Number](list any, field string) (T, error) {
 	}
 
 	for idx := 0; idx < rlist.Len(); idx++ {
-		item := reflect.ValueOf(rlist.Index(idx).Interface())
-		if item.Kind() == reflect.Ptr {
-			item = item.Elem()
-		}
-		if item.Kind() != reflect.Struct {
-			return sum, ErrUnexpectedKind[reflect.Kind]{
-				arg:  "list[] -> item",
-				want: reflect.Struct,
-				got:  item.Kind(),
-			}
-		}
-		fval := item.FieldByName(field)
-		if !fval.IsValid() {
-			return sum, ErrFieldNotExist{
-				field: field,
-				on:    "list(arg 0)",
-			}
+		fval, err := fieldByPath(rlist.Index(idx), field, "list(arg 0)[]")
+		if err != nil {
+			return sum, err
 		}
+		fval = indirect(fval)
 		if fval.Kind() != reflect.TypeOf(sum).Kind() {
 			return sum, ErrUnexpectedKind[reflect.Kind]{
 				arg:  fmt.Sprintf("list[] -> item -> %s(field)", field),
@@ -349,49 +341,30 @@ func AccessOp(x, y any) (any, error) {
 	}
 	field := yval.String()
 
-	xval := reflect.ValueOf(x)
-	if xval.Kind() == reflect.Ptr {
-		xval = xval.Elem()
-	}
+	xval := indirect(reflect.ValueOf(x))
 	switch xval.Kind() {
-	case reflect.Struct:
-		fval := xval.FieldByName(field)
-		if !fval.IsValid() {
-			return nil, ErrFieldNotExist{
-				field: field,
-				on:    fmt.Sprintf("%s(arg 0)", xval.Kind().String()),
-			}
+	case reflect.Struct, reflect.Map:
+		fval, err := fieldByPath(xval, field, "arg 0")
+		if err != nil {
+			return nil, err
 		}
-		return fval.Interface(), nil
+		return valueOf(fval), nil
 	case reflect.Array, reflect.Slice:
 		var items []any
 		for idx := 0; idx < xval.Len(); idx++ {
-			item := reflect.ValueOf(xval.Index(idx).Interface())
-			if item.Kind() == reflect.Ptr {
-				item = item.Elem()
-			}
-			if item.Kind() != reflect.Struct {
-				return nil, ErrUnexpectedKind[reflect.Kind]{
-					arg:  "list[](args 0) -> item",
-					want: reflect.Struct,
-					got:  item.Kind(),
-				}
-			}
-			fval := item.FieldByName(field)
-			if !fval.IsValid() {
-				return nil, ErrFieldNotExist{
-					field: field,
-					on:    "list[](arg 0) -> item",
-				}
+			fval, err := fieldByPath(xval.Index(idx), field, "list(arg 0)[]")
+			if err != nil {
+				return nil, err
 			}
-			items = append(items, fval.Interface())
+			items = append(items, valueOf(fval))
 		}
 		return items, nil
 	default:
 		return nil, ErrUnexpectedKind[string]{
 			arg: 0,
-			want: fmt.Sprintf("%s|%s|%s",
+			want: fmt.Sprintf("%s|%s|%s|%s",
 				reflect.Struct,
+				reflect.Map,
 				reflect.Slice,
 				reflect.Array,
 			),
@@ -411,41 +384,22 @@ func AccessSpreadOp(x, y any) (any, error) {
 	}
 	field := yval.String()
 
-	xval := reflect.ValueOf(x)
-	if xval.Kind() == reflect.Ptr {
-		xval = xval.Elem()
-	}
+	xval := indirect(reflect.ValueOf(x))
 	switch xval.Kind() {
-	case reflect.Struct:
-		fval := xval.FieldByName(field)
-		if !fval.IsValid() {
-			return nil, ErrFieldNotExist{
-				field: field,
-				on:    fmt.Sprintf("%s(arg 0)", xval.Kind().String()),
-			}
+	case reflect.Struct, reflect.Map:
+		fval, err := fieldByPath(xval, field, "arg 0")
+		if err != nil {
+			return nil, err
 		}
-		return fval.Interface(), nil
+		return valueOf(fval), nil
 	case reflect.Array, reflect.Slice:
 		var items []any
 		for idx := 0; idx < xval.Len(); idx++ {
-			item := reflect.ValueOf(xval.Index(idx).Interface())
-			if item.Kind() == reflect.Ptr {
-				item = item.Elem()
-			}
-			if item.Kind() != reflect.Struct {
-				return nil, ErrUnexpectedKind[reflect.Kind]{
-					arg:  "list[](args 0) -> item",
-					want: reflect.Struct,
-					got:  item.Kind(),
-				}
-			}
-			fval := item.FieldByName(field)
-			if !fval.IsValid() {
-				return nil, ErrFieldNotExist{
-					field: field,
-					on:    "list[](arg 0) -> item",
-				}
+			fval, err := fieldByPath(xval.Index(idx), field, "list(arg 0)[]")
+			if err != nil {
+				return nil, err
 			}
+			fval = indirect(fval)
 			if fval.Kind() == reflect.Slice || fval.Kind() == reflect.Array {
 				for idx := 0; idx < fval.Len(); idx++ {
 					val := fval.Index(idx).Interface()
@@ -453,14 +407,15 @@ func AccessSpreadOp(x, y any) (any, error) {
 				}
 				continue
 			}
-			items = append(items, fval.Interface())
+			items = append(items, valueOf(fval))
 		}
 		return items, nil
 	default:
 		return nil, ErrUnexpectedKind[string]{
 			arg: 0,
-			want: fmt.Sprintf("%s|%s|%s",
+			want: fmt.Sprintf("%s|%s|%s|%s",
 				reflect.Struct,
+				reflect.Map,
 				reflect.Slice,
 				reflect.Array,
 			),
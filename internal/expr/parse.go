@@ -0,0 +1,394 @@
+package expr
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"text/scanner"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/PaesslerAG/gval"
+)
+
+// Parse parses text into an evaluable using the full expression language.
+// Errors returned while parsing and evaluating the expression are of type
+// *Error and point to the part of text that failed.
+func Parse(text string) (gval.Evaluable, error) {
+	src := newSource(text)
+	lang := gval.Full(append(operators(), src.language())...)
+	ev, err := lang.NewEvaluable(text)
+	if err != nil {
+		return nil, src.parseError(err)
+	}
+	return src.wrap(ev, Span{Start: 0, End: len(text)}), nil
+}
+
+// source keeps track of where the operands of an expression are located in
+// its text while the expression is being parsed.
+type source struct {
+	text   string
+	tokens []token
+	// heads maps the name of an identifier to the indices of the tokens at
+	// which it starts an operand, i.e., a variable or a function call.
+	heads map[string][]int
+	// seen counts how many times an identifier has been parsed so far.
+	seen map[string]int
+}
+
+type token struct {
+	tok  rune
+	text string
+	span Span
+}
+
+func newSource(text string) *source {
+	// keep in sync with the gval parser's scanner configuration
+	var sc scanner.Scanner
+	sc.Init(strings.NewReader(text))
+	sc.Error = func(*scanner.Scanner, string) {}
+	sc.Mode = scanner.GoTokens
+	sc.IsIdentRune = func(r rune, pos int) bool {
+		return unicode.IsLetter(r) || r == '_' || (pos > 0 && unicode.IsDigit(r))
+	}
+
+	s := &source{
+		text:  text,
+		heads: make(map[string][]int),
+		seen:  make(map[string]int),
+	}
+	for tok := sc.Scan(); tok != scanner.EOF; tok = sc.Scan() {
+		t := token{
+			tok:  tok,
+			text: sc.TokenText(),
+			span: Span{
+				Start: sc.Position.Offset,
+				End:   sc.Position.Offset + len(sc.TokenText()),
+			},
+		}
+		isField := len(s.tokens) != 0 && s.tokens[len(s.tokens)-1].tok == '.'
+		if tok == scanner.Ident && !isField {
+			s.heads[t.text] = append(s.heads[t.text], len(s.tokens))
+		}
+		s.tokens = append(s.tokens, t)
+	}
+	return s
+}
+
+// language returns the gval language extension that parses identifiers, i.e.,
+// variables and function calls, and attaches their location to any error
+// they return.
+func (s *source) language() gval.Language {
+	return gval.PrefixMetaPrefix(scanner.Ident, s.parseIdent)
+}
+
+func (s *source) parseIdent(c context.Context, p *gval.Parser) (string, func() (gval.Evaluable, error), error) {
+	name := p.TokenText()
+	span := s.operandSpan(s.next(name))
+	return name, func() (gval.Evaluable, error) {
+		if fn, ok := functions()[name]; ok {
+			return s.parseCall(c, p, fn, span)
+		}
+		return s.parseVar(c, p, name, span)
+	}, nil
+}
+
+func (s *source) parseCall(c context.Context, p *gval.Parser, fn any, span Span) (gval.Evaluable, error) {
+	var args []gval.Evaluable
+	if p.Scan() == '(' {
+		var err error
+		args, err = parseArguments(c, p)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		p.Camouflage("function call", '(')
+	}
+	return s.wrap(call(fn, args), span), nil
+}
+
+func (s *source) parseVar(c context.Context, p *gval.Parser, name string, span Span) (gval.Evaluable, error) {
+	keys := gval.Evaluables{p.Const(name)}
+	for {
+		switch p.Scan() {
+		case '.':
+			if p.Scan() != scanner.Ident {
+				return nil, p.Expected("field", scanner.Ident)
+			}
+			name += "." + p.TokenText()
+			keys = append(keys, p.Const(p.TokenText()))
+		case '[':
+			key, err := p.ParseExpression(c)
+			if err != nil {
+				return nil, err
+			}
+			if p.Scan() != ']' {
+				return nil, p.Expected("array key", ']')
+			}
+			keys = append(keys, key)
+		case '(':
+			return nil, &Error{
+				Expr: s.text,
+				Span: span,
+				Err: ErrUnknownFunction{
+					name:        name,
+					suggestions: suggest(name, functionNames()),
+				},
+			}
+		default:
+			p.Camouflage("variable", '.', '(', '[')
+			return s.wrap(variable(keys), span), nil
+		}
+	}
+}
+
+// next returns the index of the token at which the next occurrence of the
+// identifier name starts, or -1 if it can not be found.
+func (s *source) next(name string) int {
+	idx := s.seen[name]
+	s.seen[name]++
+	if idx >= len(s.heads[name]) {
+		return -1
+	}
+	return s.heads[name][idx]
+}
+
+// operandSpan returns the span of the operand starting at the token with the
+// given index, including any field accesses, indices and call arguments.
+func (s *source) operandSpan(idx int) Span {
+	if idx < 0 || idx >= len(s.tokens) {
+		return Span{Start: 0, End: len(s.text)}
+	}
+	end := idx
+Extend:
+	for end+1 < len(s.tokens) {
+		switch s.tokens[end+1].tok {
+		case '.':
+			if end+2 < len(s.tokens) && s.tokens[end+2].tok == scanner.Ident {
+				end += 2
+				continue
+			}
+		case '(', '[':
+			if closing := s.closing(end + 1); closing > 0 {
+				end = closing
+				continue
+			}
+		}
+		break Extend
+	}
+	return Span{Start: s.tokens[idx].span.Start, End: s.tokens[end].span.End}
+}
+
+// closing returns the index of the token closing the bracket opened at the
+// token with the given index, or -1 if the bracket is never closed.
+func (s *source) closing(idx int) int {
+	depth := 0
+	for i := idx; i < len(s.tokens); i++ {
+		switch s.tokens[i].tok {
+		case '(', '[', '{':
+			depth++
+		case ')', ']', '}':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// wrap turns errors returned by ev into an *Error pointing at span, unless
+// they already point to a more precise part of the expression.
+func (s *source) wrap(ev gval.Evaluable, span Span) gval.Evaluable {
+	return func(c context.Context, v any) (any, error) {
+		res, err := ev(c, v)
+		if err != nil {
+			return nil, s.errorAt(span, err)
+		}
+		return res, nil
+	}
+}
+
+func (s *source) errorAt(span Span, err error) error {
+	var e *Error
+	if errors.As(err, &e) && e.Expr == s.text {
+		return err
+	}
+	var path string
+	var fieldErr ErrFieldNotExist
+	if errors.As(err, &fieldErr) && fieldErr.on != "." {
+		path = fieldErr.on
+	}
+	return &Error{
+		Expr: s.text,
+		Span: span,
+		Path: path,
+		Err:  err,
+	}
+}
+
+// parseError converts an error returned by the gval parser into an *Error.
+// The gval parser reports the position of the offending token in the error
+// message as "parsing error: <expr>\t:<line>:<col> - <line>:<col> <err>".
+func (s *source) parseError(err error) error {
+	var e *Error
+	if errors.As(err, &e) {
+		return e
+	}
+
+	span := Span{Start: 0, End: len(s.text)}
+	prefix := fmt.Sprintf("parsing error: %s\t:", s.text)
+	if msg := err.Error(); strings.HasPrefix(msg, prefix) {
+		var l1, c1, l2, c2 int
+		_, scanErr := fmt.Sscanf(msg[len(prefix):], "%d:%d - %d:%d", &l1, &c1, &l2, &c2)
+		if scanErr == nil {
+			span = Span{Start: s.offset(l1, c1), End: s.offset(l2, c2)}
+		}
+	}
+
+	inner := errors.Unwrap(err)
+	if inner == nil {
+		inner = err
+	}
+	return &Error{
+		Expr: s.text,
+		Span: span,
+		Err:  inner,
+	}
+}
+
+// offset converts a 1-based line and column into a byte offset.
+func (s *source) offset(line, col int) int {
+	off := 0
+	for l := 1; l < line; l++ {
+		idx := strings.IndexByte(s.text[off:], '\n')
+		if idx < 0 {
+			return len(s.text)
+		}
+		off += idx + 1
+	}
+	for c := 1; c < col && off < len(s.text); c++ {
+		if s.text[off] == '\n' {
+			break
+		}
+		_, size := utf8.DecodeRuneInString(s.text[off:])
+		off += size
+	}
+	return off
+}
+
+func parseArguments(c context.Context, p *gval.Parser) ([]gval.Evaluable, error) {
+	if p.Scan() == ')' {
+		return nil, nil
+	}
+	p.Camouflage("scan arguments", ')')
+	var args []gval.Evaluable
+	for {
+		arg, err := p.ParseExpression(c)
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, arg)
+		switch p.Scan() {
+		case ')':
+			return args, nil
+		case ',':
+		default:
+			return nil, p.Expected("arguments", ')', ',')
+		}
+	}
+}
+
+// variable returns an evaluable that resolves the path made up of keys on
+// the parameter it is evaluated with.
+func variable(keys gval.Evaluables) gval.Evaluable {
+	return func(c context.Context, v any) (any, error) {
+		path, err := keys.EvalStrings(c, v)
+		if err != nil {
+			return nil, err
+		}
+		fval, err := walk(reflect.ValueOf(v), path, "")
+		if err != nil {
+			return nil, err
+		}
+		return valueOf(fval), nil
+	}
+}
+
+var contextType = reflect.TypeOf((*context.Context)(nil)).Elem()
+
+// call returns an evaluable that calls fn with the results of args.
+func call(fn any, args []gval.Evaluable) gval.Evaluable {
+	fval := reflect.ValueOf(fn)
+	ftyp := fval.Type()
+	return func(c context.Context, v any) (ret any, err error) {
+		in := make([]reflect.Value, 0, len(args)+1)
+		if ftyp.NumIn() > 0 && ftyp.In(0) == contextType {
+			in = append(in, reflect.ValueOf(c))
+		}
+		nargs := len(args) + len(in)
+		if (!ftyp.IsVariadic() && nargs != ftyp.NumIn()) ||
+			(ftyp.IsVariadic() && nargs < ftyp.NumIn()-1) {
+			return nil, fmt.Errorf("want %d arguments, got %d", ftyp.NumIn()-len(in), len(args))
+		}
+
+		for idx, arg := range args {
+			res, err := arg(c, v)
+			if err != nil {
+				return nil, err
+			}
+			pos := len(in)
+			var want reflect.Type
+			if ftyp.IsVariadic() && pos >= ftyp.NumIn()-1 {
+				want = ftyp.In(ftyp.NumIn() - 1).Elem()
+			} else {
+				want = ftyp.In(pos)
+			}
+			if res == nil {
+				in = append(in, reflect.Zero(want))
+				continue
+			}
+			rval := reflect.ValueOf(res)
+			if !rval.Type().AssignableTo(want) {
+				return nil, ErrUnexpectedKind[string]{
+					arg:  idx,
+					want: want.String(),
+					got:  rval.Type().String(),
+				}
+			}
+			in = append(in, rval)
+		}
+
+		defer func() {
+			if r := recover(); r != nil {
+				ret, err = nil, fmt.Errorf("%v", r)
+			}
+		}()
+		out := fval.Call(in)
+		if n := len(out); n > 0 && ftyp.Out(n-1) == errorType {
+			if errVal := out[n-1].Interface(); errVal != nil {
+				return nil, errVal.(error)
+			}
+			out = out[:n-1]
+		}
+		if len(out) == 0 {
+			return nil, nil
+		}
+		return out[0].Interface(), nil
+	}
+}
+
+var errorType = reflect.TypeOf((*error)(nil)).Elem()
+
+// functionNames returns the sorted names of all custom functions.
+func functionNames() []string {
+	var names []string
+	for name := range functions() {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
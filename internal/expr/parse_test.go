@@ -0,0 +1,144 @@
+package expr_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/accuknox/rinc/internal/expr"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type osd struct {
+	Up uint
+	In uint
+}
+
+type osdMap struct {
+	OSDs []osd
+}
+
+type cephStatus struct {
+	OSDMap osdMap
+	Hosts  uint
+}
+
+type cephMetrics struct {
+	Status cephStatus
+	Name   string
+}
+
+func TestParseErrors(t *testing.T) {
+	a := assert.New(t)
+	metrics := cephMetrics{
+		Status: cephStatus{
+			OSDMap: osdMap{OSDs: []osd{{Up: 1, In: 1}}},
+			Hosts:  3,
+		},
+	}
+	inputs := []struct {
+		expr     string
+		text     string
+		line     int
+		col      int
+		path     string
+		contains string
+	}{
+		{
+			expr:     `sumUint(Status.OSDMap.OSD, "In") > 0`,
+			text:     "Status.OSDMap.OSD",
+			line:     1,
+			col:      9,
+			path:     "Status.OSDMap",
+			contains: `did you mean "OSDs"?`,
+		},
+		{
+			expr:     `len(Status.OSDMap.OSDs) > 0 && len(Status.Hosts) > 0`,
+			text:     "len(Status.Hosts)",
+			line:     1,
+			col:      32,
+			contains: `want kind of arg 0`,
+		},
+		{
+			expr:     "Name == \"foo\" ||\n  sumUint(Status.OSDMap.OSDs, \"Upp\") > 0",
+			text:     `sumUint(Status.OSDMap.OSDs, "Upp")`,
+			line:     2,
+			col:      3,
+			path:     "list(arg 0)[]",
+			contains: `did you mean "Up"?`,
+		},
+		{
+			expr:     `findone(Status.OSDMap.OSDs, "Up", 1) != nil`,
+			text:     `findone(Status.OSDMap.OSDs, "Up", 1)`,
+			line:     1,
+			col:      1,
+			contains: `unknown function "findone", did you mean one of "findOne"`,
+		},
+		{
+			expr: `Status.Hosts < )`,
+			text: ")",
+			line: 1,
+			col:  16,
+		},
+	}
+	for _, i := range inputs {
+		ev, err := expr.Parse(i.expr)
+		if err == nil {
+			_, err = ev(context.TODO(), metrics)
+		}
+		var exprErr *expr.Error
+		if !a.True(errors.As(err, &exprErr), "expr=%s err=%v", i.expr, err) {
+			continue
+		}
+		line, col := exprErr.Position()
+		a.Equal(i.text, exprErr.Text(), "expr=%s", i.expr)
+		a.Equal(i.line, line, "expr=%s", i.expr)
+		a.Equal(i.col, col, "expr=%s", i.expr)
+		a.Equal(i.path, exprErr.Path, "expr=%s", i.expr)
+		a.Contains(exprErr.Error(), i.contains, "expr=%s", i.expr)
+	}
+}
+
+func TestParse(t *testing.T) {
+	a := assert.New(t)
+	metrics := cephMetrics{
+		Status: cephStatus{
+			OSDMap: osdMap{OSDs: []osd{{Up: 1, In: 1}, {Up: 1, In: 0}}},
+			Hosts:  3,
+		},
+		Name: "foo",
+	}
+	inputs := map[string]any{
+		`sumUint(Status.OSDMap.OSDs, "In") != len(Status.OSDMap.OSDs)`: true,
+		`Status.Hosts > 2 && Name == "foo"`:                            true,
+		`len(findMany(Status.OSDMap.OSDs, "In", uint(1)))`:             nil,
+		`Status.OSDMap.OSDs[1].In == 0`:                                true,
+		`{"x": Status.OSDMap} | len(x -> "OSDs")`:                      2,
+		`date("2024-10-18T12:00:00Z") != false`:                        true,
+	}
+	for input, want := range inputs {
+		ev, err := expr.Parse(input)
+		if want == nil {
+			// unknown function
+			a.Error(err, "expr=%s", input)
+			continue
+		}
+		if !a.NoError(err, "expr=%s", input) {
+			continue
+		}
+		got, err := ev(context.TODO(), metrics)
+		if a.NoError(err, "expr=%s", input) {
+			a.Equal(want, got, "expr=%s", input)
+		}
+	}
+}
+
+func TestErrorSnippet(t *testing.T) {
+	a := assert.New(t)
+	err := &expr.Error{
+		Expr: "Name == \"foo\" ||\n  len(Status.Foo) > 0",
+		Span: expr.Span{Start: 23, End: 33},
+	}
+	a.Equal("  len(Status.Foo) > 0\n      ^^^^^^^^^^", err.Snippet())
+}
@@ -0,0 +1,168 @@
+package expr
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// FieldByPath walks v along a dotted path, e.g., "Queue.Name", and returns
+// the value found at the end of it. Each path segment is resolved depending
+// on the kind of the current value:
+//
+//   - struct: the segment is the name of a field.
+//   - map: the segment is a key. Missing keys resolve to the zero value of the
+//     map's value type. Since keys may contain dots, e.g.,
+//     "app.kubernetes.io/name", the remaining path is tried as a single key
+//     first.
+//   - slice/array: a numeric segment indexes the list, otherwise the rest of
+//     the path is resolved on each item and a list of results is returned.
+//
+// Pointers and interfaces are dereferenced at any depth.
+func FieldByPath(v any, path string) (any, error) {
+	fval, err := fieldByPath(reflect.ValueOf(v), path, "")
+	if err != nil {
+		return nil, err
+	}
+	return valueOf(fval), nil
+}
+
+func fieldByPath(v reflect.Value, path, on string) (reflect.Value, error) {
+	return walk(v, strings.Split(path, "."), on)
+}
+
+// walk resolves the path segments on v one by one. `on` is the path that was
+// already evaluated before reaching v and is only used in errors.
+func walk(v reflect.Value, segments []string, on string) (reflect.Value, error) {
+	for idx := 0; idx < len(segments); idx++ {
+		seg := segments[idx]
+		v = indirect(v)
+		if !v.IsValid() {
+			return reflect.Value{}, ErrFieldNotExist{
+				field: seg,
+				on:    fmt.Sprintf("%s(nil)", pathOn(on)),
+			}
+		}
+
+		switch v.Kind() {
+		case reflect.Struct:
+			sf, ok := v.Type().FieldByName(seg)
+			if !ok || !sf.IsExported() {
+				return reflect.Value{}, ErrFieldNotExist{
+					field:       seg,
+					on:          pathOn(on),
+					suggestions: suggest(seg, fieldNames(v.Type())),
+				}
+			}
+			fval, err := v.FieldByIndexErr(sf.Index)
+			if err != nil {
+				return reflect.Value{}, fmt.Errorf("accessing field %q on %q: %w",
+					seg, pathOn(on), err)
+			}
+			v = fval
+		case reflect.Map:
+			ktyp := v.Type().Key()
+			if ktyp.Kind() != reflect.String {
+				return reflect.Value{}, ErrUnexpectedKind[reflect.Kind]{
+					arg:  fmt.Sprintf("%s -> key", pathOn(on)),
+					want: reflect.String,
+					got:  ktyp.Kind(),
+				}
+			}
+			rest := strings.Join(segments[idx:], ".")
+			if mval := v.MapIndex(reflect.ValueOf(rest).Convert(ktyp)); mval.IsValid() {
+				return mval, nil
+			}
+			mval := v.MapIndex(reflect.ValueOf(seg).Convert(ktyp))
+			if !mval.IsValid() {
+				mval = reflect.Zero(v.Type().Elem())
+			}
+			v = mval
+		case reflect.Slice, reflect.Array:
+			if i, err := strconv.Atoi(seg); err == nil {
+				if i < 0 || i >= v.Len() {
+					return reflect.Value{}, fmt.Errorf(
+						"index %d out of range on %q with length %d",
+						i, pathOn(on), v.Len())
+				}
+				v = v.Index(i)
+				break
+			}
+			items := make([]any, 0, v.Len())
+			for i := 0; i < v.Len(); i++ {
+				item, err := walk(v.Index(i), segments[idx:], joinPath(on, "[]"))
+				if err != nil {
+					return reflect.Value{}, err
+				}
+				if !item.IsValid() {
+					items = append(items, nil)
+					continue
+				}
+				items = append(items, item.Interface())
+			}
+			return reflect.ValueOf(items), nil
+		default:
+			return reflect.Value{}, ErrUnexpectedKind[string]{
+				arg: pathOn(on),
+				want: fmt.Sprintf("%s|%s|%s|%s",
+					reflect.Struct,
+					reflect.Map,
+					reflect.Slice,
+					reflect.Array,
+				),
+				got: v.Kind().String(),
+			}
+		}
+		on = joinPath(on, seg)
+	}
+	return v, nil
+}
+
+// fieldNames returns the names of the exported fields of the struct type t.
+func fieldNames(t reflect.Type) []string {
+	var names []string
+	for _, f := range reflect.VisibleFields(t) {
+		if f.IsExported() && !f.Anonymous {
+			names = append(names, f.Name)
+		}
+	}
+	return names
+}
+
+// valueOf returns the underlying value of v, or nil if v is invalid.
+func valueOf(v reflect.Value) any {
+	if !v.IsValid() {
+		return nil
+	}
+	return v.Interface()
+}
+
+// indirect dereferences pointers and interfaces until it reaches a concrete
+// value. It returns the zero reflect.Value if a nil pointer is encountered.
+func indirect(v reflect.Value) reflect.Value {
+	for v.IsValid() && (v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface) {
+		if v.IsNil() {
+			return reflect.Value{}
+		}
+		v = v.Elem()
+	}
+	return v
+}
+
+func joinPath(base, seg string) string {
+	if base == "" {
+		return seg
+	}
+	if seg == "[]" {
+		return base + seg
+	}
+	return base + "." + seg
+}
+
+func pathOn(on string) string {
+	if on == "" {
+		return "."
+	}
+	return on
+}
@@ -0,0 +1,153 @@
+package expr_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/accuknox/rinc/internal/expr"
+
+	"github.com/PaesslerAG/gval"
+	"github.com/stretchr/testify/assert"
+)
+
+type queue struct {
+	Name   string
+	Labels map[string]string
+}
+
+type consumer struct {
+	Tag   string
+	Queue *queue
+}
+
+type broker struct {
+	Statuses  map[string]uint
+	Consumers []consumer
+	Primary   **consumer
+}
+
+func newBroker() broker {
+	primary := &consumer{Tag: "primary", Queue: &queue{Name: "q0"}}
+	return broker{
+		Statuses: map[string]uint{
+			"active+clean": 10,
+			"degraded":     2,
+		},
+		Consumers: []consumer{
+			{
+				Tag: "c1",
+				Queue: &queue{
+					Name:   "q1",
+					Labels: map[string]string{"app.kubernetes.io/name": "foo"},
+				},
+			},
+			{Tag: "c2", Queue: &queue{Name: "q2"}},
+			{Tag: "c3", Queue: &queue{Name: "q1"}},
+		},
+		Primary: &primary,
+	}
+}
+
+func TestFieldByPath(t *testing.T) {
+	a := assert.New(t)
+	b := newBroker()
+	inputs := []struct {
+		path    string
+		want    any
+		wantErr bool
+	}{
+		{path: "Statuses.degraded", want: uint(2)},
+		{path: "Statuses.missing", want: uint(0)},
+		{path: "Consumers.0.Tag", want: "c1"},
+		{path: "Consumers.1.Queue.Name", want: "q2"},
+		{path: "Consumers.Queue.Name", want: []any{"q1", "q2", "q1"}},
+		{path: "Consumers.0.Queue.Labels.app.kubernetes.io/name", want: "foo"},
+		{path: "Primary.Queue.Name", want: "q0"},
+		{path: "Consumers.5.Tag", wantErr: true},
+		{path: "Consumers.0.Foo", wantErr: true},
+		{path: "Primary.Tag.Foo", wantErr: true},
+	}
+	for _, i := range inputs {
+		got, err := expr.FieldByPath(b, i.path)
+		if i.wantErr {
+			a.Error(err, "path=%s", i.path)
+			continue
+		}
+		if a.NoError(err, "path=%s", i.path) {
+			a.Equal(i.want, got, "path=%s", i.path)
+		}
+	}
+}
+
+func TestAccessOpMap(t *testing.T) {
+	a := assert.New(t)
+	b := newBroker()
+
+	got, err := expr.AccessOp(b.Statuses, "active+clean")
+	if a.NoError(err) {
+		a.Equal(uint(10), got)
+	}
+
+	got, err = expr.AccessOp(b, "Primary.Queue.Name")
+	if a.NoError(err) {
+		a.Equal("q0", got)
+	}
+
+	got, err = expr.AccessOp(b.Consumers, "Queue.Name")
+	if a.NoError(err) {
+		a.Equal([]any{"q1", "q2", "q1"}, got)
+	}
+
+	_, err = expr.AccessOp(b.Consumers, "Queue.Foo")
+	a.Error(err)
+}
+
+func TestFindNestedPath(t *testing.T) {
+	a := assert.New(t)
+	b := newBroker()
+
+	got, err := expr.FindMany(b.Consumers, "Queue.Name", "q1")
+	if a.NoError(err) {
+		a.Len(got, 2)
+	}
+
+	got, err = expr.FindOneRegex(b.Consumers, "Queue.Name", "^q2$")
+	if a.NoError(err) {
+		a.Equal("c2", got.(consumer).Tag)
+	}
+
+	maps := []map[string]any{
+		{"name": "foo", "ready": true},
+		{"name": "bar", "ready": false},
+	}
+	got, err = expr.FindOne(maps, "name", "bar")
+	if a.NoError(err) {
+		a.Equal(false, got.(map[string]any)["ready"])
+	}
+
+	ok, err := expr.FieldsEq(b.Consumers, "Queue.Name", "q1")
+	if a.NoError(err) {
+		a.False(ok)
+	}
+}
+
+func TestPathExpressions(t *testing.T) {
+	a := assert.New(t)
+	inputs := map[string]any{
+		`(Statuses -> "degraded") > 0`:                              true,
+		`(Statuses -> "active+clean") == 10`:                        true,
+		`len(findMany(Consumers, "Queue.Name", "q1"))`:              2,
+		`(Primary -> "Queue.Name") == "q0"`:                         true,
+		`len(evalOnEach(Consumers, "Tag != \"c2\"", "Queue.Name"))`: 2,
+	}
+	for input, want := range inputs {
+		got, err := gval.Full(expr.Full()...).EvaluateWithContext(
+			context.TODO(),
+			input,
+			newBroker(),
+		)
+		if a.NoError(err, "expr=%s", input) {
+			a.Equal(want, got, "expr=%s", input)
+		}
+	}
+}
@@ -0,0 +1,184 @@
+package expr
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"regexp"
+	"strings"
+)
+
+// StartsWith checks if s begins with prefix.
+func StartsWith(s, prefix any) (bool, error) {
+	str, err := stringArg(s, 0)
+	if err != nil {
+		return false, err
+	}
+	p, err := stringArg(prefix, 1)
+	if err != nil {
+		return false, err
+	}
+	return strings.HasPrefix(str, p), nil
+}
+
+// EndsWith checks if s ends with suffix.
+func EndsWith(s, suffix any) (bool, error) {
+	str, err := stringArg(s, 0)
+	if err != nil {
+		return false, err
+	}
+	p, err := stringArg(suffix, 1)
+	if err != nil {
+		return false, err
+	}
+	return strings.HasSuffix(str, p), nil
+}
+
+// Matches checks if s contains a match of the regular expression regex.
+func Matches(s, regex any) (bool, error) {
+	str, err := stringArg(s, 0)
+	if err != nil {
+		return false, err
+	}
+	r, err := stringArg(regex, 1)
+	if err != nil {
+		return false, err
+	}
+	re, err := regexp.Compile(r)
+	if err != nil {
+		return false, fmt.Errorf("compiling regex %q: %w", r, err)
+	}
+	return re.MatchString(str), nil
+}
+
+// Split slices s into all substrings separated by sep.
+func Split(s, sep any) ([]string, error) {
+	str, err := stringArg(s, 0)
+	if err != nil {
+		return nil, err
+	}
+	p, err := stringArg(sep, 1)
+	if err != nil {
+		return nil, err
+	}
+	return strings.Split(str, p), nil
+}
+
+// Lower returns s with all letters mapped to their lower case.
+func Lower(s any) (string, error) {
+	str, err := stringArg(s, 0)
+	if err != nil {
+		return "", err
+	}
+	return strings.ToLower(str), nil
+}
+
+// Upper returns s with all letters mapped to their upper case.
+func Upper(s any) (string, error) {
+	str, err := stringArg(s, 0)
+	if err != nil {
+		return "", err
+	}
+	return strings.ToUpper(str), nil
+}
+
+// Join concatenates the items of list, formatted using their default format,
+// with sep placed between them.
+func Join(list, sep any) (string, error) {
+	rlist := reflect.ValueOf(list)
+	if rlist.Kind() != reflect.Slice && rlist.Kind() != reflect.Array {
+		return "", ErrUnexpectedKind[string]{
+			arg:  0,
+			want: fmt.Sprintf("%s|%s", reflect.Array, reflect.Slice),
+			got:  rlist.Kind().String(),
+		}
+	}
+	p, err := stringArg(sep, 1)
+	if err != nil {
+		return "", err
+	}
+	items := make([]string, rlist.Len())
+	for idx := range items {
+		items[idx] = fmt.Sprint(valueOf(indirect(rlist.Index(idx))))
+	}
+	return strings.Join(items, p), nil
+}
+
+// Sprintf formats args according to format, see fmt.Sprintf. Numbers in
+// expressions are floats, so integral ones are formatted as integers by the
+// integer verbs, e.g., "%d".
+func Sprintf(format string, args ...any) string {
+	for idx, arg := range args {
+		if f, ok := arg.(float64); ok && f == math.Trunc(f) &&
+			f >= math.MinInt64 && f < math.MaxInt64 {
+			args[idx] = integral(f)
+		}
+	}
+	return fmt.Sprintf(format, args...)
+}
+
+// integral is an integral float, which is formatted as an integer by the
+// integer verbs, and as a float otherwise.
+type integral float64
+
+// Format satisfies the fmt.Formatter interface.
+func (n integral) Format(f fmt.State, verb rune) {
+	switch verb {
+	case 'd', 'o', 'O', 'x', 'X', 'b', 'c', 'U':
+		fmt.Fprintf(f, fmt.FormatString(f, verb), int64(n))
+	default:
+		fmt.Fprintf(f, fmt.FormatString(f, verb), float64(n))
+	}
+}
+
+// ImageRepo returns the repository of an OCI image reference, i.e., the
+// reference without its tag and digest, e.g., "docker.io/library/nginx" for
+// "docker.io/library/nginx:1.27@sha256:...".
+func ImageRepo(name any) (string, error) {
+	ref, err := stringArg(name, 0)
+	if err != nil {
+		return "", err
+	}
+	repo, _, _ := parseImageRef(ref)
+	return repo, nil
+}
+
+// ImageTag returns the tag of an OCI image reference. It returns "latest" if
+// the reference has neither a tag nor a digest, and an empty string if it is
+// only pinned by digest.
+func ImageTag(name any) (string, error) {
+	ref, err := stringArg(name, 0)
+	if err != nil {
+		return "", err
+	}
+	_, tag, digest := parseImageRef(ref)
+	if tag == "" && digest == "" {
+		return "latest", nil
+	}
+	return tag, nil
+}
+
+// parseImageRef splits an OCI image reference of the form
+// "[registry[:port]/]repository[:tag][@digest]" into its parts.
+func parseImageRef(ref string) (repo, tag, digest string) {
+	repo, digest, _ = strings.Cut(ref, "@")
+	// a colon before the last slash separates the registry host and port
+	if idx := strings.LastIndexByte(repo, ':'); idx > strings.LastIndexByte(repo, '/') {
+		repo, tag = repo[:idx], repo[idx+1:]
+	}
+	return repo, tag, digest
+}
+
+// stringArg returns x as a string, or an error mentioning the position of x
+// in the arguments if it isn't one.
+func stringArg(x any, arg int) (string, error) {
+	xval := reflect.ValueOf(x)
+	if xval.Kind() != reflect.String {
+		return "", ErrUnexpectedKind[reflect.Kind]{
+			arg:  arg,
+			want: reflect.String,
+			got:  xval.Kind(),
+		}
+	}
+	return xval.String(), nil
+}
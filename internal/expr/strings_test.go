@@ -0,0 +1,101 @@
+package expr_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/accuknox/rinc/internal/expr"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestImageRef(t *testing.T) {
+	a := assert.New(t)
+	inputs := []struct {
+		name string
+		repo string
+		tag  string
+	}{
+		{name: "nginx", repo: "nginx", tag: "latest"},
+		{name: "nginx:1.27", repo: "nginx", tag: "1.27"},
+		{name: "docker.io/library/nginx:1.27-alpine", repo: "docker.io/library/nginx", tag: "1.27-alpine"},
+		{name: "localhost:5000/app", repo: "localhost:5000/app", tag: "latest"},
+		{name: "localhost:5000/app:v1", repo: "localhost:5000/app", tag: "v1"},
+		{name: "ghcr.io/org/app@sha256:abcd", repo: "ghcr.io/org/app", tag: ""},
+		{name: "ghcr.io/org/app:v2@sha256:abcd", repo: "ghcr.io/org/app", tag: "v2"},
+	}
+	for _, i := range inputs {
+		repo, err := expr.ImageRepo(i.name)
+		if a.NoError(err, "name=%s", i.name) {
+			a.Equal(i.repo, repo, "name=%s", i.name)
+		}
+		tag, err := expr.ImageTag(i.name)
+		if a.NoError(err, "name=%s", i.name) {
+			a.Equal(i.tag, tag, "name=%s", i.name)
+		}
+	}
+	_, err := expr.ImageTag(1)
+	a.Error(err)
+}
+
+func TestStringExpressions(t *testing.T) {
+	a := assert.New(t)
+	type container struct {
+		Name  string
+		Image string
+		State string
+	}
+	data := struct {
+		Containers []container
+		Health     string
+	}{
+		Containers: []container{
+			{Name: "app", Image: "accuknox/app:v1.2.0", State: "WAITING: Reason=CrashLoopBackOff"},
+			{Name: "sidecar", Image: "envoyproxy/envoy:latest", State: "RUNNING"},
+		},
+		Health: "HEALTH_WARN",
+	}
+	inputs := map[string]any{
+		`startsWith(Containers[0].State, "WAITING")`:                                true,
+		`endsWith(Containers[0].State, "CrashLoopBackOff")`:                         true,
+		`matches(Containers[1].State, "^RUN")`:                                      true,
+		`matches(Health, "ERR$")`:                                                   false,
+		`split(Containers[0].State, "=")`:                                           []string{"WAITING: Reason", "CrashLoopBackOff"},
+		`has(split("a,b,c", ","), "b")`:                                             true,
+		`lower(Health) == "health_warn"`:                                            true,
+		`upper("ok")`:                                                               "OK",
+		`join(Containers -> "Name", ", ")`:                                          "app, sidecar",
+		`join([1, 2], "-")`:                                                         "1-2",
+		`sprintf("%s is %v", Containers[1].Name, len(Containers))`:                  "sidecar is 2",
+		`sprintf("%d-%s", 1, "a")`:                                                  "1-a",
+		`sprintf("%03d %x %.1f %v %d", 7, 255, 50, 2.5, 2.5)`:                       "007 ff 50.0 2.5 %!d(float64=2.5)",
+		`sprintf("%d pods", len(Containers))`:                                       "2 pods",
+		`imageTag(Containers[0].Image) == "v1.2.0"`:                                 true,
+		`imageRepo(Containers[1].Image)`:                                            "envoyproxy/envoy",
+		`len(findManyRegex(Containers, "Image", "^accuknox/")) == 1`:                true,
+		`join(evalOnEach(Containers, "imageTag(Image) == \"latest\"", "Name"), "")`: "sidecar",
+	}
+	for input, want := range inputs {
+		ev, err := expr.Parse(input)
+		if !a.NoError(err, "expr=%s", input) {
+			continue
+		}
+		got, err := ev(context.TODO(), data)
+		if a.NoError(err, "expr=%s", input) {
+			a.Equal(want, got, "expr=%s", input)
+		}
+	}
+
+	errs := []string{
+		`startsWith(1, "a")`,
+		`matches(Health, "(")`,
+		`join(Health, ",")`,
+	}
+	for _, input := range errs {
+		ev, err := expr.Parse(input)
+		if err == nil {
+			_, err = ev(context.TODO(), data)
+		}
+		a.Error(err, "expr=%s", input)
+	}
+}
@@ -0,0 +1,62 @@
+package expr
+
+import (
+	"sort"
+	"strings"
+)
+
+// maxSuggestions is the maximum number of suggestions offered for a
+// misspelled name.
+const maxSuggestions = 3
+
+// suggest returns the candidates that are likely to be what the user meant
+// when they wrote name, closest first.
+func suggest(name string, candidates []string) []string {
+	type match struct {
+		name string
+		dist int
+	}
+
+	lname := strings.ToLower(name)
+	threshold := max(1, len(name)/3)
+
+	var matches []match
+	for _, c := range candidates {
+		lc := strings.ToLower(c)
+		dist := levenshtein(lname, lc)
+		if dist <= threshold || (len(lname) >= 3 && strings.HasPrefix(lc, lname)) {
+			matches = append(matches, match{name: c, dist: dist})
+		}
+	}
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i].dist < matches[j].dist
+	})
+
+	var out []string
+	for idx := 0; idx < len(matches) && idx < maxSuggestions; idx++ {
+		out = append(out, matches[idx].name)
+	}
+	return out
+}
+
+// levenshtein computes the edit distance between a and b.
+func levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(rb)]
+}
@@ -0,0 +1,148 @@
+package expr
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// dayRegex matches the day component of a duration string, e.g., "7d" in
+// "7d12h".
+var dayRegex = regexp.MustCompile(`([0-9]*\.?[0-9]+)d`)
+
+// Duration parses a duration string such as "6h" or "1h30m". In addition to
+// the units supported by time.ParseDuration, a "d" (24h) unit is accepted.
+func Duration(x any) (time.Duration, error) {
+	xval := reflect.ValueOf(x)
+	if xval.Kind() != reflect.String {
+		return 0, ErrUnexpectedKind[reflect.Kind]{
+			arg:  0,
+			want: reflect.String,
+			got:  xval.Kind(),
+		}
+	}
+	s := dayRegex.ReplaceAllStringFunc(xval.String(), func(d string) string {
+		days, err := strconv.ParseFloat(strings.TrimSuffix(d, "d"), 64)
+		if err != nil {
+			return d
+		}
+		return fmt.Sprintf("%gh", days*24)
+	})
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		return 0, fmt.Errorf("parsing duration %q: %w", xval.String(), err)
+	}
+	return d, nil
+}
+
+type nowKey struct{}
+
+// WithNow returns a copy of ctx in which the now and since functions of
+// expressions evaluate relative to t instead of the current time, e.g., the
+// time of the evaluated report.
+func WithNow(ctx context.Context, t time.Time) context.Context {
+	return context.WithValue(ctx, nowKey{}, t)
+}
+
+// Now returns the current time in UTC.
+func Now() time.Time {
+	return now(context.Background())
+}
+
+// Since returns the time elapsed since t.
+func Since(t any) (time.Duration, error) {
+	return since(context.Background(), t)
+}
+
+// now returns the time set by WithNow, otherwise the current time in UTC.
+func now(ctx context.Context) time.Time {
+	if t, ok := ctx.Value(nowKey{}).(time.Time); ok {
+		return t
+	}
+	return time.Now().UTC()
+}
+
+// since returns the time elapsed since t until now.
+func since(ctx context.Context, t any) (time.Duration, error) {
+	switch at := t.(type) {
+	case time.Time:
+		return now(ctx).Sub(at), nil
+	case *time.Time:
+		if at != nil {
+			return now(ctx).Sub(*at), nil
+		}
+	}
+	return 0, ErrUnexpectedKind[string]{
+		arg:  0,
+		want: "time.Time",
+		got:  reflect.ValueOf(t).Kind().String(),
+	}
+}
+
+// HumanDuration formats a duration into a human readable string, e.g.,
+// "2d3h4m5s". Numeric values are treated as nanoseconds, which allows
+// formatting durations that went through arithmetic operations.
+func HumanDuration(d any) (string, error) {
+	ns, err := toInt64(d)
+	if err != nil {
+		return "", err
+	}
+	dur := time.Duration(ns).Round(time.Second)
+	if dur == 0 {
+		return "0s", nil
+	}
+
+	var sb strings.Builder
+	if dur < 0 {
+		sb.WriteString("-")
+		dur = -dur
+	}
+	units := []struct {
+		suffix string
+		size   time.Duration
+	}{
+		{"d", time.Hour * 24},
+		{"h", time.Hour},
+		{"m", time.Minute},
+		{"s", time.Second},
+	}
+	for _, u := range units {
+		if dur < u.size {
+			continue
+		}
+		fmt.Fprintf(&sb, "%d%s", dur/u.size, u.suffix)
+		dur %= u.size
+	}
+	return sb.String(), nil
+}
+
+// toInt64 converts any numeric value to int64.
+func toInt64(x any) (int64, error) {
+	xval := reflect.ValueOf(x)
+	switch xval.Kind() {
+	case reflect.Int,
+		reflect.Int8,
+		reflect.Int16,
+		reflect.Int32,
+		reflect.Int64:
+		return xval.Int(), nil
+	case reflect.Uint,
+		reflect.Uint8,
+		reflect.Uint16,
+		reflect.Uint32,
+		reflect.Uint64:
+		return int64(xval.Uint()), nil
+	case reflect.Float32, reflect.Float64:
+		return int64(xval.Float()), nil
+	default:
+		return 0, ErrUnexpectedKind[string]{
+			arg:  0,
+			want: "int|uint|float",
+			got:  xval.Kind().String(),
+		}
+	}
+}
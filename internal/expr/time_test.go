@@ -0,0 +1,132 @@
+package expr_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/accuknox/rinc/internal/expr"
+
+	"github.com/PaesslerAG/gval"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDuration(t *testing.T) {
+	a := assert.New(t)
+	inputs := map[any]time.Duration{
+		"6h":     time.Hour * 6,
+		"1h30m":  time.Hour + time.Minute*30,
+		"1d":     time.Hour * 24,
+		"1d12h":  time.Hour * 36,
+		"0.5d":   time.Hour * 12,
+		"10s":    time.Second * 10,
+		"foobar": -1,
+		"6x":     -1,
+		10:       -1,
+	}
+	for input, want := range inputs {
+		got, err := expr.Duration(input)
+		if want == -1 {
+			a.Error(err, "x=%v", input)
+			continue
+		}
+		a.NoError(err, "x=%v", input)
+		a.Equal(want, got, "x=%v", input)
+	}
+}
+
+func TestSince(t *testing.T) {
+	a := assert.New(t)
+
+	start := time.Now().Add(-time.Hour)
+	got, err := expr.Since(start)
+	if a.NoError(err) {
+		a.GreaterOrEqual(got, time.Hour)
+	}
+
+	got, err = expr.Since(&start)
+	if a.NoError(err) {
+		a.GreaterOrEqual(got, time.Hour)
+	}
+
+	_, err = expr.Since("foo")
+	a.Error(err)
+
+	_, err = expr.Since(nil)
+	a.Error(err)
+}
+
+func TestHumanDuration(t *testing.T) {
+	a := assert.New(t)
+	inputs := []data{
+		{x: time.Duration(0), value: "0s"},
+		{x: time.Second * 90, value: "1m30s"},
+		{x: time.Hour*51 + time.Minute*4 + time.Second*5, value: "2d3h4m5s"},
+		{x: -time.Hour, value: "-1h"},
+		{x: float64(time.Hour), value: "1h"},
+		{x: "foo", wantErr: true},
+	}
+	for _, i := range inputs {
+		got, err := expr.HumanDuration(i.x)
+		if i.wantErr {
+			a.Error(err, "x=%v", i.x)
+			continue
+		}
+		a.NoError(err, "x=%v", i.x)
+		a.Equal(i.value, got, "x=%v", i.x)
+	}
+}
+
+func TestTimeExpressions(t *testing.T) {
+	a := assert.New(t)
+	type job struct {
+		Age       time.Duration
+		StartTime time.Time
+	}
+	data := job{
+		Age:       time.Hour * 8,
+		StartTime: time.Now().Add(-time.Hour * 2),
+	}
+	inputs := map[string]bool{
+		`Age > duration("6h")`:                 true,
+		`Age > duration("1d")`:                 false,
+		`since(StartTime) > duration("1h")`:    true,
+		`since(StartTime) > duration("3h")`:    false,
+		`humanDuration(Age) == "8h"`:           true,
+		`humanDuration(Age * 2) == "16h"`:      true,
+		`since(now()) < duration("1m")`:        true,
+		`since(StartTime) < Age`:               true,
+		`duration("90m") == duration("1h30m")`: true,
+	}
+	for input, want := range inputs {
+		got, err := gval.Full(expr.Full()...).EvaluateWithContext(
+			context.TODO(),
+			input,
+			data,
+		)
+		if a.NoError(err, "expr=%s", input) {
+			a.Equal(want, got, "expr=%s", input)
+		}
+	}
+}
+
+func TestWithNow(t *testing.T) {
+	a := assert.New(t)
+	now := time.Date(2024, 10, 18, 12, 0, 0, 0, time.UTC)
+	ctx := expr.WithNow(context.Background(), now)
+	data := map[string]any{
+		"StartTime": now.Add(-time.Hour * 2),
+	}
+	inputs := map[string]any{
+		`since(StartTime)`:                  time.Hour * 2,
+		`since(StartTime) > duration("3h")`: false,
+		`now()`:                             now,
+		`since(now())`:                      time.Duration(0),
+	}
+	for input, want := range inputs {
+		got, err := gval.Full(expr.Full()...).EvaluateWithContext(ctx, input, data)
+		if a.NoError(err, "expr=%s", input) {
+			a.Equal(want, got, "expr=%s", input)
+		}
+	}
+}
@@ -6,13 +6,14 @@ import (
 	"log/slog"
 	"time"
 
+	"github.com/accuknox/rinc/internal/db"
 	"github.com/accuknox/rinc/internal/report/ceph"
 )
 
 // GenerateCEPHReport generates ceph status report.
 func (j Job) GenerateCEPHReport(ctx context.Context, now time.Time) error {
-	r := ceph.NewReporter(j.conf.Ceph, j.kubeClient, j.mongo)
-	err := r.Report(ctx, now)
+	r := ceph.NewReporter(j.conf.Ceph, j.kubeClient, j.opts.Network, j.mongo)
+	err := generate(ctx, j, now, r, db.CollectionCeph, j.conf.Ceph.Alerts)
 	if err != nil {
 		slog.LogAttrs(
 			ctx,
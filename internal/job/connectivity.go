@@ -6,13 +6,14 @@ import (
 	"log/slog"
 	"time"
 
+	"github.com/accuknox/rinc/internal/db"
 	"github.com/accuknox/rinc/internal/report/connectivity"
 )
 
 // GenerateConnectivityReport generates connectivity status report.
 func (j Job) GenerateConnectivityReport(ctx context.Context, now time.Time) error {
-	r := connectivity.NewReporter(j.conf.Connectivity, j.kubeClient, j.mongo)
-	err := r.Report(ctx, now)
+	r := connectivity.NewReporter(j.conf.Connectivity, j.kubeClient, j.opts.Network, j.mongo)
+	err := generate(ctx, j, now, r, db.CollectionConnectivity, j.conf.Connectivity.Alerts)
 	if err != nil {
 		slog.LogAttrs(
 			ctx,
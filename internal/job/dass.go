@@ -6,14 +6,18 @@ import (
 	"log/slog"
 	"time"
 
+	"github.com/accuknox/rinc/internal/db"
 	"github.com/accuknox/rinc/internal/report/dass"
 )
 
 // GenerateDaSSReport generates a status report for deployments and
 // statefulsets.
 func (j Job) GenerateDaSSReport(ctx context.Context, now time.Time) error {
-	r := dass.NewReporter(j.conf.DaSS, j.kubeClient, j.mongo)
-	err := r.Report(ctx, now)
+	r := dass.NewReporter(j.conf.DaSS, j.kubeClient, j.cache, j.mongo)
+	if ns, ok := j.namespaces[db.CollectionDass]; ok {
+		r = r.WithNamespaces(ns)
+	}
+	err := generate(ctx, j, now, r, db.CollectionDass, j.conf.DaSS.Alerts)
 	if err != nil {
 		slog.LogAttrs(
 			ctx,
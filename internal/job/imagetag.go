@@ -6,14 +6,18 @@ import (
 	"log/slog"
 	"time"
 
+	"github.com/accuknox/rinc/internal/db"
 	"github.com/accuknox/rinc/internal/report/imagetag"
 )
 
 // GenerateImageTagReport generates an image tag report for deployments and
 // statefulsets.
 func (j Job) GenerateImageTagReport(ctx context.Context, now time.Time) error {
-	r := imagetag.NewReporter(j.conf.ImageTag, j.kubeClient, j.mongo)
-	err := r.Report(ctx, now)
+	r := imagetag.NewReporter(j.conf.ImageTag, j.kubeClient, j.cache, j.mongo)
+	if ns, ok := j.namespaces[db.CollectionImageTag]; ok {
+		r = r.WithNamespaces(ns)
+	}
+	err := generate(ctx, j, now, r, db.CollectionImageTag, j.conf.ImageTag.Alerts)
 	if err != nil {
 		slog.LogAttrs(
 			ctx,
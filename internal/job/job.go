@@ -2,14 +2,24 @@ package job
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
+	"slices"
 	"time"
 
 	"github.com/accuknox/rinc/internal/conf"
+	"github.com/accuknox/rinc/internal/db"
+	"github.com/accuknox/rinc/internal/expr"
+	"github.com/accuknox/rinc/internal/kube"
+	"github.com/accuknox/rinc/internal/report"
+	"github.com/accuknox/rinc/internal/snapshot"
+	"github.com/accuknox/rinc/internal/telemetry"
 	"github.com/accuknox/rinc/internal/util"
 
 	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 	"k8s.io/client-go/kubernetes"
 	metrics "k8s.io/metrics/pkg/client/clientset/versioned"
 )
@@ -18,27 +28,146 @@ import (
 // reports.
 type Job struct {
 	conf          conf.C
-	kubeClient    *kubernetes.Clientset
-	metricsClient *metrics.Clientset
-	mongo         *mongo.Client
+	kubeClient    kubernetes.Interface
+	metricsClient metrics.Interface
+	// cache is the informer cache shared by the Kubernetes reporters. It is
+	// populated by GenerateAll.
+	cache *kube.Cache
+	// namespaces are the namespaces of the enabled Kubernetes reporters by
+	// the collection of their reports, resolved once by GenerateAll.
+	namespaces map[string]kube.Namespaces
+	mongo      *mongo.Client
+	opts       Options
+}
+
+// Options are the optional settings of a Job.
+type Options struct {
+	// Network is how the reporters reach the services outside of Kubernetes.
+	// The live network is used if it is nil.
+	Network snapshot.Network
+	// Now is the time of the generated reports. The current time is used if
+	// it is zero.
+	Now time.Time
+	// Output receives the reports and their firing alerts instead of
+	// MongoDB if it is set.
+	Output Output
+	// Reporters are the collections of the reports to generate, regardless
+	// of whether they are enabled. All enabled reports are generated if it
+	// is empty.
+	Reporters []string
+}
+
+// Output receives the generated reports instead of MongoDB.
+type Output interface {
+	// Write receives the metrics document of the report stored in
+	// collection, and the alerts that fired on it.
+	Write(collection string, now time.Time, metrics any, alerts []db.Alert) error
+}
+
+// collector is a reporter that can collect its metrics without writing them
+// to the database.
+type collector[M any] interface {
+	report.Reporter
+	Collect(ctx context.Context, now time.Time) (M, error)
+}
+
+// generate writes the report of r to the database, or collects it with its
+// firing alerts into the output of the job if it has one.
+func generate[M any](ctx context.Context, j Job, now time.Time, r collector[M], collection string, alerts []conf.Alert) (err error) {
+	ctx = telemetry.WithReporter(ctx, collection)
+	ctx, span := telemetry.Start(ctx, "report "+collection)
+	defer func(start time.Time) {
+		telemetry.ObserveReporter(collection, start, err)
+		telemetry.End(span, err)
+	}(time.Now())
+
+	if j.opts.Output == nil {
+		return r.Report(ctx, now)
+	}
+	metrics, err := r.Collect(ctx, now)
+	if err != nil {
+		return err
+	}
+	firing := report.SoftEvaluateAlerts(ctx, alerts, metrics)
+	err = j.opts.Output.Write(collection, now, metrics, firing)
+	if err != nil {
+		return fmt.Errorf("writing output: %w", err)
+	}
+	return nil
 }
 
 // New returns a new reporting Job object.
-func New(c conf.C, k *kubernetes.Clientset, m *metrics.Clientset, mongo *mongo.Client) Job {
+func New(c conf.C, k kubernetes.Interface, m metrics.Interface, mongo *mongo.Client, opts Options) Job {
 	slog.SetDefault(util.NewLogger(c.Log))
+	if opts.Network == nil {
+		opts.Network = snapshot.Live
+	}
 	return Job{
 		conf:          c,
 		kubeClient:    k,
 		metricsClient: m,
 		mongo:         mongo,
+		opts:          opts,
 	}
 }
 
 // GenerateAll generates reports for all the configured tasks.
 func (j Job) GenerateAll(ctx context.Context) error {
-	now := time.Now().UTC().Round(time.Second)
+	now := j.opts.Now
+	if now.IsZero() {
+		now = time.Now().UTC().Round(time.Second)
+	}
+	// alerts are evaluated relative to the time of the reports
+	ctx = expr.WithNow(ctx, now)
+
+	scopes, namespaces, err := j.cacheScopes(ctx)
+	if err != nil {
+		slog.LogAttrs(
+			ctx,
+			slog.LevelError,
+			"resolving informer cache scopes",
+			slog.String("error", err.Error()),
+		)
+		return fmt.Errorf("resolving informer cache scopes: %w", err)
+	}
+	j.namespaces = namespaces
+	if len(scopes) != 0 {
+		resources := scopes.Resources()
+		cache, err := kube.NewCache(j.kubeClient, scopes)
+		if err != nil {
+			return fmt.Errorf("creating informer cache: %w", err)
+		}
+		cacheCtx, cancel := context.WithCancel(ctx)
+		defer func() {
+			cancel()
+			cache.Shutdown()
+		}()
+		// the List calls of the informers are made while syncing, and
+		// aren't traced on their own
+		_, span := telemetry.Start(ctx, "sync informer cache", trace.WithAttributes(
+			attribute.StringSlice("resources", resourceNames(resources)),
+		))
+		err = cache.Start(cacheCtx)
+		telemetry.End(span, err)
+		if err != nil {
+			slog.LogAttrs(
+				ctx,
+				slog.LevelError,
+				"starting informer cache",
+				slog.String("error", err.Error()),
+			)
+			return fmt.Errorf("starting informer cache: %w", err)
+		}
+		slog.LogAttrs(
+			ctx,
+			slog.LevelInfo,
+			"informer cache synced",
+			slog.Any("resources", resources),
+		)
+		j.cache = cache
+	}
 
-	if j.conf.RabbitMQ.Enable {
+	if j.enabled(db.CollectionRabbitmq, j.conf.RabbitMQ.Enable) {
 		err := j.GenerateRMQReport(ctx, now)
 		if err != nil {
 			slog.LogAttrs(
@@ -51,7 +180,7 @@ func (j Job) GenerateAll(ctx context.Context) error {
 		}
 	}
 
-	if j.conf.LongJobs.Enable {
+	if j.enabled(db.CollectionLongJobs, j.conf.LongJobs.Enable) {
 		err := j.GenerateLongRunningJobsReport(ctx, now)
 		if err != nil {
 			slog.LogAttrs(
@@ -64,7 +193,7 @@ func (j Job) GenerateAll(ctx context.Context) error {
 		}
 	}
 
-	if j.conf.ImageTag.Enable {
+	if j.enabled(db.CollectionImageTag, j.conf.ImageTag.Enable) {
 		err := j.GenerateImageTagReport(ctx, now)
 		if err != nil {
 			slog.LogAttrs(
@@ -77,7 +206,7 @@ func (j Job) GenerateAll(ctx context.Context) error {
 		}
 	}
 
-	if j.conf.DaSS.Enable {
+	if j.enabled(db.CollectionDass, j.conf.DaSS.Enable) {
 		err := j.GenerateDaSSReport(ctx, now)
 		if err != nil {
 			slog.LogAttrs(
@@ -90,7 +219,7 @@ func (j Job) GenerateAll(ctx context.Context) error {
 		}
 	}
 
-	if j.conf.Ceph.Enable {
+	if j.enabled(db.CollectionCeph, j.conf.Ceph.Enable) {
 		err := j.GenerateCEPHReport(ctx, now)
 		if err != nil {
 			slog.LogAttrs(
@@ -103,7 +232,7 @@ func (j Job) GenerateAll(ctx context.Context) error {
 		}
 	}
 
-	if j.conf.PVUtilization.Enable {
+	if j.enabled(db.CollectionPVUtilizaton, j.conf.PVUtilization.Enable) {
 		err := j.GeneratePVUtilizationReport(ctx, now)
 		if err != nil {
 			slog.LogAttrs(
@@ -116,7 +245,7 @@ func (j Job) GenerateAll(ctx context.Context) error {
 		}
 	}
 
-	if j.conf.ResourceUtilization.Enable {
+	if j.enabled(db.CollectionResourceUtilization, j.conf.ResourceUtilization.Enable) {
 		err := j.GenerateResourceUtilizationReport(ctx, now)
 		if err != nil {
 			slog.LogAttrs(
@@ -129,18 +258,20 @@ func (j Job) GenerateAll(ctx context.Context) error {
 		}
 	}
 
-	err := j.GenerateConnectivityReport(ctx, now)
-	if err != nil {
-		slog.LogAttrs(
-			ctx,
-			slog.LevelError,
-			"generating connectivity status report",
-			slog.String("error", err.Error()),
-		)
-		return fmt.Errorf("generating connectivity status report: %w", err)
+	if j.enabled(db.CollectionConnectivity, true) {
+		err := j.GenerateConnectivityReport(ctx, now)
+		if err != nil {
+			slog.LogAttrs(
+				ctx,
+				slog.LevelError,
+				"generating connectivity status report",
+				slog.String("error", err.Error()),
+			)
+			return fmt.Errorf("generating connectivity status report: %w", err)
+		}
 	}
 
-	if j.conf.PodStatus.Enable {
+	if j.enabled(db.CollectionPodStatus, j.conf.PodStatus.Enable) {
 		err := j.GeneratePodStatusReport(ctx, now)
 		if err != nil {
 			slog.LogAttrs(
@@ -155,3 +286,84 @@ func (j Job) GenerateAll(ctx context.Context) error {
 
 	return nil
 }
+
+// enabled reports whether the report stored in collection is generated, given
+// whether it is enabled in the configuration.
+func (j Job) enabled(collection string, enable bool) bool {
+	if len(j.opts.Reporters) == 0 {
+		return enable
+	}
+	return slices.Contains(j.opts.Reporters, collection)
+}
+
+func resourceNames(resources []kube.Resource) []string {
+	names := make([]string, len(resources))
+	for i, r := range resources {
+		names[i] = string(r)
+	}
+	return names
+}
+
+// cacheScopes returns the Kubernetes resources read by the enabled reporters,
+// which are listed once into the shared informer cache, limited to the
+// namespaces and label selectors of the reporters reading them. Pods and
+// events are read by their owners or the objects they are about, and aren't
+// limited to the label selectors of the reporters.
+//
+// The namespaces of the reporters are returned too, by the collection of
+// their reports, so that the reporters don't resolve them again.
+func (j Job) cacheScopes(ctx context.Context) (kube.Scopes, map[string]kube.Namespaces, error) {
+	scopes := make(kube.Scopes)
+	namespaces := make(map[string]kube.Namespaces)
+	add := func(collection string, selector conf.NamespaceSelector, add func(ns kube.Namespaces)) error {
+		ns, err := kube.ResolveNamespaces(ctx, j.kubeClient, selector)
+		if err != nil {
+			return fmt.Errorf("resolving namespaces: %w", err)
+		}
+		namespaces[collection] = ns
+		add(ns)
+		return nil
+	}
+	var errs []error
+	if j.enabled(db.CollectionLongJobs, j.conf.LongJobs.Enable) {
+		c := j.conf.LongJobs
+		errs = append(errs, add(db.CollectionLongJobs, c.Namespaces, func(ns kube.Namespaces) {
+			scopes.Add(kube.ResourceJobs, ns, c.LabelSelector)
+			scopes.Add(kube.ResourcePods, ns, "")
+		}))
+	}
+	if j.enabled(db.CollectionImageTag, j.conf.ImageTag.Enable) {
+		c := j.conf.ImageTag
+		errs = append(errs, add(db.CollectionImageTag, c.Namespaces, func(ns kube.Namespaces) {
+			scopes.Add(kube.ResourceDeployments, ns, c.LabelSelector)
+			scopes.Add(kube.ResourceStatefulSets, ns, c.LabelSelector)
+		}))
+	}
+	if j.enabled(db.CollectionDass, j.conf.DaSS.Enable) {
+		c := j.conf.DaSS
+		errs = append(errs, add(db.CollectionDass, c.Namespaces, func(ns kube.Namespaces) {
+			scopes.Add(kube.ResourceDeployments, ns, c.LabelSelector)
+			scopes.Add(kube.ResourceStatefulSets, ns, c.LabelSelector)
+			scopes.Add(kube.ResourceEvents, ns, "")
+		}))
+	}
+	if j.enabled(db.CollectionResourceUtilization, j.conf.ResourceUtilization.Enable) {
+		c := j.conf.ResourceUtilization
+		errs = append(errs, add(db.CollectionResourceUtilization, c.Namespaces, func(ns kube.Namespaces) {
+			scopes.Add(kube.ResourcePods, ns, "")
+			scopes.Add(kube.ResourceNodes, kube.Namespaces{}, "")
+		}))
+	}
+	if j.enabled(db.CollectionPodStatus, j.conf.PodStatus.Enable) {
+		c := j.conf.PodStatus
+		errs = append(errs, add(db.CollectionPodStatus, c.Namespaces, func(ns kube.Namespaces) {
+			scopes.Add(kube.ResourceDeployments, ns, c.LabelSelector)
+			scopes.Add(kube.ResourceStatefulSets, ns, c.LabelSelector)
+			scopes.Add(kube.ResourcePods, ns, "")
+		}))
+	}
+	if err := errors.Join(errs...); err != nil {
+		return nil, nil, err
+	}
+	return scopes, namespaces, nil
+}
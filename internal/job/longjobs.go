@@ -6,14 +6,18 @@ import (
 	"log/slog"
 	"time"
 
+	"github.com/accuknox/rinc/internal/db"
 	"github.com/accuknox/rinc/internal/report/longjobs"
 )
 
 // GenerateLongRunningJobsReport generates a report for Kubernetes jobs running
 // older than the given provided threshold.
 func (j Job) GenerateLongRunningJobsReport(ctx context.Context, now time.Time) error {
-	r := longjobs.NewReporter(j.conf.LongJobs, j.kubeClient, j.mongo)
-	err := r.Report(ctx, now)
+	r := longjobs.NewReporter(j.conf.LongJobs, j.kubeClient, j.cache, j.mongo)
+	if ns, ok := j.namespaces[db.CollectionLongJobs]; ok {
+		r = r.WithNamespaces(ns)
+	}
+	err := generate(ctx, j, now, r, db.CollectionLongJobs, j.conf.LongJobs.Alerts)
 	if err != nil {
 		slog.LogAttrs(
 			ctx,
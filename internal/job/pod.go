@@ -6,13 +6,17 @@ import (
 	"log/slog"
 	"time"
 
+	"github.com/accuknox/rinc/internal/db"
 	"github.com/accuknox/rinc/internal/report/pod"
 )
 
 // GeneratePodStatusReport generates pod status report.
 func (j Job) GeneratePodStatusReport(ctx context.Context, now time.Time) error {
-	r := pod.NewReporter(j.conf.PodStatus, j.kubeClient, j.mongo)
-	err := r.Report(ctx, now)
+	r := pod.NewReporter(j.conf.PodStatus, j.kubeClient, j.cache, j.mongo)
+	if ns, ok := j.namespaces[db.CollectionPodStatus]; ok {
+		r = r.WithNamespaces(ns)
+	}
+	err := generate(ctx, j, now, r, db.CollectionPodStatus, j.conf.PodStatus.Alerts)
 	if err != nil {
 		slog.LogAttrs(
 			ctx,
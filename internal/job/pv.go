@@ -6,13 +6,14 @@ import (
 	"log/slog"
 	"time"
 
+	"github.com/accuknox/rinc/internal/db"
 	"github.com/accuknox/rinc/internal/report/pv"
 )
 
 // GeneratePVUtilizationReport generates a PV utilization status report.
 func (j Job) GeneratePVUtilizationReport(ctx context.Context, now time.Time) error {
-	r := pv.NewReporter(j.conf.PVUtilization, j.kubeClient, j.mongo)
-	err := r.Report(ctx, now)
+	r := pv.NewReporter(j.conf.PVUtilization, j.kubeClient, j.opts.Network, j.mongo)
+	err := generate(ctx, j, now, r, db.CollectionPVUtilizaton, j.conf.PVUtilization.Alerts)
 	if err != nil {
 		slog.LogAttrs(
 			ctx,
@@ -6,13 +6,14 @@ import (
 	"log/slog"
 	"time"
 
+	"github.com/accuknox/rinc/internal/db"
 	"github.com/accuknox/rinc/internal/report/rabbitmq"
 )
 
 // GenerateRMQReport generates a RabbitMQ status and metrics report.
 func (j Job) GenerateRMQReport(ctx context.Context, now time.Time) error {
-	r := rabbitmq.NewReporter(j.conf.RabbitMQ, j.kubeClient, j.mongo)
-	err := r.Report(ctx, now)
+	r := rabbitmq.NewReporter(j.conf.RabbitMQ, j.kubeClient, j.opts.Network, j.mongo)
+	err := generate(ctx, j, now, r, db.CollectionRabbitmq, j.conf.RabbitMQ.Alerts)
 	if err != nil {
 		slog.LogAttrs(
 			ctx,
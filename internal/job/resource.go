@@ -6,6 +6,7 @@ import (
 	"log/slog"
 	"time"
 
+	"github.com/accuknox/rinc/internal/db"
 	"github.com/accuknox/rinc/internal/report/resource"
 )
 
@@ -14,10 +15,14 @@ func (j Job) GenerateResourceUtilizationReport(ctx context.Context, now time.Tim
 	r := resource.NewReporter(resource.Config{
 		ResourceUtilizationConfig: j.conf.ResourceUtilization,
 		KubeClient:                j.kubeClient,
+		Cache:                     j.cache,
 		MetricsClient:             j.metricsClient,
 		MongoClient:               j.mongo,
 	})
-	err := r.Report(ctx, now)
+	if ns, ok := j.namespaces[db.CollectionResourceUtilization]; ok {
+		r = r.WithNamespaces(ns)
+	}
+	err := generate(ctx, j, now, r, db.CollectionResourceUtilization, j.conf.ResourceUtilization.Alerts)
 	if err != nil {
 		slog.LogAttrs(
 			ctx,
@@ -0,0 +1,369 @@
+package kube
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+)
+
+// Resource is a kind of Kubernetes resource that can be cached.
+type Resource string
+
+const (
+	ResourcePods         Resource = "pods"
+	ResourceDeployments  Resource = "deployments"
+	ResourceStatefulSets Resource = "statefulsets"
+	ResourceJobs         Resource = "jobs"
+	ResourceEvents       Resource = "events"
+	ResourceNodes        Resource = "nodes"
+)
+
+const (
+	// indexOwner indexes objects by the UIDs of their owners.
+	indexOwner = "owner"
+	// indexInvolvedObject indexes events by the namespace and name of the
+	// object they are about.
+	indexInvolvedObject = "involvedObject"
+)
+
+// maxScopedNamespaces is the maximum number of namespaces whose objects are
+// listed and watched one namespace at a time. The objects of more namespaces
+// are listed and watched cluster-wide, which takes fewer requests.
+const maxScopedNamespaces = 10
+
+// Scope limits the objects of a resource that are listed and watched.
+type Scope struct {
+	// Namespaces are the namespaces to cache the objects of, all of them if
+	// nil. It is ignored for cluster-scoped resources, i.e., nodes.
+	Namespaces []string
+	// LabelSelector selects the objects to cache, all of them if empty.
+	LabelSelector string
+}
+
+// Scopes are the scopes of the cached resources.
+type Scopes map[Resource]Scope
+
+// Add widens the scope of resource r to include the objects in the
+// namespaces ns matching selector, which is the label selector of the reader
+// of r, or empty if it reads objects regardless of their labels.
+func (s Scopes) Add(r Resource, ns Namespaces, selector string) {
+	names := ns.Names()
+	scope, ok := s[r]
+	if !ok {
+		s[r] = Scope{Namespaces: names, LabelSelector: selector}
+		return
+	}
+	if scope.Namespaces == nil || names == nil {
+		scope.Namespaces = nil
+	} else {
+		for _, name := range names {
+			if !slices.Contains(scope.Namespaces, name) {
+				scope.Namespaces = append(scope.Namespaces, name)
+			}
+		}
+		slices.Sort(scope.Namespaces)
+	}
+	// readers with different label selectors share the objects of both
+	if scope.LabelSelector != selector {
+		scope.LabelSelector = ""
+	}
+	s[r] = scope
+}
+
+// Resources returns the resources of s in a stable order.
+func (s Scopes) Resources() []Resource {
+	resources := make([]Resource, 0, len(s))
+	for r := range s {
+		resources = append(resources, r)
+	}
+	slices.Sort(resources)
+	return resources
+}
+
+// Cache is a shared informer cache of Kubernetes resources. Each resource is
+// listed and watched once, and shared by all the reporters reading it,
+// instead of each reporter issuing its own List calls.
+//
+// Objects returned by the cache are shared and must not be modified.
+type Cache struct {
+	factories map[factoryKey]informers.SharedInformerFactory
+	informers map[Resource][]cache.SharedIndexInformer
+}
+
+// factoryKey is the namespace and label selector of the objects listed and
+// watched by the informers of a factory.
+type factoryKey struct {
+	namespace     string
+	labelSelector string
+}
+
+// NewCache creates a cache of the provided resources, whose objects are
+// limited to their scopes. Only these resources can be read from the cache,
+// which must be started with Start first.
+func NewCache(client kubernetes.Interface, scopes Scopes) (*Cache, error) {
+	c := &Cache{
+		factories: make(map[factoryKey]informers.SharedInformerFactory),
+		informers: make(map[Resource][]cache.SharedIndexInformer, len(scopes)),
+	}
+	for _, r := range scopes.Resources() {
+		scope := scopes[r]
+		namespaces := scope.Namespaces
+		if namespaces == nil || len(namespaces) > maxScopedNamespaces || r == ResourceNodes {
+			namespaces = []string{metav1.NamespaceAll}
+		}
+		// a selection of no namespaces has no informers
+		c.informers[r] = []cache.SharedIndexInformer{}
+		for _, ns := range namespaces {
+			informer, err := c.newInformer(client, r, factoryKey{
+				namespace:     ns,
+				labelSelector: scope.LabelSelector,
+			})
+			if err != nil {
+				return nil, err
+			}
+			c.informers[r] = append(c.informers[r], informer)
+		}
+	}
+	return c, nil
+}
+
+// newInformer creates an informer of resource r limited to the namespace and
+// label selector of key, sharing the factory of key with other resources.
+func (c *Cache) newInformer(client kubernetes.Interface, r Resource, key factoryKey) (cache.SharedIndexInformer, error) {
+	factory, ok := c.factories[key]
+	if !ok {
+		factory = informers.NewSharedInformerFactoryWithOptions(
+			client,
+			0, // reporters run once, no need to resync
+			informers.WithNamespace(key.namespace),
+			informers.WithTweakListOptions(func(opts *metav1.ListOptions) {
+				opts.LabelSelector = key.labelSelector
+			}),
+			informers.WithTransform(stripManagedFields),
+		)
+		c.factories[key] = factory
+	}
+	var (
+		informer cache.SharedIndexInformer
+		indexers cache.Indexers
+	)
+	switch r {
+	case ResourcePods:
+		informer = factory.Core().V1().Pods().Informer()
+		indexers = cache.Indexers{indexOwner: ownerIndexFunc}
+	case ResourceDeployments:
+		informer = factory.Apps().V1().Deployments().Informer()
+	case ResourceStatefulSets:
+		informer = factory.Apps().V1().StatefulSets().Informer()
+	case ResourceJobs:
+		informer = factory.Batch().V1().Jobs().Informer()
+	case ResourceEvents:
+		informer = factory.Core().V1().Events().Informer()
+		indexers = cache.Indexers{indexInvolvedObject: involvedObjectIndexFunc}
+	case ResourceNodes:
+		informer = factory.Core().V1().Nodes().Informer()
+	default:
+		return nil, fmt.Errorf("unknown resource %q", r)
+	}
+	if indexers != nil {
+		err := informer.AddIndexers(indexers)
+		if err != nil {
+			return nil, fmt.Errorf("adding %s indexers: %w", r, err)
+		}
+	}
+	return informer, nil
+}
+
+// Start starts the informers and waits until their caches are synced. The
+// informers are stopped once ctx is done.
+func (c *Cache) Start(ctx context.Context) error {
+	var unsynced []string
+	for _, factory := range c.factories {
+		factory.Start(ctx.Done())
+	}
+	for _, factory := range c.factories {
+		for typ, synced := range factory.WaitForCacheSync(ctx.Done()) {
+			if !synced {
+				unsynced = append(unsynced, typ.String())
+			}
+		}
+	}
+	if len(unsynced) != 0 {
+		slices.Sort(unsynced)
+		return fmt.Errorf("syncing informer caches of %s: %w",
+			strings.Join(slices.Compact(unsynced), ", "), context.Cause(ctx))
+	}
+	return nil
+}
+
+// Shutdown stops the informers and waits for them to terminate. The context
+// passed to Start must be done first.
+func (c *Cache) Shutdown() {
+	for _, factory := range c.factories {
+		factory.Shutdown()
+	}
+}
+
+// Pods returns the pods in the namespaces ns matching selector.
+func (c *Cache) Pods(ns Namespaces, selector labels.Selector) ([]*corev1.Pod, error) {
+	return list[*corev1.Pod](c, ResourcePods, ns, selector)
+}
+
+// PodsOwnedBy returns the pods that have an owner with the provided uid.
+func (c *Cache) PodsOwnedBy(uid types.UID) ([]*corev1.Pod, error) {
+	return byIndex[*corev1.Pod](c, ResourcePods, indexOwner, string(uid))
+}
+
+// PodsSelectedBy returns the pods in the namespace matching the label
+// selector of a workload, e.g., of a deployment.
+func (c *Cache) PodsSelectedBy(namespace string, selector *metav1.LabelSelector) ([]*corev1.Pod, error) {
+	sel, err := metav1.LabelSelectorAsSelector(selector)
+	if err != nil {
+		return nil, fmt.Errorf("parsing label selector: %w", err)
+	}
+	return list[*corev1.Pod](c, ResourcePods, newNamespaces([]string{namespace}), sel)
+}
+
+// Deployments returns the deployments in the namespaces ns matching
+// selector.
+func (c *Cache) Deployments(ns Namespaces, selector labels.Selector) ([]*appsv1.Deployment, error) {
+	return list[*appsv1.Deployment](c, ResourceDeployments, ns, selector)
+}
+
+// StatefulSets returns the statefulsets in the namespaces ns matching
+// selector.
+func (c *Cache) StatefulSets(ns Namespaces, selector labels.Selector) ([]*appsv1.StatefulSet, error) {
+	return list[*appsv1.StatefulSet](c, ResourceStatefulSets, ns, selector)
+}
+
+// Jobs returns the jobs in the namespaces ns matching selector.
+func (c *Cache) Jobs(ns Namespaces, selector labels.Selector) ([]*batchv1.Job, error) {
+	return list[*batchv1.Job](c, ResourceJobs, ns, selector)
+}
+
+// EventsFor returns the events about the object with the provided namespace
+// and name.
+func (c *Cache) EventsFor(namespace, name string) ([]*corev1.Event, error) {
+	return byIndex[*corev1.Event](c, ResourceEvents, indexInvolvedObject, namespace+"/"+name)
+}
+
+// Nodes returns all nodes.
+func (c *Cache) Nodes() ([]*corev1.Node, error) {
+	return list[*corev1.Node](c, ResourceNodes, Namespaces{}, labels.Everything())
+}
+
+func (c *Cache) informersOf(r Resource) ([]cache.SharedIndexInformer, error) {
+	if c == nil {
+		return nil, fmt.Errorf("%s are not cached, no cache provided", r)
+	}
+	informers, ok := c.informers[r]
+	if !ok {
+		return nil, fmt.Errorf("%s are not cached", r)
+	}
+	return informers, nil
+}
+
+// list returns the objects of resource r in the namespaces ns matching
+// selector, sorted by namespace and name. Objects of a single namespace are
+// looked up by the namespace index.
+func list[T metav1.Object](c *Cache, r Resource, ns Namespaces, selector labels.Selector) ([]T, error) {
+	informers, err := c.informersOf(r)
+	if err != nil {
+		return nil, err
+	}
+	var objs []T
+	appendFn := func(obj any) {
+		o := obj.(T)
+		if ns.Contains(o.GetNamespace()) && selector.Matches(labels.Set(o.GetLabels())) {
+			objs = append(objs, o)
+		}
+	}
+	for _, informer := range informers {
+		if ns.List != "" {
+			err = cache.ListAllByNamespace(informer.GetIndexer(), ns.List, selector, appendFn)
+		} else {
+			err = cache.ListAll(informer.GetIndexer(), selector, appendFn)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("listing cached %s: %w", r, err)
+		}
+	}
+	sortObjects(objs)
+	return objs, nil
+}
+
+// byIndex returns the objects of resource r whose index values include key,
+// sorted by namespace and name.
+func byIndex[T metav1.Object](c *Cache, r Resource, index, key string) ([]T, error) {
+	informers, err := c.informersOf(r)
+	if err != nil {
+		return nil, err
+	}
+	var objs []T
+	for _, informer := range informers {
+		items, err := informer.GetIndexer().ByIndex(index, key)
+		if err != nil {
+			return nil, fmt.Errorf("looking up cached %s by %s: %w", r, index, err)
+		}
+		for _, item := range items {
+			objs = append(objs, item.(T))
+		}
+	}
+	sortObjects(objs)
+	return objs, nil
+}
+
+// sortObjects sorts objs by namespace and name, the order of the List
+// responses of the Kubernetes API server, since the indexers of the
+// informers are unordered.
+func sortObjects[T metav1.Object](objs []T) {
+	slices.SortFunc(objs, func(a, b T) int {
+		return cmp.Or(
+			cmp.Compare(a.GetNamespace(), b.GetNamespace()),
+			cmp.Compare(a.GetName(), b.GetName()),
+		)
+	})
+}
+
+func ownerIndexFunc(obj any) ([]string, error) {
+	o, err := meta.Accessor(obj)
+	if err != nil {
+		return nil, err
+	}
+	owners := o.GetOwnerReferences()
+	keys := make([]string, len(owners))
+	for idx, owner := range owners {
+		keys[idx] = string(owner.UID)
+	}
+	return keys, nil
+}
+
+func involvedObjectIndexFunc(obj any) ([]string, error) {
+	ev, ok := obj.(*corev1.Event)
+	if !ok {
+		return nil, fmt.Errorf("want an event, got %T", obj)
+	}
+	return []string{ev.InvolvedObject.Namespace + "/" + ev.InvolvedObject.Name}, nil
+}
+
+// stripManagedFields removes the managed fields of objects before they are
+// cached, since they are never read and make up a large part of each object.
+func stripManagedFields(obj any) (any, error) {
+	if o, err := meta.Accessor(obj); err == nil {
+		o.SetManagedFields(nil)
+	}
+	return obj, nil
+}
@@ -0,0 +1,298 @@
+package kube
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func TestCache(t *testing.T) {
+	a := assert.New(t)
+	meta := func(ns, name string, lbls map[string]string) metav1.ObjectMeta {
+		return metav1.ObjectMeta{Namespace: ns, Name: name, Labels: lbls}
+	}
+	owned := meta("accuknox", "job-pod", nil)
+	owned.OwnerReferences = []metav1.OwnerReference{{UID: "job-uid", Name: "job"}}
+	client := fake.NewSimpleClientset(
+		&appsv1.Deployment{ObjectMeta: meta("accuknox", "api", map[string]string{"app": "api"})},
+		&appsv1.Deployment{ObjectMeta: meta("accuknox", "ui", map[string]string{"app": "ui"})},
+		&appsv1.Deployment{ObjectMeta: meta("monitoring", "grafana", nil)},
+		&corev1.Pod{ObjectMeta: meta("accuknox", "api-1", map[string]string{"app": "api"})},
+		&corev1.Pod{ObjectMeta: meta("accuknox", "api-2", map[string]string{"app": "api"})},
+		&corev1.Pod{ObjectMeta: meta("monitoring", "api-3", map[string]string{"app": "api"})},
+		&corev1.Pod{ObjectMeta: owned},
+		&corev1.Event{
+			ObjectMeta:     meta("accuknox", "api.1", nil),
+			InvolvedObject: corev1.ObjectReference{Namespace: "accuknox", Name: "api"},
+			Reason:         "ScalingReplicaSet",
+		},
+	)
+
+	c, err := NewCache(client, Scopes{ResourceDeployments: {}, ResourcePods: {}, ResourceEvents: {}})
+	if !a.NoError(err) {
+		return
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer func() {
+		cancel()
+		c.Shutdown()
+	}()
+	if !a.NoError(c.Start(ctx)) {
+		return
+	}
+
+	names := func(objs []metav1.Object) []string {
+		var names []string
+		for _, o := range objs {
+			names = append(names, o.GetNamespace()+"/"+o.GetName())
+		}
+		return names
+	}
+
+	depls, err := c.Deployments(Namespaces{}, labels.Everything())
+	a.NoError(err)
+	a.Len(depls, 3)
+
+	depls, err = c.Deployments(newNamespaces([]string{"accuknox"}), labels.SelectorFromSet(labels.Set{"app": "ui"}))
+	a.NoError(err)
+	a.ElementsMatch([]string{"accuknox/ui"}, names(objects(depls)))
+
+	pods, err := c.PodsSelectedBy("accuknox", &metav1.LabelSelector{
+		MatchLabels: map[string]string{"app": "api"},
+	})
+	a.NoError(err)
+	a.ElementsMatch([]string{"accuknox/api-1", "accuknox/api-2"}, names(objects(pods)))
+
+	pods, err = c.PodsOwnedBy("job-uid")
+	a.NoError(err)
+	a.ElementsMatch([]string{"accuknox/job-pod"}, names(objects(pods)))
+
+	events, err := c.EventsFor("accuknox", "api")
+	a.NoError(err)
+	if a.Len(events, 1) {
+		a.Equal("ScalingReplicaSet", events[0].Reason)
+	}
+
+	_, err = c.Jobs(Namespaces{}, labels.Everything())
+	a.ErrorContains(err, "jobs are not cached")
+}
+
+func objects[T metav1.Object](objs []T) []metav1.Object {
+	out := make([]metav1.Object, len(objs))
+	for idx, o := range objs {
+		out[idx] = o
+	}
+	return out
+}
+
+func TestCacheOrder(t *testing.T) {
+	a := assert.New(t)
+	meta := func(ns, name string) metav1.ObjectMeta {
+		return metav1.ObjectMeta{
+			Namespace:       ns,
+			Name:            name,
+			Labels:          map[string]string{"app": "api"},
+			OwnerReferences: []metav1.OwnerReference{{UID: "owner-uid"}},
+		}
+	}
+	event := func(ns, name string) *corev1.Event {
+		return &corev1.Event{
+			ObjectMeta:     meta(ns, name),
+			InvolvedObject: corev1.ObjectReference{Namespace: "accuknox", Name: "api"},
+		}
+	}
+	// created out of order, with more objects than the indexers are likely
+	// to iterate in order by chance
+	var objs []runtime.Object
+	for _, ns := range []string{"monitoring", "accuknox", "kube-system"} {
+		for _, name := range []string{"ui", "api", "worker", "db"} {
+			objs = append(objs,
+				&appsv1.Deployment{ObjectMeta: meta(ns, name)},
+				&appsv1.StatefulSet{ObjectMeta: meta(ns, name)},
+				&batchv1.Job{ObjectMeta: meta(ns, name)},
+				&corev1.Pod{ObjectMeta: meta(ns, name)},
+				event(ns, name),
+			)
+		}
+	}
+	client := fake.NewSimpleClientset(objs...)
+	c, err := NewCache(client, Scopes{
+		ResourceDeployments:  {},
+		ResourceStatefulSets: {},
+		ResourceJobs:         {},
+		ResourcePods:         {},
+		ResourceEvents:       {},
+	})
+	if !a.NoError(err) {
+		return
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer func() {
+		cancel()
+		c.Shutdown()
+	}()
+	if !a.NoError(c.Start(ctx)) {
+		return
+	}
+
+	want := []string{
+		"accuknox/api", "accuknox/db", "accuknox/ui", "accuknox/worker",
+		"kube-system/api", "kube-system/db", "kube-system/ui", "kube-system/worker",
+		"monitoring/api", "monitoring/db", "monitoring/ui", "monitoring/worker",
+	}
+	names := func(objs []metav1.Object, err error) []string {
+		a.NoError(err)
+		var names []string
+		for _, o := range objs {
+			names = append(names, o.GetNamespace()+"/"+o.GetName())
+		}
+		return names
+	}
+	all := Namespaces{}
+	selector := labels.SelectorFromSet(labels.Set{"app": "api"})
+	// lists the same objects repeatedly, as the order of the indexers
+	// changes between iterations
+	for range 5 {
+		depls, err := c.Deployments(all, selector)
+		a.Equal(want, names(objects(depls), err), "deployments")
+		sts, err := c.StatefulSets(all, selector)
+		a.Equal(want, names(objects(sts), err), "statefulsets")
+		jobs, err := c.Jobs(all, selector)
+		a.Equal(want, names(objects(jobs), err), "jobs")
+		pods, err := c.Pods(all, selector)
+		a.Equal(want, names(objects(pods), err), "pods")
+		pods, err = c.PodsOwnedBy("owner-uid")
+		a.Equal(want, names(objects(pods), err), "owned pods")
+		events, err := c.EventsFor("accuknox", "api")
+		a.Equal(want, names(objects(events), err), "events")
+		pods, err = c.Pods(newNamespaces([]string{"kube-system"}), selector)
+		a.Equal(want[4:8], names(objects(pods), err), "pods of a namespace")
+	}
+}
+
+func TestCacheScopes(t *testing.T) {
+	a := assert.New(t)
+	client := fake.NewSimpleClientset(
+		&appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{
+			Namespace: "accuknox", Name: "api", Labels: map[string]string{"tier": "backend"},
+		}},
+		&appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{
+			Namespace: "accuknox", Name: "ui", Labels: map[string]string{"tier": "frontend"},
+		}},
+		&appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{
+			Namespace: "monitoring", Name: "grafana", Labels: map[string]string{"tier": "backend"},
+		}},
+		&corev1.Event{ObjectMeta: metav1.ObjectMeta{Namespace: "accuknox", Name: "api.1"}},
+		&corev1.Event{ObjectMeta: metav1.ObjectMeta{Namespace: "kube-system", Name: "dns.1"}},
+		&corev1.Event{ObjectMeta: metav1.ObjectMeta{Namespace: "monitoring", Name: "grafana.1"}},
+		&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-1"}},
+	)
+	c, err := NewCache(client, Scopes{
+		ResourceDeployments: {Namespaces: []string{"accuknox"}, LabelSelector: "tier=backend"},
+		ResourceEvents:      {Namespaces: []string{"accuknox", "monitoring"}},
+		// cluster-scoped
+		ResourceNodes: {Namespaces: []string{"accuknox"}},
+		// none selected
+		ResourcePods: {Namespaces: []string{}},
+	})
+	if !a.NoError(err) {
+		return
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer func() {
+		cancel()
+		c.Shutdown()
+	}()
+	if !a.NoError(c.Start(ctx)) {
+		return
+	}
+
+	type list struct {
+		resource, namespace, selector string
+	}
+	var lists []list
+	for _, action := range client.Actions() {
+		if l, ok := action.(k8stesting.ListAction); ok {
+			lists = append(lists, list{
+				resource:  l.GetResource().Resource,
+				namespace: l.GetNamespace(),
+				selector:  l.GetListRestrictions().Labels.String(),
+			})
+		}
+	}
+	a.ElementsMatch([]list{
+		{resource: "deployments", namespace: "accuknox", selector: "tier=backend"},
+		{resource: "events", namespace: "accuknox"},
+		{resource: "events", namespace: "monitoring"},
+		{resource: "nodes"},
+	}, lists)
+
+	depls, err := c.Deployments(Namespaces{}, labels.Everything())
+	a.NoError(err)
+	if a.Len(depls, 1) {
+		a.Equal("api", depls[0].Name)
+	}
+	// the events have no involved object
+	events, err := c.EventsFor("", "")
+	a.NoError(err)
+	if a.Len(events, 2) {
+		a.Equal("api.1", events[0].Name)
+		a.Equal("grafana.1", events[1].Name)
+	}
+	nodes, err := c.Nodes()
+	a.NoError(err)
+	a.Len(nodes, 1)
+	pods, err := c.Pods(Namespaces{}, labels.Everything())
+	a.NoError(err)
+	a.Empty(pods)
+}
+
+func TestScopesAdd(t *testing.T) {
+	tests := []struct {
+		name string
+		adds []Namespaces
+		sels []string
+		want Scope
+	}{
+		{
+			name: "single reader",
+			adds: []Namespaces{newNamespaces([]string{"accuknox"})},
+			sels: []string{"tier=backend"},
+			want: Scope{Namespaces: []string{"accuknox"}, LabelSelector: "tier=backend"},
+		},
+		{
+			name: "shared selector",
+			adds: []Namespaces{newNamespaces([]string{"monitoring"}), newNamespaces([]string{"accuknox"})},
+			sels: []string{"tier=backend", "tier=backend"},
+			want: Scope{Namespaces: []string{"accuknox", "monitoring"}, LabelSelector: "tier=backend"},
+		},
+		{
+			name: "different selectors",
+			adds: []Namespaces{newNamespaces([]string{"accuknox"}), newNamespaces([]string{"accuknox"})},
+			sels: []string{"tier=backend", "tier=frontend"},
+			want: Scope{Namespaces: []string{"accuknox"}},
+		},
+		{
+			name: "all namespaces",
+			adds: []Namespaces{newNamespaces([]string{"accuknox"}), {}},
+			sels: []string{"", ""},
+			want: Scope{},
+		},
+	}
+	for _, tt := range tests {
+		s := make(Scopes)
+		for idx, ns := range tt.adds {
+			s.Add(ResourceDeployments, ns, tt.sels[idx])
+		}
+		assert.Equal(t, tt.want, s[ResourceDeployments], tt.name)
+	}
+}
@@ -12,13 +12,9 @@ import (
 )
 
 // NewClient creates a new Kubernetes API server client using the provided
-// configuration.
-func NewClient(c conf.KubernetesClient) (*kubernetes.Clientset, error) {
-	conf, err := config(c)
-	if err != nil {
-		return nil, err
-	}
-	client, err := kubernetes.NewForConfig(conf)
+// client configuration.
+func NewClient(c *rest.Config) (*kubernetes.Clientset, error) {
+	client, err := kubernetes.NewForConfig(c)
 	if err != nil {
 		return nil, fmt.Errorf("creating new kube client: %w", err)
 	}
@@ -26,20 +22,18 @@ func NewClient(c conf.KubernetesClient) (*kubernetes.Clientset, error) {
 }
 
 // NewMetricsClient creates a new Kubernetes Metrics API client using the
-// provided configuration.
-func NewMetricsClient(c conf.KubernetesClient) (*metrics.Clientset, error) {
-	conf, err := config(c)
-	if err != nil {
-		return nil, err
-	}
-	client, err := metrics.NewForConfig(conf)
+// provided client configuration.
+func NewMetricsClient(c *rest.Config) (*metrics.Clientset, error) {
+	client, err := metrics.NewForConfig(c)
 	if err != nil {
 		return nil, fmt.Errorf("creating new metrics client: %w", err)
 	}
 	return client, err
 }
 
-func config(c conf.KubernetesClient) (*rest.Config, error) {
+// RESTConfig returns the client configuration of the Kubernetes API server
+// using the provided configuration.
+func RESTConfig(c conf.KubernetesClient) (*rest.Config, error) {
 	if c.InCluster {
 		conf, err := rest.InClusterConfig()
 		if err != nil {
@@ -0,0 +1,37 @@
+// Package kubetest provides utilities for testing code that reads from the
+// Kubernetes API, e.g., the reporters, against fake clientsets.
+package kubetest
+
+import (
+	"context"
+	"testing"
+
+	"github.com/accuknox/rinc/internal/kube"
+
+	"k8s.io/client-go/kubernetes"
+)
+
+// NewCache creates an informer cache of all objects of the provided resources
+// backed by client, usually a fake clientset, and waits until it is synced.
+// The cache is shut down when the test finishes.
+func NewCache(t testing.TB, client kubernetes.Interface, resources ...kube.Resource) *kube.Cache {
+	t.Helper()
+	scopes := make(kube.Scopes, len(resources))
+	for _, r := range resources {
+		scopes.Add(r, kube.Namespaces{}, "")
+	}
+	c, err := kube.NewCache(client, scopes)
+	if err != nil {
+		t.Fatalf("creating informer cache: %s", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(func() {
+		cancel()
+		c.Shutdown()
+	})
+	err = c.Start(ctx)
+	if err != nil {
+		t.Fatalf("starting informer cache: %s", err)
+	}
+	return c
+}
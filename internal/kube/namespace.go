@@ -0,0 +1,129 @@
+package kube
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/accuknox/rinc/internal/conf"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// Namespaces are the namespaces selected by a conf.NamespaceSelector.
+type Namespaces struct {
+	// List is the namespace to list resources in. It is a single namespace
+	// if only one is selected, and metav1.NamespaceAll otherwise, in which
+	// case the listed resources must be filtered with Contains.
+	List string
+	// names are the selected namespaces, nil if all are selected.
+	names map[string]bool
+}
+
+// Contains returns true if the namespace ns is selected.
+func (n Namespaces) Contains(ns string) bool {
+	return n.names == nil || n.names[ns]
+}
+
+// Names returns the selected namespaces in order, or nil if all are
+// selected.
+func (n Namespaces) Names() []string {
+	if n.names == nil {
+		return nil
+	}
+	names := make([]string, 0, len(n.names))
+	for name := range n.names {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// String returns a human-readable representation of the selected
+// namespaces, used in logs and errors.
+func (n Namespaces) String() string {
+	if n.names == nil {
+		return "*"
+	}
+	return strings.Join(n.Names(), ",")
+}
+
+// ResolveNamespaces returns the namespaces selected by s. The namespaces are
+// only listed from the Kubernetes API server if s can't be resolved without,
+// i.e., if it contains glob patterns, exclusions or a label selector.
+func ResolveNamespaces(ctx context.Context, client kubernetes.Interface, s conf.NamespaceSelector) (Namespaces, error) {
+	if s.IsEmpty() {
+		return Namespaces{List: metav1.NamespaceAll}, nil
+	}
+	if len(s.Exclude) == 0 && s.LabelSelector == "" && !hasGlob(s.Include) {
+		return newNamespaces(s.Include), nil
+	}
+
+	var (
+		names    []string
+		cntinue  string
+		selected = func(ns string) bool {
+			return (len(s.Include) == 0 || matchAny(s.Include, ns)) &&
+				!matchAny(s.Exclude, ns)
+		}
+	)
+	for {
+		nsList, err := client.
+			CoreV1().
+			Namespaces().
+			List(ctx, metav1.ListOptions{
+				LabelSelector: s.LabelSelector,
+				Continue:      cntinue,
+				Limit:         100,
+			})
+		if err != nil {
+			return Namespaces{}, fmt.Errorf("listing namespaces: %w", err)
+		}
+		for _, ns := range nsList.Items {
+			if selected(ns.Name) {
+				names = append(names, ns.Name)
+			}
+		}
+		cntinue = nsList.Continue
+		if cntinue == "" {
+			break
+		}
+	}
+	return newNamespaces(names), nil
+}
+
+func newNamespaces(names []string) Namespaces {
+	n := Namespaces{
+		List:  metav1.NamespaceAll,
+		names: make(map[string]bool, len(names)),
+	}
+	for _, name := range names {
+		n.names[name] = true
+	}
+	if len(n.names) == 1 {
+		n.List = names[0]
+	}
+	return n
+}
+
+func hasGlob(patterns []string) bool {
+	for _, p := range patterns {
+		if strings.ContainsAny(p, `*?[\`) {
+			return true
+		}
+	}
+	return false
+}
+
+func matchAny(patterns []string, name string) bool {
+	for _, p := range patterns {
+		// patterns are validated when loading the configuration
+		if ok, _ := path.Match(p, name); ok {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,109 @@
+package kube
+
+import (
+	"context"
+	"testing"
+
+	"github.com/accuknox/rinc/internal/conf"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestResolveNamespaces(t *testing.T) {
+	namespace := func(name string, labels map[string]string) *corev1.Namespace {
+		return &corev1.Namespace{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Labels: labels},
+		}
+	}
+	client := fake.NewSimpleClientset(
+		namespace("default", nil),
+		namespace("kube-system", nil),
+		namespace("monitoring", nil),
+		namespace("accuknox-agents", map[string]string{"team": "platform"}),
+		namespace("accuknox-chart", map[string]string{"team": "platform"}),
+		namespace("accuknox-ui", map[string]string{"team": "frontend"}),
+	)
+
+	tests := []struct {
+		name     string
+		selector conf.NamespaceSelector
+		list     string
+		selected []string
+		ignored  []string
+	}{
+		{
+			name:     "empty",
+			selector: conf.NamespaceSelector{},
+			list:     metav1.NamespaceAll,
+			selected: []string{"default", "kube-system", "unknown"},
+		},
+		{
+			name:     "single namespace",
+			selector: conf.NamespaceSelector{Include: []string{"monitoring"}},
+			list:     "monitoring",
+			selected: []string{"monitoring"},
+			ignored:  []string{"default"},
+		},
+		{
+			name:     "multiple namespaces",
+			selector: conf.NamespaceSelector{Include: []string{"default", "monitoring"}},
+			list:     metav1.NamespaceAll,
+			selected: []string{"default", "monitoring"},
+			ignored:  []string{"kube-system"},
+		},
+		{
+			name:     "exclude",
+			selector: conf.NamespaceSelector{Exclude: []string{"kube-system", "monitoring"}},
+			list:     metav1.NamespaceAll,
+			selected: []string{"default", "accuknox-agents", "accuknox-ui"},
+			ignored:  []string{"kube-system", "monitoring"},
+		},
+		{
+			name: "glob",
+			selector: conf.NamespaceSelector{
+				Include: []string{"accuknox-*"},
+				Exclude: []string{"*-ui"},
+			},
+			list:     metav1.NamespaceAll,
+			selected: []string{"accuknox-agents", "accuknox-chart"},
+			ignored:  []string{"accuknox-ui", "default"},
+		},
+		{
+			name:     "label selector",
+			selector: conf.NamespaceSelector{LabelSelector: "team=platform"},
+			list:     metav1.NamespaceAll,
+			selected: []string{"accuknox-agents", "accuknox-chart"},
+			ignored:  []string{"accuknox-ui", "default"},
+		},
+		{
+			name: "label selector matching a single namespace",
+			selector: conf.NamespaceSelector{
+				LabelSelector: "team=platform",
+				Exclude:       []string{"accuknox-chart"},
+			},
+			list:     "accuknox-agents",
+			selected: []string{"accuknox-agents"},
+			ignored:  []string{"accuknox-chart"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := assert.New(t)
+			ns, err := ResolveNamespaces(context.Background(), client, tt.selector)
+			if !a.NoError(err) {
+				return
+			}
+			a.Equal(tt.list, ns.List)
+			for _, name := range tt.selected {
+				a.Truef(ns.Contains(name), "%q should be selected", name)
+			}
+			for _, name := range tt.ignored {
+				a.Falsef(ns.Contains(name), "%q should not be selected", name)
+			}
+		})
+	}
+}
@@ -0,0 +1,144 @@
+// Package output writes the scraped reports, i.e., the metrics document of
+// every reporter and the firing alerts, as JSON or YAML to files or stdout
+// instead of storing them in MongoDB.
+package output
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/accuknox/rinc/internal/conf"
+	"github.com/accuknox/rinc/internal/db"
+
+	"sigs.k8s.io/yaml"
+)
+
+// Writer writes the scraped reports. With a directory, the metrics document
+// of every reporter is written into a file named after its collection, e.g.,
+// `ceph.json`, as soon as it is scraped, and the alerts of all reporters into
+// `alerts.json` on Close. Otherwise, a single document with the metrics keyed
+// by collection and the alerts under the `alerts` key is written to out on
+// Close.
+type Writer struct {
+	format string
+	dir    string
+	out    io.Writer
+
+	mu      sync.Mutex
+	metrics map[string]any
+	alerts  []db.AlertDocument
+}
+
+// New returns a Writer of the provided output configuration. out is only
+// written to if no directory is configured.
+func New(c conf.Output, out io.Writer) (*Writer, error) {
+	if c.Dir != "" {
+		err := os.MkdirAll(c.Dir, 0o755)
+		if err != nil {
+			return nil, fmt.Errorf("creating output directory: %w", err)
+		}
+	}
+	return &Writer{
+		format:  c.Format,
+		dir:     c.Dir,
+		out:     out,
+		metrics: make(map[string]any),
+	}, nil
+}
+
+// Write writes the metrics document of the report stored in collection, and
+// keeps the alerts that fired on it for Close.
+func (w *Writer) Write(collection string, now time.Time, metrics any, alerts []db.Alert) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.alerts = append(w.alerts, db.AlertDocument{
+		Timestamp: now,
+		From:      collection,
+		Alerts:    alerts,
+	})
+	if w.dir == "" {
+		w.metrics[collection] = metrics
+		return nil
+	}
+	return w.writeFile(collection, metrics)
+}
+
+// Close writes the alerts, and the metrics if no directory is configured.
+func (w *Writer) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	alerts := w.alerts
+	if alerts == nil {
+		alerts = []db.AlertDocument{}
+	}
+	if w.dir != "" {
+		return w.writeFile(db.CollectionAlerts, alerts)
+	}
+	doc := make(map[string]any, len(w.metrics)+1)
+	for collection, metrics := range w.metrics {
+		doc[collection] = metrics
+	}
+	doc[db.CollectionAlerts] = alerts
+	data, err := w.marshal(doc)
+	if err != nil {
+		return err
+	}
+	_, err = w.out.Write(data)
+	if err != nil {
+		return fmt.Errorf("writing reports: %w", err)
+	}
+	return nil
+}
+
+// Severity returns the highest severity of the alerts that fired, or an
+// empty severity if none did.
+func (w *Writer) Severity() conf.Severity {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	var severity conf.Severity
+	for _, doc := range w.alerts {
+		for _, alert := range doc.Alerts {
+			if alert.Severity.Rank() > severity.Rank() {
+				severity = alert.Severity
+			}
+		}
+	}
+	return severity
+}
+
+func (w *Writer) writeFile(collection string, v any) error {
+	data, err := w.marshal(v)
+	if err != nil {
+		return err
+	}
+	path := filepath.Join(w.dir, collection+"."+w.format)
+	err = os.WriteFile(path, data, 0o644)
+	if err != nil {
+		return fmt.Errorf("writing %q: %w", path, err)
+	}
+	return nil
+}
+
+func (w *Writer) marshal(v any) ([]byte, error) {
+	switch w.format {
+	case conf.OutputJSON:
+		data, err := json.MarshalIndent(v, "", "  ")
+		if err != nil {
+			return nil, fmt.Errorf("marshaling json: %w", err)
+		}
+		return append(data, '\n'), nil
+	case conf.OutputYAML:
+		data, err := yaml.Marshal(v)
+		if err != nil {
+			return nil, fmt.Errorf("marshaling yaml: %w", err)
+		}
+		return data, nil
+	default:
+		return nil, fmt.Errorf("unsupported output format %q", w.format)
+	}
+}
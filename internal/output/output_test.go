@@ -0,0 +1,172 @@
+package output_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/accuknox/rinc/internal/conf"
+	"github.com/accuknox/rinc/internal/db"
+	"github.com/accuknox/rinc/internal/output"
+	"github.com/accuknox/rinc/types/dass"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var now = time.Date(2024, 10, 18, 12, 0, 0, 0, time.UTC)
+
+func write(a *assert.Assertions, w *output.Writer) {
+	a.NoError(w.Write(db.CollectionDass, now, dass.Metrics{
+		Timestamp:   now,
+		Deployments: []dass.Resource{{Name: "api", Namespace: "accuknox"}},
+	}, []db.Alert{
+		{Message: "api is unavailable", Severity: conf.SeverityWarning},
+	}))
+	a.NoError(w.Write(db.CollectionLongJobs, now, struct{ Timestamp time.Time }{now}, nil))
+}
+
+func TestWriterStdout(t *testing.T) {
+	tests := []struct {
+		format string
+		want   string
+	}{
+		{
+			format: conf.OutputJSON,
+			want: `{
+  "alerts": [
+    {
+      "Timestamp": "2024-10-18T12:00:00Z",
+      "From": "dass",
+      "Alerts": [
+        {
+          "Message": "api is unavailable",
+          "Severity": "warning"
+        }
+      ]
+    },
+    {
+      "Timestamp": "2024-10-18T12:00:00Z",
+      "From": "longjobs",
+      "Alerts": null
+    }
+  ],
+  "dass": {
+    "Timestamp": "2024-10-18T12:00:00Z",
+    "Deployments": [
+      {
+        "Name": "api",
+        "Namespace": "accuknox",
+        "Age": 0,
+        "DesiredReplicas": 0,
+        "ReadyReplicas": 0,
+        "AvailableReplicas": 0,
+        "UpdatedReplicas": 0,
+        "Events": null,
+        "IsReplicaFailure": false,
+        "IsAvailable": false
+      }
+    ],
+    "Statefulsets": null
+  },
+  "longjobs": {
+    "Timestamp": "2024-10-18T12:00:00Z"
+  }
+}
+`,
+		},
+		{
+			format: conf.OutputYAML,
+			want: `alerts:
+- Alerts:
+  - Message: api is unavailable
+    Severity: warning
+  From: dass
+  Timestamp: "2024-10-18T12:00:00Z"
+- Alerts: null
+  From: longjobs
+  Timestamp: "2024-10-18T12:00:00Z"
+dass:
+  Deployments:
+  - Age: 0
+    AvailableReplicas: 0
+    DesiredReplicas: 0
+    Events: null
+    IsAvailable: false
+    IsReplicaFailure: false
+    Name: api
+    Namespace: accuknox
+    ReadyReplicas: 0
+    UpdatedReplicas: 0
+  Statefulsets: null
+  Timestamp: "2024-10-18T12:00:00Z"
+longjobs:
+  Timestamp: "2024-10-18T12:00:00Z"
+`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			a := assert.New(t)
+			var out bytes.Buffer
+			w, err := output.New(conf.Output{Format: tt.format}, &out)
+			if !a.NoError(err) {
+				return
+			}
+			write(a, w)
+			a.Empty(out.String(), "written before close")
+			a.NoError(w.Close())
+			a.Equal(tt.want, out.String())
+			a.Equal(conf.SeverityWarning, w.Severity())
+		})
+	}
+}
+
+func TestWriterDir(t *testing.T) {
+	a := assert.New(t)
+	dir := filepath.Join(t.TempDir(), "reports")
+	var out bytes.Buffer
+	w, err := output.New(conf.Output{Format: conf.OutputJSON, Dir: dir}, &out)
+	if !a.NoError(err) {
+		return
+	}
+	write(a, w)
+	a.FileExists(filepath.Join(dir, "dass.json"))
+	a.FileExists(filepath.Join(dir, "longjobs.json"))
+	a.NoFileExists(filepath.Join(dir, "alerts.json"))
+	a.NoError(w.Close())
+	a.Empty(out.String())
+
+	// metrics files can be loaded as they are, e.g., by the expr command
+	data, err := os.ReadFile(filepath.Join(dir, "dass.json"))
+	if a.NoError(err) {
+		var metrics dass.Metrics
+		a.NoError(json.Unmarshal(data, &metrics))
+		a.Equal(now, metrics.Timestamp)
+		a.Len(metrics.Deployments, 1)
+	}
+	data, err = os.ReadFile(filepath.Join(dir, "alerts.json"))
+	if a.NoError(err) {
+		var alerts []db.AlertDocument
+		a.NoError(json.Unmarshal(data, &alerts))
+		a.Len(alerts, 2)
+	}
+}
+
+func TestWriterSeverity(t *testing.T) {
+	a := assert.New(t)
+	w, err := output.New(conf.Output{Format: conf.OutputJSON}, new(bytes.Buffer))
+	if !a.NoError(err) {
+		return
+	}
+	a.Equal(conf.Severity(""), w.Severity())
+	a.NoError(w.Write(db.CollectionCeph, now, nil, []db.Alert{
+		{Severity: conf.SeverityInfo},
+		{Severity: conf.SeverityCritical},
+		{Severity: conf.SeverityWarning},
+	}))
+	a.Equal(conf.SeverityCritical, w.Severity())
+}
@@ -0,0 +1,79 @@
+package repl
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/accuknox/rinc/internal/conf"
+	"github.com/accuknox/rinc/internal/db"
+	"github.com/accuknox/rinc/internal/util"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// LoadFile loads the report stored in a JSON file into the metrics type of
+// the provided collection.
+func LoadFile(collection, path string) (any, error) {
+	metrics, err := db.NewMetrics(collection)
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading %q: %w", path, err)
+	}
+	if err := json.Unmarshal(data, metrics); err != nil {
+		return nil, fmt.Errorf("decoding %q: %w", path, err)
+	}
+	return metrics, nil
+}
+
+// LoadDocument loads the report stored in MongoDB at the provided isosec
+// timestamp into the metrics type of the provided collection. The latest
+// report is loaded if at is empty.
+func LoadDocument(ctx context.Context, client *mongo.Client, collection, at string) (any, error) {
+	metrics, err := db.NewMetrics(collection)
+	if err != nil {
+		return nil, err
+	}
+
+	filter := bson.M{}
+	if at != "" {
+		timestamp, err := time.Parse(util.IsosecLayout, at)
+		if err != nil {
+			return nil, fmt.Errorf("parsing timestamp %q: %w", at, err)
+		}
+		filter["timestamp"] = timestamp
+	}
+	opts := options.FindOne().SetSort(bson.D{{Key: "timestamp", Value: -1}})
+
+	result := db.
+		Database(client).
+		Collection(collection).
+		FindOne(ctx, filter, opts)
+	if err := result.Err(); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, fmt.Errorf("no %s report found", collection)
+		}
+		return nil, fmt.Errorf("fetching %s report: %w", collection, err)
+	}
+	if err := result.Decode(metrics); err != nil {
+		return nil, fmt.Errorf("decoding %s report: %w", collection, err)
+	}
+	return metrics, nil
+}
+
+// Load loads the report selected by the provided REPL configuration. client
+// may be nil if the report is loaded from a file.
+func Load(ctx context.Context, client *mongo.Client, c conf.REPL) (any, error) {
+	if c.File != "" {
+		return LoadFile(c.Collection, c.File)
+	}
+	return LoadDocument(ctx, client, c.Collection, c.At)
+}
@@ -0,0 +1,169 @@
+// Package repl implements an interactive prompt to evaluate expressions
+// against a stored report.
+package repl
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+
+	"github.com/accuknox/rinc/internal/expr"
+)
+
+const (
+	prompt             = "> "
+	continuationPrompt = "... "
+)
+
+// Run reads expressions from in, evaluates them against data and writes the
+// results to out until in is exhausted or the user exits. An expression can
+// be continued on the next line by ending the line with a backslash.
+func Run(ctx context.Context, in io.Reader, out io.Writer, data any) error {
+	sc := bufio.NewScanner(in)
+	var lines []string
+
+	fmt.Fprint(out, prompt)
+	for sc.Scan() {
+		line := sc.Text()
+		if cont, ok := strings.CutSuffix(line, `\`); ok {
+			lines = append(lines, cont)
+			fmt.Fprint(out, continuationPrompt)
+			continue
+		}
+		text := strings.TrimSpace(strings.Join(append(lines, line), "\n"))
+		lines = nil
+
+		switch text {
+		case "":
+		case "exit", "quit":
+			return nil
+		default:
+			eval(ctx, out, text, data)
+		}
+		fmt.Fprint(out, prompt)
+	}
+	fmt.Fprintln(out)
+	return sc.Err()
+}
+
+func eval(ctx context.Context, out io.Writer, text string, data any) {
+	ev, err := expr.Parse(text)
+	if err == nil {
+		var res any
+		res, err = ev(ctx, data)
+		if err == nil {
+			fmt.Fprintln(out, format(res))
+			return
+		}
+	}
+	var exprErr *expr.Error
+	if errors.As(err, &exprErr) {
+		fmt.Fprintln(out, exprErr.Snippet())
+	}
+	fmt.Fprintf(out, "error: %s\n", err)
+}
+
+// format formats the result of an expression. Composite values are formatted
+// as indented JSON keyed by Go field names, i.e., the names used in
+// expressions, everything else using its default format.
+func format(v any) string {
+	if v == nil {
+		return "nil"
+	}
+	switch reflect.Indirect(reflect.ValueOf(v)).Kind() {
+	case reflect.Struct, reflect.Map, reflect.Slice, reflect.Array:
+		if _, ok := v.(fmt.Stringer); ok {
+			break
+		}
+		out, err := json.MarshalIndent(plain(reflect.ValueOf(v)), "", "  ")
+		if err == nil {
+			return string(out)
+		}
+	}
+	return fmt.Sprint(v)
+}
+
+// object is a JSON object that keeps the order of its fields.
+type object []field
+
+type field struct {
+	name  string
+	value any
+}
+
+func (o object) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for idx, f := range o {
+		if idx > 0 {
+			buf.WriteByte(',')
+		}
+		name, err := json.Marshal(f.name)
+		if err != nil {
+			return nil, err
+		}
+		value, err := json.Marshal(f.value)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(name)
+		buf.WriteByte(':')
+		buf.Write(value)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// plain converts v into a value that marshals to JSON using the Go field
+// names of structs instead of their json tags.
+func plain(v reflect.Value) any {
+	for v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return nil
+		}
+		v = v.Elem()
+	}
+	if !v.IsValid() {
+		return nil
+	}
+	if v.CanInterface() {
+		if s, ok := v.Interface().(fmt.Stringer); ok {
+			return s.String()
+		}
+	}
+
+	switch v.Kind() {
+	case reflect.Struct:
+		var o object
+		for idx := 0; idx < v.NumField(); idx++ {
+			if f := v.Type().Field(idx); f.IsExported() {
+				o = append(o, field{name: f.Name, value: plain(v.Field(idx))})
+			}
+		}
+		return o
+	case reflect.Map:
+		m := make(map[string]any, v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			m[fmt.Sprint(iter.Key().Interface())] = plain(iter.Value())
+		}
+		return m
+	case reflect.Slice, reflect.Array:
+		if v.Kind() == reflect.Slice && v.IsNil() {
+			return []any{}
+		}
+		l := make([]any, v.Len())
+		for idx := range l {
+			l[idx] = plain(v.Index(idx))
+		}
+		return l
+	default:
+		return v.Interface()
+	}
+}
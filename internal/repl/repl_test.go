@@ -0,0 +1,67 @@
+package repl_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/accuknox/rinc/internal/db"
+	"github.com/accuknox/rinc/internal/repl"
+	"github.com/accuknox/rinc/types/ceph"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRun(t *testing.T) {
+	a := assert.New(t)
+	metrics := ceph.Metrics{}
+	metrics.Status.Hosts = 3
+
+	inputs := []struct {
+		in   string
+		want string
+	}{
+		{
+			in:   "Status.Hosts + 1\n",
+			want: "> 4\n> \n",
+		},
+		{
+			in:   "Status.Hosts > 2 &&\\\n  Status.Hosts < 4\nexit\n",
+			want: "> ... true\n> ",
+		},
+		{
+			in:   "Status.Hostz\n",
+			want: "> Status.Hostz\n^^^^^^^^^^^^\nerror: 1:1: \"Status.Hostz\": field \"Hostz\" does not exist on \"Status\", did you mean \"Hosts\"?\n> \n",
+		},
+		{
+			in:   "\n{\"x\": 1}\n",
+			want: "> > {\n  \"x\": 1\n}\n> \n",
+		},
+	}
+	for _, i := range inputs {
+		var out strings.Builder
+		err := repl.Run(context.TODO(), strings.NewReader(i.in), &out, metrics)
+		if a.NoError(err, "in=%q", i.in) {
+			a.Equal(i.want, out.String(), "in=%q", i.in)
+		}
+	}
+}
+
+func TestLoadFile(t *testing.T) {
+	a := assert.New(t)
+	path := filepath.Join(t.TempDir(), "ceph.json")
+	err := os.WriteFile(path, []byte(`{"status": {"hosts": 3}}`), 0o600)
+	if !a.NoError(err) {
+		return
+	}
+
+	metrics, err := repl.LoadFile(db.CollectionCeph, path)
+	if a.NoError(err) && a.IsType(&ceph.Metrics{}, metrics) {
+		a.Equal(uint(3), metrics.(*ceph.Metrics).Status.Hosts)
+	}
+
+	_, err = repl.LoadFile("foo", path)
+	a.Error(err)
+}
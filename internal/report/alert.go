@@ -2,10 +2,14 @@ package report
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"log/slog"
 
 	"github.com/accuknox/rinc/internal/conf"
 	"github.com/accuknox/rinc/internal/db"
+	"github.com/accuknox/rinc/internal/expr"
+	"github.com/accuknox/rinc/internal/telemetry"
 )
 
 // SoftEvaluateAlerts evaluates the provided alerts using the given data and
@@ -18,12 +22,15 @@ func SoftEvaluateAlerts(ctx context.Context, alerts []conf.Alert, data any) []db
 	for _, alert := range alerts {
 		fire, err := alert.When.Evaluable.EvalBool(ctx, data)
 		if err != nil {
+			telemetry.AlertEvaluationFailed(ctx)
 			slog.LogAttrs(
 				ctx,
 				slog.LevelError,
 				"evaluating boolean expressions",
-				slog.String("error", err.Error()),
-				slog.String("expr", alert.When.Text),
+				append(
+					exprErrAttrs(err),
+					slog.String("expr", alert.When.Text),
+				)...,
 			)
 			continue
 		}
@@ -32,12 +39,15 @@ func SoftEvaluateAlerts(ctx context.Context, alerts []conf.Alert, data any) []db
 		}
 		msg, err := alert.Message.Evaluate(ctx, data)
 		if err != nil {
+			telemetry.AlertEvaluationFailed(ctx)
 			slog.LogAttrs(
 				ctx,
 				slog.LevelError,
 				"evaluating message expression",
-				slog.String("error", err.Error()),
-				slog.String("message", alert.Message.Text),
+				append(
+					exprErrAttrs(err),
+					slog.String("message", alert.Message.Text),
+				)...,
 			)
 			continue
 		}
@@ -49,3 +59,22 @@ func SoftEvaluateAlerts(ctx context.Context, alerts []conf.Alert, data any) []db
 
 	return firing
 }
+
+// exprErrAttrs returns log attributes describing err. If err was returned by
+// an expression, the location of the failure and the field path evaluated
+// until then are included.
+func exprErrAttrs(err error) []slog.Attr {
+	attrs := []slog.Attr{slog.String("error", err.Error())}
+	var exprErr *expr.Error
+	if errors.As(err, &exprErr) {
+		line, col := exprErr.Position()
+		attrs = append(attrs,
+			slog.String("at", fmt.Sprintf("%d:%d", line, col)),
+			slog.String("failed", exprErr.Text()),
+		)
+		if exprErr.Path != "" {
+			attrs = append(attrs, slog.String("path", exprErr.Path))
+		}
+	}
+	return attrs
+}
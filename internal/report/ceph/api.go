@@ -7,6 +7,8 @@ import (
 	"fmt"
 	"net/http"
 	"net/url"
+
+	"github.com/accuknox/rinc/internal/telemetry"
 )
 
 const (
@@ -74,11 +76,11 @@ func (r Reporter) call(ctx context.Context, endp, mediaTyp string, v any, q ...u
 	req.Header.Set("accept", mediaTyp)
 
 	client := http.Client{
-		Transport: &http.Transport{
+		Transport: telemetry.Transport(telemetry.APICeph, r.network.Transport(&http.Transport{
 			TLSClientConfig: &tls.Config{
 				InsecureSkipVerify: true,
 			},
-		},
+		})),
 	}
 	resp, err := client.Do(req)
 	if err != nil {
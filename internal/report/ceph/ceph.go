@@ -10,6 +10,7 @@ import (
 	"github.com/accuknox/rinc/internal/conf"
 	"github.com/accuknox/rinc/internal/db"
 	"github.com/accuknox/rinc/internal/report"
+	"github.com/accuknox/rinc/internal/snapshot"
 	types "github.com/accuknox/rinc/types/ceph"
 
 	"go.mongodb.org/mongo-driver/v2/bson"
@@ -19,17 +20,20 @@ import (
 
 // Reporter is the ceph status reporter.
 type Reporter struct {
-	kubeClient *kubernetes.Clientset
+	kubeClient kubernetes.Interface
+	network    snapshot.Network
 	conf       conf.Ceph
 	mongo      *mongo.Client
 	token      *token
 }
 
-// NewReporter creates a new ceph status reporter.
-func NewReporter(c conf.Ceph, k *kubernetes.Clientset, mongo *mongo.Client) Reporter {
+// NewReporter creates a new ceph status reporter. The dashboard API is
+// reached through the network n.
+func NewReporter(c conf.Ceph, k kubernetes.Interface, n snapshot.Network, mongo *mongo.Client) Reporter {
 	return Reporter{
 		conf:       c,
 		kubeClient: k,
+		network:    n,
 		mongo:      mongo,
 		token:      nil,
 	}
@@ -38,6 +42,64 @@ func NewReporter(c conf.Ceph, k *kubernetes.Clientset, mongo *mongo.Client) Repo
 // Report satisfies the report.Reporter interface by writing the CEPH status
 // and fetched metrics to the provided io.Writer.
 func (r Reporter) Report(ctx context.Context, now time.Time) error {
+	metrics, err := r.Collect(ctx, now)
+	if err != nil {
+		return err
+	}
+
+	result, err := db.
+		Database(r.mongo).
+		Collection(db.CollectionCeph).
+		InsertOne(ctx, metrics)
+	if err != nil {
+		slog.LogAttrs(
+			ctx,
+			slog.LevelError,
+			"ceph: inserting metrics into mongodb",
+			slog.Time("timestamp", now),
+			slog.String("error", err.Error()),
+		)
+		return fmt.Errorf("inserting metrics into mongodb: %w", err)
+	}
+	slog.LogAttrs(
+		ctx,
+		slog.LevelDebug,
+		"ceph: inserted metrics into mongodb",
+		slog.Any("insertedId", result.InsertedID),
+	)
+
+	alerts := report.SoftEvaluateAlerts(ctx, r.conf.Alerts, metrics)
+	result, err = db.
+		Database(r.mongo).
+		Collection(db.CollectionAlerts).
+		InsertOne(ctx, bson.M{
+			"timestamp": now,
+			"from":      db.CollectionCeph,
+			"alerts":    alerts,
+		})
+	if err != nil {
+		slog.LogAttrs(
+			ctx,
+			slog.LevelError,
+			"ceph: inserting alerts into mongodb",
+			slog.Time("timestamp", now),
+			slog.String("error", err.Error()),
+		)
+		return fmt.Errorf("inserting alerts into mongodb: %w", err)
+	}
+	slog.LogAttrs(
+		ctx,
+		slog.LevelDebug,
+		"ceph: inserted alerts into mongodb",
+		slog.Any("insertedId", result.InsertedID),
+	)
+
+	return nil
+}
+
+// Collect fetches the CEPH status and metrics from the CEPH dashboard API
+// without writing anything to the database.
+func (r Reporter) Collect(ctx context.Context, now time.Time) (types.Metrics, error) {
 	summary := new(types.Summary)
 	err := r.call(ctx, summaryEndpoint, mediaTypeV10, summary)
 	if err != nil {
@@ -47,7 +109,7 @@ func (r Reporter) Report(ctx context.Context, now time.Time) error {
 			"fetching ceph summary",
 			slog.String("error", err.Error()),
 		)
-		return fmt.Errorf("fetching ceph summary: %w", err)
+		return types.Metrics{}, fmt.Errorf("fetching ceph summary: %w", err)
 	}
 
 	status := new(types.Status)
@@ -59,7 +121,7 @@ func (r Reporter) Report(ctx context.Context, now time.Time) error {
 			"fetching ceph health status",
 			slog.String("error", err.Error()),
 		)
-		return fmt.Errorf("fetching ceph health status: %w", err)
+		return types.Metrics{}, fmt.Errorf("fetching ceph health status: %w", err)
 	}
 
 	var hosts []types.Host
@@ -77,7 +139,7 @@ func (r Reporter) Report(ctx context.Context, now time.Time) error {
 				"fetching ceph hosts",
 				slog.String("error", err.Error()),
 			)
-			return fmt.Errorf("fetching ceph hosts: %w", err)
+			return types.Metrics{}, fmt.Errorf("fetching ceph hosts: %w", err)
 		}
 		if len(h) == 0 {
 			break
@@ -98,7 +160,7 @@ func (r Reporter) Report(ctx context.Context, now time.Time) error {
 				slog.String("error", err.Error()),
 				slog.String("host", h.Hostname),
 			)
-			return fmt.Errorf("fetching ceph host devices: %w", err)
+			return types.Metrics{}, fmt.Errorf("fetching ceph host devices: %w", err)
 		}
 		devices = append(devices, d...)
 	}
@@ -112,7 +174,7 @@ func (r Reporter) Report(ctx context.Context, now time.Time) error {
 			"fetching ceph host inventories",
 			slog.String("error", err.Error()),
 		)
-		return fmt.Errorf("fetching ceph host inventories: %w", err)
+		return types.Metrics{}, fmt.Errorf("fetching ceph host inventories: %w", err)
 	}
 
 	var buckets []types.Bucket
@@ -126,7 +188,7 @@ func (r Reporter) Report(ctx context.Context, now time.Time) error {
 			"fetching ceph RGW buckets",
 			slog.String("error", err.Error()),
 		)
-		return fmt.Errorf("fetching ceph RGW buckets: %w", err)
+		return types.Metrics{}, fmt.Errorf("fetching ceph RGW buckets: %w", err)
 	}
 
 	metrics := types.Metrics{
@@ -139,52 +201,5 @@ func (r Reporter) Report(ctx context.Context, now time.Time) error {
 		Inventories: inventories,
 	}
 
-	result, err := db.
-		Database(r.mongo).
-		Collection(db.CollectionCeph).
-		InsertOne(ctx, metrics)
-	if err != nil {
-		slog.LogAttrs(
-			ctx,
-			slog.LevelError,
-			"ceph: inserting metrics into mongodb",
-			slog.Time("timestamp", now),
-			slog.String("error", err.Error()),
-		)
-		return fmt.Errorf("inserting metrics into mongodb: %w", err)
-	}
-	slog.LogAttrs(
-		ctx,
-		slog.LevelDebug,
-		"ceph: inserted metrics into mongodb",
-		slog.Any("insertedId", result.InsertedID),
-	)
-
-	alerts := report.SoftEvaluateAlerts(ctx, r.conf.Alerts, metrics)
-	result, err = db.
-		Database(r.mongo).
-		Collection(db.CollectionAlerts).
-		InsertOne(ctx, bson.M{
-			"timestamp": now,
-			"from":      db.CollectionCeph,
-			"alerts":    alerts,
-		})
-	if err != nil {
-		slog.LogAttrs(
-			ctx,
-			slog.LevelError,
-			"ceph: inserting alerts into mongodb",
-			slog.Time("timestamp", now),
-			slog.String("error", err.Error()),
-		)
-		return fmt.Errorf("inserting alerts into mongodb: %w", err)
-	}
-	slog.LogAttrs(
-		ctx,
-		slog.LevelDebug,
-		"ceph: inserted alerts into mongodb",
-		slog.Any("insertedId", result.InsertedID),
-	)
-
-	return nil
+	return metrics, nil
 }
@@ -10,6 +10,7 @@ import (
 	"net/url"
 	"time"
 
+	"github.com/accuknox/rinc/internal/telemetry"
 	types "github.com/accuknox/rinc/types/ceph"
 
 	"github.com/golang-jwt/jwt/v5"
@@ -39,11 +40,11 @@ func (r *Reporter) fetchTkn(ctx context.Context) error {
 	req.Header.Set("Content-Type", "application/json")
 
 	client := http.Client{
-		Transport: &http.Transport{
+		Transport: telemetry.Transport(telemetry.APICeph, r.network.Transport(&http.Transport{
 			TLSClientConfig: &tls.Config{
 				InsecureSkipVerify: true,
 			},
-		},
+		})),
 	}
 	resp, err := client.Do(req)
 	if err != nil {
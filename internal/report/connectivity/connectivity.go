@@ -9,6 +9,7 @@ import (
 	"github.com/accuknox/rinc/internal/conf"
 	"github.com/accuknox/rinc/internal/db"
 	"github.com/accuknox/rinc/internal/report"
+	"github.com/accuknox/rinc/internal/snapshot"
 	types "github.com/accuknox/rinc/types/connectivity"
 
 	"go.mongodb.org/mongo-driver/v2/bson"
@@ -18,24 +19,100 @@ import (
 
 // Reporter is the connectivity status reporter.
 type Reporter struct {
-	kubeClient *kubernetes.Clientset
+	kubeClient kubernetes.Interface
 	conf       conf.Connectivity
 	mongo      *mongo.Client
+	network    snapshot.Network
 }
 
-// NewReporter creates a new connectivity status reporter.
-func NewReporter(c conf.Connectivity, k *kubernetes.Clientset, mongo *mongo.Client) Reporter {
+// NewReporter creates a new connectivity status reporter. The results of the
+// checks are recorded and replayed through n.
+func NewReporter(c conf.Connectivity, k kubernetes.Interface, n snapshot.Network, mongo *mongo.Client) Reporter {
 	return Reporter{
 		conf:       c,
 		kubeClient: k,
 		mongo:      mongo,
+		network:    n,
 	}
 }
 
 // Report satisfies the report.Reporter interface by writing the connectivity
 // status to the database.
 func (r Reporter) Report(ctx context.Context, now time.Time) error {
-	metrics := types.Metrics{Timestamp: now}
+	metrics, err := r.Collect(ctx, now)
+	if err != nil {
+		return err
+	}
+
+	result, err := db.
+		Database(r.mongo).
+		Collection(db.CollectionConnectivity).
+		InsertOne(ctx, metrics)
+	if err != nil {
+		slog.LogAttrs(
+			ctx,
+			slog.LevelError,
+			"connectivity: inserting metrics into mongodb",
+			slog.Time("timestamp", now),
+			slog.String("error", err.Error()),
+		)
+		return fmt.Errorf("inserting metrics into mongodb: %w", err)
+	}
+	slog.LogAttrs(
+		ctx,
+		slog.LevelDebug,
+		"connectivity: inserted metrics into mongodb",
+		slog.Any("insertedId", result.InsertedID),
+	)
+
+	alerts := report.SoftEvaluateAlerts(ctx, r.conf.Alerts, metrics)
+	result, err = db.
+		Database(r.mongo).
+		Collection(db.CollectionAlerts).
+		InsertOne(ctx, bson.M{
+			"timestamp": now,
+			"from":      db.CollectionConnectivity,
+			"alerts":    alerts,
+		})
+	if err != nil {
+		slog.LogAttrs(
+			ctx,
+			slog.LevelError,
+			"connectivity: inserting alerts into mongodb",
+			slog.Time("timestamp", now),
+			slog.String("error", err.Error()),
+		)
+		return fmt.Errorf("inserting alerts into mongodb: %w", err)
+	}
+	slog.LogAttrs(
+		ctx,
+		slog.LevelDebug,
+		"connectivity: inserted alerts into mongodb",
+		slog.Any("insertedId", result.InsertedID),
+	)
+
+	return nil
+}
+
+// Collect checks the connectivity to the configured services without writing
+// anything to the database.
+func (r Reporter) Collect(ctx context.Context, now time.Time) (types.Metrics, error) {
+	var metrics types.Metrics
+	err := r.network.Check(db.CollectionConnectivity, &metrics, func() error {
+		metrics = r.check(ctx)
+		return nil
+	})
+	if err != nil {
+		return metrics, fmt.Errorf("checking connectivity: %w", err)
+	}
+	metrics.Timestamp = now
+	return metrics, nil
+}
+
+// check checks the connectivity to the configured services. Failed checks are
+// logged, and reported as unreachable services.
+func (r Reporter) check(ctx context.Context) types.Metrics {
+	var metrics types.Metrics
 
 	if r.conf.Vault.Enable {
 		vault, err := r.vaultReport(ctx)
@@ -127,52 +204,5 @@ func (r Reporter) Report(ctx context.Context, now time.Time) error {
 		}
 	}
 
-	result, err := db.
-		Database(r.mongo).
-		Collection(db.CollectionConnectivity).
-		InsertOne(ctx, metrics)
-	if err != nil {
-		slog.LogAttrs(
-			ctx,
-			slog.LevelError,
-			"connectivity: inserting metrics into mongodb",
-			slog.Time("timestamp", now),
-			slog.String("error", err.Error()),
-		)
-		return fmt.Errorf("inserting metrics into mongodb: %w", err)
-	}
-	slog.LogAttrs(
-		ctx,
-		slog.LevelDebug,
-		"connectivity: inserted metrics into mongodb",
-		slog.Any("insertedId", result.InsertedID),
-	)
-
-	alerts := report.SoftEvaluateAlerts(ctx, r.conf.Alerts, metrics)
-	result, err = db.
-		Database(r.mongo).
-		Collection(db.CollectionAlerts).
-		InsertOne(ctx, bson.M{
-			"timestamp": now,
-			"from":      db.CollectionConnectivity,
-			"alerts":    alerts,
-		})
-	if err != nil {
-		slog.LogAttrs(
-			ctx,
-			slog.LevelError,
-			"connectivity: inserting alerts into mongodb",
-			slog.Time("timestamp", now),
-			slog.String("error", err.Error()),
-		)
-		return fmt.Errorf("inserting alerts into mongodb: %w", err)
-	}
-	slog.LogAttrs(
-		ctx,
-		slog.LevelDebug,
-		"connectivity: inserted alerts into mongodb",
-		slog.Any("insertedId", result.InsertedID),
-	)
-
-	return nil
+	return metrics
 }
@@ -7,6 +7,7 @@ import (
 	"net/url"
 	"time"
 
+	"github.com/accuknox/rinc/internal/telemetry"
 	types "github.com/accuknox/rinc/types/connectivity"
 )
 
@@ -27,7 +28,10 @@ func (r Reporter) metabaseReport(ctx context.Context) (*types.Metabase, error) {
 		return nil, fmt.Errorf("creating new http request: %w", err)
 	}
 
-	resp, err := http.DefaultClient.Do(req)
+	client := http.Client{
+		Transport: telemetry.Transport(telemetry.APIMetabase, http.DefaultTransport),
+	}
+	resp, err := client.Do(req)
 	if err != nil {
 		return nil, fmt.Errorf("http request failed: %w", err)
 	}
@@ -8,61 +8,53 @@ import (
 
 	"github.com/accuknox/rinc/internal/conf"
 	"github.com/accuknox/rinc/internal/db"
+	"github.com/accuknox/rinc/internal/kube"
 	"github.com/accuknox/rinc/internal/report"
 	types "github.com/accuknox/rinc/types/dass"
 
 	"go.mongodb.org/mongo-driver/v2/bson"
 	"go.mongodb.org/mongo-driver/v2/mongo"
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/client-go/kubernetes"
 )
 
 // Reporter is the deployment and statefulset status (DaSS) reporter.
 type Reporter struct {
-	kubeClient *kubernetes.Clientset
+	kubeClient kubernetes.Interface
+	cache      *kube.Cache
 	conf       conf.DaSS
 	mongo      *mongo.Client
+	// namespaces are the namespaces selected by the configuration, resolved
+	// on every Collect if they are nil.
+	namespaces *kube.Namespaces
 }
 
 // NewReporter creates a new deployment and statefulset status (DaSS) reporter.
-func NewReporter(c conf.DaSS, k *kubernetes.Clientset, mongo *mongo.Client) Reporter {
+// The cache must include deployments, statefulsets and events.
+func NewReporter(c conf.DaSS, k kubernetes.Interface, cache *kube.Cache, mongo *mongo.Client) Reporter {
 	return Reporter{
 		conf:       c,
 		kubeClient: k,
+		cache:      cache,
 		mongo:      mongo,
 	}
 }
 
+// WithNamespaces returns a copy of r limited to ns, the namespaces already
+// resolved from the configuration, e.g., by the job, instead of resolving them
+// again.
+func (r Reporter) WithNamespaces(ns kube.Namespaces) Reporter {
+	r.namespaces = &ns
+	return r
+}
+
 // Report satisfies the report.Reporter interface by fetching the status of
-// deployments and statefulsets from the Kubernetes API server, and writes the
-// report to the database.
+// deployments and statefulsets from the informer cache, and writes the report
+// to the database.
 func (r Reporter) Report(ctx context.Context, now time.Time) error {
-	depls, err := r.deployments(ctx)
+	metrics, err := r.Collect(ctx, now)
 	if err != nil {
-		slog.LogAttrs(
-			ctx,
-			slog.LevelError,
-			"fetching deployment resources",
-			slog.String("error", err.Error()),
-		)
-		return fmt.Errorf("fetching deployments: %w", err)
-	}
-
-	ss, err := r.statefulset(ctx)
-	if err != nil {
-		slog.LogAttrs(
-			ctx,
-			slog.LevelError,
-			"fetching statefulset resources",
-			slog.String("error", err.Error()),
-		)
-		return fmt.Errorf("fetching statefulsets: %w", err)
-	}
-
-	metrics := types.Metrics{
-		Timestamp:    now,
-		Deployments:  depls,
-		Statefulsets: ss,
+		return err
 	}
 
 	result, err := db.Database(r.mongo).
@@ -114,171 +106,185 @@ func (r Reporter) Report(ctx context.Context, now time.Time) error {
 	return nil
 }
 
-func (r Reporter) deployments(ctx context.Context) ([]types.Resource, error) {
+// Collect fetches the status of deployments and statefulsets from the informer
+// cache without writing anything to the database.
+func (r Reporter) Collect(ctx context.Context, now time.Time) (types.Metrics, error) {
+	ns, err := r.resolveNamespaces(ctx)
+	if err != nil {
+		slog.LogAttrs(
+			ctx,
+			slog.LevelError,
+			"resolving namespaces",
+			slog.String("error", err.Error()),
+		)
+		return types.Metrics{}, fmt.Errorf("resolving namespaces: %w", err)
+	}
+
+	selector, err := labels.Parse(r.conf.LabelSelector)
+	if err != nil {
+		return types.Metrics{}, fmt.Errorf("parsing label selector: %w", err)
+	}
+
+	depls, err := r.deployments(ctx, now, ns, selector)
+	if err != nil {
+		slog.LogAttrs(
+			ctx,
+			slog.LevelError,
+			"fetching deployment resources",
+			slog.String("error", err.Error()),
+		)
+		return types.Metrics{}, fmt.Errorf("fetching deployments: %w", err)
+	}
+
+	ss, err := r.statefulset(ctx, now, ns, selector)
+	if err != nil {
+		slog.LogAttrs(
+			ctx,
+			slog.LevelError,
+			"fetching statefulset resources",
+			slog.String("error", err.Error()),
+		)
+		return types.Metrics{}, fmt.Errorf("fetching statefulsets: %w", err)
+	}
+
+	metrics := types.Metrics{
+		Timestamp:    now,
+		Deployments:  depls,
+		Statefulsets: ss,
+	}
+
+	return metrics, nil
+}
+
+func (r Reporter) deployments(ctx context.Context, now time.Time, ns kube.Namespaces, selector labels.Selector) ([]types.Resource, error) {
 	var deployments []types.Resource
-	var cntinue string
+	depls, err := r.cache.Deployments(ns, selector)
+	if err != nil {
+		slog.LogAttrs(
+			ctx,
+			slog.LevelError,
+			"listing deployments",
+			slog.String("namespace", ns.String()),
+			slog.String("error", err.Error()),
+		)
+		return nil, fmt.Errorf("listing deployments in ns %q: %w",
+			ns, err)
+	}
 
-	for {
-		depls, err := r.kubeClient.
-			AppsV1().
-			Deployments(r.conf.Namespace).
-			List(ctx, metav1.ListOptions{
-				Continue: cntinue,
-				Limit:    30,
-			})
+	for _, d := range depls {
+		events, err := r.events(d.Namespace, d.Name)
 		if err != nil {
 			slog.LogAttrs(
 				ctx,
 				slog.LevelError,
-				"listing deployments",
-				slog.String("namespace", r.conf.Namespace),
-				slog.String("error", err.Error()),
-			)
-			return nil, fmt.Errorf("listing deployments in ns %q: %w",
-				r.conf.Namespace, err)
-		}
-
-		for _, d := range depls.Items {
-			events, err := r.events(ctx, d.Name, d.Kind)
-			if err != nil {
-				slog.LogAttrs(
-					ctx,
-					slog.LevelError,
-					"fetching events",
-					slog.String("kind", d.Kind),
-					slog.String("for", d.Name),
-					slog.String("namespace", d.Namespace),
-					slog.String("error", err.Error()),
-				)
-				return nil, fmt.Errorf("fetching events for %q: %w", d.Name, err)
-			}
-			var desiredReplicas int32
-			if d.Spec.Replicas != nil {
-				desiredReplicas = *d.Spec.Replicas
-			}
-			deployments = append(deployments, types.Resource{
-				Name:              d.Name,
-				Namespace:         d.Namespace,
-				Age:               time.Since(d.CreationTimestamp.Time),
-				DesiredReplicas:   desiredReplicas,
-				ReadyReplicas:     d.Status.ReadyReplicas,
-				AvailableReplicas: d.Status.AvailableReplicas,
-				UpdatedReplicas:   d.Status.UpdatedReplicas,
-				Events:            events,
-				IsReplicaFailure:  deploymentHasReplicaFailure(d.Status.Conditions),
-				IsAvailable:       isDeploymentAvailable(d.Status.Conditions),
-			})
-			slog.LogAttrs(
-				ctx,
-				slog.LevelDebug,
-				"collected deployment",
-				slog.String("name", d.Name),
-				slog.String("namespace", d.Namespace),
+				"fetching events",
 				slog.String("kind", d.Kind),
-				slog.Int("desiredReplicas", int(desiredReplicas)),
-				slog.Int("readyReplicas", int(d.Status.ReadyReplicas)),
-				slog.Int("availableReplicas", int(d.Status.AvailableReplicas)),
-				slog.Int("updatedReplicas", int(d.Status.UpdatedReplicas)),
+				slog.String("for", d.Name),
+				slog.String("namespace", d.Namespace),
+				slog.String("error", err.Error()),
 			)
+			return nil, fmt.Errorf("fetching events for %q: %w", d.Name, err)
 		}
-
-		cntinue = depls.Continue
-		if cntinue == "" {
-			break
+		var desiredReplicas int32
+		if d.Spec.Replicas != nil {
+			desiredReplicas = *d.Spec.Replicas
 		}
+		deployments = append(deployments, types.Resource{
+			Name:              d.Name,
+			Namespace:         d.Namespace,
+			Age:               now.Sub(d.CreationTimestamp.Time),
+			DesiredReplicas:   desiredReplicas,
+			ReadyReplicas:     d.Status.ReadyReplicas,
+			AvailableReplicas: d.Status.AvailableReplicas,
+			UpdatedReplicas:   d.Status.UpdatedReplicas,
+			Events:            events,
+			IsReplicaFailure:  deploymentHasReplicaFailure(d.Status.Conditions),
+			IsAvailable:       isDeploymentAvailable(d.Status.Conditions),
+		})
+		slog.LogAttrs(
+			ctx,
+			slog.LevelDebug,
+			"collected deployment",
+			slog.String("name", d.Name),
+			slog.String("namespace", d.Namespace),
+			slog.String("kind", d.Kind),
+			slog.Int("desiredReplicas", int(desiredReplicas)),
+			slog.Int("readyReplicas", int(d.Status.ReadyReplicas)),
+			slog.Int("availableReplicas", int(d.Status.AvailableReplicas)),
+			slog.Int("updatedReplicas", int(d.Status.UpdatedReplicas)),
+		)
 	}
 
 	return deployments, nil
 }
 
-func (r Reporter) statefulset(ctx context.Context) ([]types.Resource, error) {
+func (r Reporter) statefulset(ctx context.Context, now time.Time, ns kube.Namespaces, selector labels.Selector) ([]types.Resource, error) {
 	var statefulsets []types.Resource
-	var cntinue string
+	ss, err := r.cache.StatefulSets(ns, selector)
+	if err != nil {
+		slog.LogAttrs(
+			ctx,
+			slog.LevelError,
+			"listing statefulsets",
+			slog.String("namespace", ns.String()),
+			slog.String("error", err.Error()),
+		)
+		return nil, fmt.Errorf("listing statefulsets in ns %q: %w",
+			ns, err)
+	}
 
-	for {
-		ss, err := r.kubeClient.
-			AppsV1().
-			StatefulSets(r.conf.Namespace).
-			List(ctx, metav1.ListOptions{
-				Continue: cntinue,
-				Limit:    30,
-			})
+	for _, s := range ss {
+		events, err := r.events(s.Namespace, s.Name)
 		if err != nil {
 			slog.LogAttrs(
 				ctx,
 				slog.LevelError,
-				"listing statefulsets",
-				slog.String("namespace", r.conf.Namespace),
-				slog.String("error", err.Error()),
-			)
-			return nil, fmt.Errorf("listing statefulsets in ns %q: %w",
-				r.conf.Namespace, err)
-		}
-
-		for _, s := range ss.Items {
-			events, err := r.events(ctx, s.Name, s.Kind)
-			if err != nil {
-				slog.LogAttrs(
-					ctx,
-					slog.LevelError,
-					"fetching events",
-					slog.String("kind", s.Kind),
-					slog.String("for", s.Name),
-					slog.String("namespace", s.Namespace),
-					slog.String("error", err.Error()),
-				)
-				return nil, fmt.Errorf("fetching events for %q: %w", s.Name, err)
-			}
-			var desiredReplicas int32
-			if s.Spec.Replicas != nil {
-				desiredReplicas = *s.Spec.Replicas
-			}
-			statefulsets = append(statefulsets, types.Resource{
-				Name:              s.Name,
-				Namespace:         s.Namespace,
-				Age:               time.Since(s.CreationTimestamp.Time),
-				DesiredReplicas:   desiredReplicas,
-				ReadyReplicas:     s.Status.ReadyReplicas,
-				AvailableReplicas: s.Status.AvailableReplicas,
-				UpdatedReplicas:   s.Status.UpdatedReplicas,
-				Events:            events,
-			})
-			slog.LogAttrs(
-				ctx,
-				slog.LevelDebug,
-				"collected statefulset",
-				slog.String("name", s.Name),
-				slog.String("namespace", s.Namespace),
+				"fetching events",
 				slog.String("kind", s.Kind),
-				slog.Int("desiredReplicas", int(desiredReplicas)),
-				slog.Int("readyReplicas", int(s.Status.ReadyReplicas)),
-				slog.Int("availableReplicas", int(s.Status.AvailableReplicas)),
-				slog.Int("updatedReplicas", int(s.Status.UpdatedReplicas)),
+				slog.String("for", s.Name),
+				slog.String("namespace", s.Namespace),
+				slog.String("error", err.Error()),
 			)
+			return nil, fmt.Errorf("fetching events for %q: %w", s.Name, err)
 		}
-
-		cntinue = ss.Continue
-		if cntinue == "" {
-			break
+		var desiredReplicas int32
+		if s.Spec.Replicas != nil {
+			desiredReplicas = *s.Spec.Replicas
 		}
+		statefulsets = append(statefulsets, types.Resource{
+			Name:              s.Name,
+			Namespace:         s.Namespace,
+			Age:               now.Sub(s.CreationTimestamp.Time),
+			DesiredReplicas:   desiredReplicas,
+			ReadyReplicas:     s.Status.ReadyReplicas,
+			AvailableReplicas: s.Status.AvailableReplicas,
+			UpdatedReplicas:   s.Status.UpdatedReplicas,
+			Events:            events,
+		})
+		slog.LogAttrs(
+			ctx,
+			slog.LevelDebug,
+			"collected statefulset",
+			slog.String("name", s.Name),
+			slog.String("namespace", s.Namespace),
+			slog.String("kind", s.Kind),
+			slog.Int("desiredReplicas", int(desiredReplicas)),
+			slog.Int("readyReplicas", int(s.Status.ReadyReplicas)),
+			slog.Int("availableReplicas", int(s.Status.AvailableReplicas)),
+			slog.Int("updatedReplicas", int(s.Status.UpdatedReplicas)),
+		)
 	}
 
 	return statefulsets, nil
 }
 
-func (r Reporter) events(ctx context.Context, name, kind string) ([]types.Event, error) {
+func (r Reporter) events(namespace, name string) ([]types.Event, error) {
 	var events []types.Event
-	evList, err := r.kubeClient.
-		CoreV1().
-		Events(r.conf.Namespace).
-		List(ctx, metav1.ListOptions{
-			FieldSelector: fmt.Sprintf("involvedObject.name=%s", name),
-			TypeMeta:      metav1.TypeMeta{Kind: kind},
-		})
+	evList, err := r.cache.EventsFor(namespace, name)
 	if err != nil {
 		return nil, err
 	}
-	for _, ev := range evList.Items {
+	for _, ev := range evList {
 		events = append(events, types.Event{
 			Type:    ev.Type,
 			Reason:  ev.Reason,
@@ -287,3 +293,12 @@ func (r Reporter) events(ctx context.Context, name, kind string) ([]types.Event,
 	}
 	return events, nil
 }
+
+// resolveNamespaces returns the namespaces passed with WithNamespaces, or
+// resolves the ones selected by the configuration.
+func (r Reporter) resolveNamespaces(ctx context.Context) (kube.Namespaces, error) {
+	if r.namespaces != nil {
+		return *r.namespaces, nil
+	}
+	return kube.ResolveNamespaces(ctx, r.kubeClient, r.conf.Namespaces)
+}
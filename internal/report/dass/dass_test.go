@@ -0,0 +1,214 @@
+package dass
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/accuknox/rinc/internal/conf"
+	"github.com/accuknox/rinc/internal/kube"
+	"github.com/accuknox/rinc/internal/kube/kubetest"
+	types "github.com/accuknox/rinc/types/dass"
+
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/utils/ptr"
+)
+
+func TestCollect(t *testing.T) {
+	now := time.Date(2024, 10, 18, 12, 0, 0, 0, time.UTC)
+	created := metav1.NewTime(now.Add(-time.Hour))
+	meta := func(ns, name string, lbls map[string]string) metav1.ObjectMeta {
+		return metav1.ObjectMeta{
+			Namespace:         ns,
+			Name:              name,
+			Labels:            lbls,
+			CreationTimestamp: created,
+		}
+	}
+	client := fake.NewSimpleClientset(
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "accuknox"}},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "monitoring"}},
+		&appsv1.Deployment{
+			ObjectMeta: meta("accuknox", "api", map[string]string{"tier": "backend"}),
+			Spec:       appsv1.DeploymentSpec{Replicas: ptr.To[int32](3)},
+			Status: appsv1.DeploymentStatus{
+				ReadyReplicas:     2,
+				AvailableReplicas: 2,
+				UpdatedReplicas:   3,
+				Conditions: []appsv1.DeploymentCondition{
+					{Type: appsv1.DeploymentAvailable, Status: corev1.ConditionTrue},
+					{Type: appsv1.DeploymentReplicaFailure, Status: corev1.ConditionTrue},
+				},
+			},
+		},
+		&appsv1.Deployment{
+			ObjectMeta: meta("accuknox", "ui", map[string]string{"tier": "frontend"}),
+		},
+		&appsv1.Deployment{
+			ObjectMeta: meta("monitoring", "grafana", map[string]string{"tier": "backend"}),
+		},
+		&appsv1.StatefulSet{
+			ObjectMeta: meta("accuknox", "mongodb", map[string]string{"tier": "backend"}),
+			Spec:       appsv1.StatefulSetSpec{Replicas: ptr.To[int32](1)},
+			Status: appsv1.StatefulSetStatus{
+				ReadyReplicas:     1,
+				AvailableReplicas: 1,
+				UpdatedReplicas:   1,
+			},
+		},
+		&appsv1.StatefulSet{
+			ObjectMeta: meta("accuknox", "kafka", map[string]string{"tier": "queue"}),
+		},
+		&corev1.Event{
+			ObjectMeta:     meta("accuknox", "api.1", nil),
+			InvolvedObject: corev1.ObjectReference{Namespace: "accuknox", Name: "api"},
+			Type:           corev1.EventTypeWarning,
+			Reason:         "FailedCreate",
+			Message:        "exceeded quota",
+		},
+		&corev1.Event{
+			ObjectMeta:     meta("accuknox", "ui.1", nil),
+			InvolvedObject: corev1.ObjectReference{Namespace: "accuknox", Name: "ui"},
+			Type:           corev1.EventTypeNormal,
+			Reason:         "ScalingReplicaSet",
+		},
+	)
+	cache := kubetest.NewCache(t, client,
+		kube.ResourceDeployments, kube.ResourceStatefulSets, kube.ResourceEvents)
+
+	tests := []struct {
+		name string
+		conf conf.DaSS
+		want types.Metrics
+	}{
+		{
+			name: "selected namespaces and labels",
+			conf: conf.DaSS{
+				Namespaces:    conf.NamespaceSelector{Include: []string{"accuknox"}},
+				LabelSelector: "tier=backend",
+			},
+			want: types.Metrics{
+				Deployments: []types.Resource{{
+					Name:              "api",
+					Namespace:         "accuknox",
+					DesiredReplicas:   3,
+					ReadyReplicas:     2,
+					AvailableReplicas: 2,
+					UpdatedReplicas:   3,
+					Events: []types.Event{{
+						Type:    corev1.EventTypeWarning,
+						Reason:  "FailedCreate",
+						Message: "exceeded quota",
+					}},
+					IsReplicaFailure: true,
+					IsAvailable:      true,
+				}},
+				Statefulsets: []types.Resource{{
+					Name:              "mongodb",
+					Namespace:         "accuknox",
+					DesiredReplicas:   1,
+					ReadyReplicas:     1,
+					AvailableReplicas: 1,
+					UpdatedReplicas:   1,
+				}},
+			},
+		},
+		{
+			name: "all namespaces in order",
+			want: types.Metrics{
+				Deployments: []types.Resource{
+					{
+						Name:              "api",
+						Namespace:         "accuknox",
+						DesiredReplicas:   3,
+						ReadyReplicas:     2,
+						AvailableReplicas: 2,
+						UpdatedReplicas:   3,
+						Events: []types.Event{{
+							Type:    corev1.EventTypeWarning,
+							Reason:  "FailedCreate",
+							Message: "exceeded quota",
+						}},
+						IsReplicaFailure: true,
+						IsAvailable:      true,
+					},
+					{
+						Name:      "ui",
+						Namespace: "accuknox",
+						Events: []types.Event{{
+							Type:   corev1.EventTypeNormal,
+							Reason: "ScalingReplicaSet",
+						}},
+					},
+					{Name: "grafana", Namespace: "monitoring"},
+				},
+				Statefulsets: []types.Resource{
+					{Name: "kafka", Namespace: "accuknox"},
+					{
+						Name:              "mongodb",
+						Namespace:         "accuknox",
+						DesiredReplicas:   1,
+						ReadyReplicas:     1,
+						AvailableReplicas: 1,
+						UpdatedReplicas:   1,
+					},
+				},
+			},
+		},
+		{
+			name: "excluded namespace",
+			conf: conf.DaSS{
+				Namespaces:    conf.NamespaceSelector{Exclude: []string{"accuknox"}},
+				LabelSelector: "tier=backend",
+			},
+			want: types.Metrics{
+				Deployments: []types.Resource{{
+					Name:      "grafana",
+					Namespace: "monitoring",
+				}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := assert.New(t)
+			r := NewReporter(tt.conf, client, cache, nil)
+			got, err := r.Collect(context.Background(), now)
+			if !a.NoError(err) {
+				return
+			}
+			for _, res := range [][]types.Resource{got.Deployments, got.Statefulsets} {
+				for idx := range res {
+					a.Equal(time.Hour, res[idx].Age)
+					res[idx].Age = 0
+				}
+			}
+			tt.want.Timestamp = now
+			a.Equal(tt.want, got)
+		})
+	}
+
+	// namespaces resolved by the job aren't resolved again
+	a := assert.New(t)
+	c := conf.DaSS{Namespaces: conf.NamespaceSelector{Exclude: []string{"accuknox"}}}
+	ns, err := kube.ResolveNamespaces(context.Background(), client, c.Namespaces)
+	if !a.NoError(err) {
+		return
+	}
+	client.ClearActions()
+	got, err := NewReporter(c, client, cache, nil).WithNamespaces(ns).Collect(context.Background(), now)
+	if !a.NoError(err) {
+		return
+	}
+	for _, action := range client.Actions() {
+		a.NotEqual("namespaces", action.GetResource().Resource)
+	}
+	if a.Len(got.Deployments, 1) {
+		a.Equal("grafana", got.Deployments[0].Name)
+	}
+}
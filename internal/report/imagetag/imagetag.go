@@ -4,65 +4,58 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
+	"slices"
 	"time"
 
 	"github.com/accuknox/rinc/internal/conf"
 	"github.com/accuknox/rinc/internal/db"
+	"github.com/accuknox/rinc/internal/kube"
 	"github.com/accuknox/rinc/internal/report"
 	types "github.com/accuknox/rinc/types/imagetag"
 	"go.mongodb.org/mongo-driver/v2/bson"
 	"go.mongodb.org/mongo-driver/v2/mongo"
 
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/client-go/kubernetes"
 )
 
 // Reporter is the image tag reporter.
 type Reporter struct {
-	kubeClient *kubernetes.Clientset
+	kubeClient kubernetes.Interface
+	cache      *kube.Cache
 	conf       conf.ImageTag
 	mongo      *mongo.Client
+	// namespaces are the namespaces selected by the configuration, resolved
+	// on every Collect if they are nil.
+	namespaces *kube.Namespaces
 }
 
-// NewReporter creates a new image tag reporter.
-func NewReporter(c conf.ImageTag, k *kubernetes.Clientset, mongo *mongo.Client) Reporter {
+// NewReporter creates a new image tag reporter. The cache must include
+// deployments and statefulsets.
+func NewReporter(c conf.ImageTag, k kubernetes.Interface, cache *kube.Cache, mongo *mongo.Client) Reporter {
 	return Reporter{
 		conf:       c,
 		kubeClient: k,
+		cache:      cache,
 		mongo:      mongo,
 	}
 }
 
+// WithNamespaces returns a copy of r limited to ns, the namespaces already
+// resolved from the configuration, e.g., by the job, instead of resolving them
+// again.
+func (r Reporter) WithNamespaces(ns kube.Namespaces) Reporter {
+	r.namespaces = &ns
+	return r
+}
+
 // Report satisfies the report.Reporter interface by fetching the image tags of
-// deployments and statefulsets from the Kubernetes API server, and writes the
-// report to the database.
+// deployments and statefulsets from the informer cache, and writes the report
+// to the database.
 func (r Reporter) Report(ctx context.Context, now time.Time) error {
-	depls, err := r.deployments(ctx)
-	if err != nil {
-		slog.LogAttrs(
-			ctx,
-			slog.LevelError,
-			"fetching deployment resources",
-			slog.String("error", err.Error()),
-		)
-		return fmt.Errorf("fetching deployments: %w", err)
-	}
-
-	statefulsets, err := r.statefulsets(ctx)
+	metrics, err := r.Collect(ctx, now)
 	if err != nil {
-		slog.LogAttrs(
-			ctx,
-			slog.LevelError,
-			"fetching statefulset resources",
-			slog.String("error", err.Error()),
-		)
-		return fmt.Errorf("fetching statefulsets: %w", err)
-	}
-
-	metrics := types.Metrics{
-		Timestamp:    now,
-		Deployments:  depls,
-		Statefulsets: statefulsets,
+		return err
 	}
 
 	result, err := db.Database(r.mongo).
@@ -114,116 +107,137 @@ func (r Reporter) Report(ctx context.Context, now time.Time) error {
 	return nil
 }
 
-func (r Reporter) deployments(ctx context.Context) ([]types.Resource, error) {
+// Collect fetches the image tags of deployments and statefulsets from the
+// informer cache without writing anything to the database.
+func (r Reporter) Collect(ctx context.Context, now time.Time) (types.Metrics, error) {
+	ns, err := r.resolveNamespaces(ctx)
+	if err != nil {
+		slog.LogAttrs(
+			ctx,
+			slog.LevelError,
+			"resolving namespaces",
+			slog.String("error", err.Error()),
+		)
+		return types.Metrics{}, fmt.Errorf("resolving namespaces: %w", err)
+	}
+
+	selector, err := labels.Parse(r.conf.LabelSelector)
+	if err != nil {
+		return types.Metrics{}, fmt.Errorf("parsing label selector: %w", err)
+	}
+
+	depls, err := r.deployments(ctx, ns, selector)
+	if err != nil {
+		slog.LogAttrs(
+			ctx,
+			slog.LevelError,
+			"fetching deployment resources",
+			slog.String("error", err.Error()),
+		)
+		return types.Metrics{}, fmt.Errorf("fetching deployments: %w", err)
+	}
+
+	statefulsets, err := r.statefulsets(ctx, ns, selector)
+	if err != nil {
+		slog.LogAttrs(
+			ctx,
+			slog.LevelError,
+			"fetching statefulset resources",
+			slog.String("error", err.Error()),
+		)
+		return types.Metrics{}, fmt.Errorf("fetching statefulsets: %w", err)
+	}
+
+	metrics := types.Metrics{
+		Timestamp:    now,
+		Deployments:  depls,
+		Statefulsets: statefulsets,
+	}
+
+	return metrics, nil
+}
+
+func (r Reporter) deployments(ctx context.Context, ns kube.Namespaces, selector labels.Selector) ([]types.Resource, error) {
 	var resources []types.Resource
-	var cntinue string
-
-	for {
-		depls, err := r.kubeClient.
-			AppsV1().
-			Deployments(r.conf.Namespace).
-			List(ctx, metav1.ListOptions{
-				Continue: cntinue,
-				Limit:    30,
-			})
-		if err != nil {
-			slog.LogAttrs(
-				ctx,
-				slog.LevelError,
-				"listing deployments",
-				slog.String("namespace", r.conf.Namespace),
-				slog.String("error", err.Error()),
-			)
-			return nil, fmt.Errorf("listing deployments in ns %q: %w",
-				r.conf.Namespace, err)
-		}
+	depls, err := r.cache.Deployments(ns, selector)
+	if err != nil {
+		slog.LogAttrs(
+			ctx,
+			slog.LevelError,
+			"listing deployments",
+			slog.String("namespace", ns.String()),
+			slog.String("error", err.Error()),
+		)
+		return nil, fmt.Errorf("listing deployments in ns %q: %w",
+			ns, err)
+	}
 
-		for _, d := range depls.Items {
-			containers := append(
-				d.Spec.Template.Spec.InitContainers,
-				d.Spec.Template.Spec.Containers...,
-			)
-			images := make([]types.Image, len(containers))
-			for idx, c := range containers {
-				images[idx] = types.Image{
-					Name:              c.Image,
-					FromInitContainer: idx < len(d.Spec.Template.Spec.InitContainers),
-				}
+	for _, d := range depls {
+		// cached objects are shared, don't append to their slices
+		containers := slices.Concat(
+			d.Spec.Template.Spec.InitContainers,
+			d.Spec.Template.Spec.Containers,
+		)
+		images := make([]types.Image, len(containers))
+		for idx, c := range containers {
+			images[idx] = types.Image{
+				Name:              c.Image,
+				FromInitContainer: idx < len(d.Spec.Template.Spec.InitContainers),
 			}
-			resources = append(resources, types.Resource{
-				Name:      d.GetName(),
-				Namespace: d.GetNamespace(),
-				Images:    images,
-			})
-		}
-
-		cntinue = depls.Continue
-		if cntinue == "" {
-			slog.LogAttrs(
-				ctx,
-				slog.LevelInfo,
-				"received image tags from all deployments",
-			)
-			break
 		}
+		resources = append(resources, types.Resource{
+			Name:      d.GetName(),
+			Namespace: d.GetNamespace(),
+			Images:    images,
+		})
 	}
 
 	return resources, nil
 }
 
-func (r Reporter) statefulsets(ctx context.Context) ([]types.Resource, error) {
+func (r Reporter) statefulsets(ctx context.Context, ns kube.Namespaces, selector labels.Selector) ([]types.Resource, error) {
 	var resources []types.Resource
-	var cntinue string
-
-	for {
-		ss, err := r.kubeClient.
-			AppsV1().
-			StatefulSets(r.conf.Namespace).
-			List(ctx, metav1.ListOptions{
-				Continue: cntinue,
-				Limit:    30,
-			})
-		if err != nil {
-			slog.LogAttrs(
-				ctx,
-				slog.LevelError,
-				"listing statefulsets",
-				slog.String("namespace", r.conf.Namespace),
-				slog.String("error", err.Error()),
-			)
-			return nil, fmt.Errorf("listing statefulsets in ns %q: %w",
-				r.conf.Namespace, err)
-		}
+	ss, err := r.cache.StatefulSets(ns, selector)
+	if err != nil {
+		slog.LogAttrs(
+			ctx,
+			slog.LevelError,
+			"listing statefulsets",
+			slog.String("namespace", ns.String()),
+			slog.String("error", err.Error()),
+		)
+		return nil, fmt.Errorf("listing statefulsets in ns %q: %w",
+			ns, err)
+	}
 
-		for _, s := range ss.Items {
-			containers := append(
-				s.Spec.Template.Spec.InitContainers,
-				s.Spec.Template.Spec.Containers...,
-			)
-			images := make([]types.Image, len(containers))
-			for idx, c := range containers {
-				images[idx] = types.Image{
-					Name:              c.Image,
-					FromInitContainer: idx < len(s.Spec.Template.Spec.InitContainers),
-				}
+	for _, s := range ss {
+		// cached objects are shared, don't append to their slices
+		containers := slices.Concat(
+			s.Spec.Template.Spec.InitContainers,
+			s.Spec.Template.Spec.Containers,
+		)
+		images := make([]types.Image, len(containers))
+		for idx, c := range containers {
+			images[idx] = types.Image{
+				Name:              c.Image,
+				FromInitContainer: idx < len(s.Spec.Template.Spec.InitContainers),
 			}
-			resources = append(resources, types.Resource{
-				Name:      s.GetName(),
-				Namespace: s.GetNamespace(),
-				Images:    images,
-			})
-		}
-
-		cntinue = ss.Continue
-		if cntinue == "" {
-			slog.LogAttrs(
-				ctx,
-				slog.LevelInfo,
-				"received image tags from all statefulsets",
-			)
-			break
 		}
+		resources = append(resources, types.Resource{
+			Name:      s.GetName(),
+			Namespace: s.GetNamespace(),
+			Images:    images,
+		})
 	}
 
 	return resources, nil
 }
+
+// resolveNamespaces returns the namespaces passed with WithNamespaces, or
+// resolves the ones selected by the configuration.
+func (r Reporter) resolveNamespaces(ctx context.Context) (kube.Namespaces, error) {
+	if r.namespaces != nil {
+		return *r.namespaces, nil
+	}
+	return kube.ResolveNamespaces(ctx, r.kubeClient, r.conf.Namespaces)
+}
@@ -0,0 +1,79 @@
+package imagetag
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/accuknox/rinc/internal/conf"
+	"github.com/accuknox/rinc/internal/kube"
+	"github.com/accuknox/rinc/internal/kube/kubetest"
+	types "github.com/accuknox/rinc/types/imagetag"
+
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestCollect(t *testing.T) {
+	a := assert.New(t)
+	podSpec := func(init []string, images ...string) corev1.PodTemplateSpec {
+		var spec corev1.PodSpec
+		for _, image := range init {
+			spec.InitContainers = append(spec.InitContainers, corev1.Container{Image: image})
+		}
+		for _, image := range images {
+			spec.Containers = append(spec.Containers, corev1.Container{Image: image})
+		}
+		return corev1.PodTemplateSpec{Spec: spec}
+	}
+	client := fake.NewSimpleClientset(
+		&appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "accuknox", Name: "api"},
+			Spec: appsv1.DeploymentSpec{
+				Template: podSpec([]string{"busybox:1.36"}, "accuknox/api:v1.2.0", "envoy:v1.30"),
+			},
+		},
+		&appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "monitoring", Name: "grafana"},
+			Spec: appsv1.DeploymentSpec{
+				Template: podSpec(nil, "grafana/grafana:11.0.0"),
+			},
+		},
+		&appsv1.StatefulSet{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "accuknox", Name: "mongodb"},
+			Spec: appsv1.StatefulSetSpec{
+				Template: podSpec(nil, "mongo:7.0"),
+			},
+		},
+	)
+	cache := kubetest.NewCache(t, client, kube.ResourceDeployments, kube.ResourceStatefulSets)
+
+	now := time.Now().UTC()
+	r := NewReporter(conf.ImageTag{
+		Namespaces: conf.NamespaceSelector{Include: []string{"accuknox"}},
+	}, client, cache, nil)
+	got, err := r.Collect(context.Background(), now)
+	if !a.NoError(err) {
+		return
+	}
+	a.Equal(types.Metrics{
+		Timestamp: now,
+		Deployments: []types.Resource{{
+			Name:      "api",
+			Namespace: "accuknox",
+			Images: []types.Image{
+				{Name: "busybox:1.36", FromInitContainer: true},
+				{Name: "accuknox/api:v1.2.0"},
+				{Name: "envoy:v1.30"},
+			},
+		}},
+		Statefulsets: []types.Resource{{
+			Name:      "mongodb",
+			Namespace: "accuknox",
+			Images:    []types.Image{{Name: "mongo:7.0"}},
+		}},
+	}, got)
+}
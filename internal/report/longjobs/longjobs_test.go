@@ -0,0 +1,129 @@
+package longjobs
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/accuknox/rinc/internal/conf"
+	"github.com/accuknox/rinc/internal/kube"
+	"github.com/accuknox/rinc/internal/kube/kubetest"
+	types "github.com/accuknox/rinc/types/longjobs"
+
+	"github.com/stretchr/testify/assert"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	apitypes "k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/utils/ptr"
+)
+
+func TestCollect(t *testing.T) {
+	now := time.Date(2024, 10, 18, 12, 0, 0, 0, time.UTC)
+	job := func(name string, age time.Duration, conds ...batchv1.JobConditionType) *batchv1.Job {
+		j := &batchv1.Job{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace:         "accuknox",
+				Name:              name,
+				UID:               apitypes.UID(name + "-uid"),
+				CreationTimestamp: metav1.NewTime(now.Add(-age)),
+			},
+		}
+		for _, c := range conds {
+			j.Status.Conditions = append(j.Status.Conditions, batchv1.JobCondition{
+				Type:   c,
+				Status: corev1.ConditionTrue,
+			})
+		}
+		return j
+	}
+	stuck := job("stuck", 3*time.Hour)
+	stuck.Status.Active = 1
+	stuck.Status.Failed = 2
+	stuck.Status.Ready = ptr.To[int32](0)
+	client := fake.NewSimpleClientset(
+		stuck,
+		job("recent", time.Minute),
+		job("complete", 3*time.Hour, batchv1.JobComplete),
+		job("failed", 3*time.Hour, batchv1.JobFailed),
+		job("suspended", 3*time.Hour, batchv1.JobSuspended),
+		&corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: "accuknox",
+				Name:      "stuck-x7k2p",
+				OwnerReferences: []metav1.OwnerReference{
+					{UID: stuck.UID, Name: stuck.Name},
+				},
+			},
+			Status: corev1.PodStatus{
+				Phase: corev1.PodPending,
+				ContainerStatuses: []corev1.ContainerStatus{{
+					Name:         "backup",
+					RestartCount: 2,
+					State: corev1.ContainerState{
+						Waiting: &corev1.ContainerStateWaiting{Reason: "CrashLoopBackOff"},
+					},
+				}},
+			},
+		},
+	)
+	cache := kubetest.NewCache(t, client, kube.ResourceJobs, kube.ResourcePods)
+
+	stuckJob := types.Job{
+		Name:       "stuck",
+		Namespace:  "accuknox",
+		ActivePods: 1,
+		FailedPods: 2,
+		Pods: []types.Pod{{
+			Name:  "stuck-x7k2p",
+			Phase: "Pending",
+			Containers: []types.Container{{
+				Name:         "backup",
+				RestartCount: 2,
+				State:        "WAITING: Reason=CrashLoopBackOff",
+			}},
+		}},
+	}
+	tests := []struct {
+		name string
+		conf conf.LongJobs
+		want []types.Job
+	}{
+		{
+			name: "running jobs",
+			conf: conf.LongJobs{OlderThan: time.Hour},
+			want: []types.Job{stuckJob},
+		},
+		{
+			name: "include suspended jobs",
+			conf: conf.LongJobs{OlderThan: time.Hour, IncludeSuspended: true},
+			want: []types.Job{
+				stuckJob,
+				{Name: "suspended", Namespace: "accuknox", Suspended: true, Pods: []types.Pod{}},
+			},
+		},
+		{
+			name: "threshold not reached",
+			conf: conf.LongJobs{OlderThan: 4 * time.Hour},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := assert.New(t)
+			r := NewReporter(tt.conf, client, cache, nil)
+			got, err := r.Collect(context.Background(), now)
+			if !a.NoError(err) {
+				return
+			}
+			a.Equal(now, got.Timestamp)
+			a.Equal(tt.conf.OlderThan, got.OlderThan)
+			for idx := range got.Jobs {
+				a.Equal(3*time.Hour, got.Jobs[idx].Age)
+				got.Jobs[idx].Age = 0
+			}
+			a.ElementsMatch(tt.want, got.Jobs)
+		})
+	}
+}
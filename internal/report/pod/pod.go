@@ -8,62 +8,53 @@ import (
 
 	"github.com/accuknox/rinc/internal/conf"
 	"github.com/accuknox/rinc/internal/db"
+	"github.com/accuknox/rinc/internal/kube"
 	"github.com/accuknox/rinc/internal/report"
 	types "github.com/accuknox/rinc/types/pod"
 
 	"go.mongodb.org/mongo-driver/v2/bson"
 	"go.mongodb.org/mongo-driver/v2/mongo"
 	corev1 "k8s.io/api/core/v1"
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/client-go/kubernetes"
 )
 
 // Reporter is the pod status reporter.
 type Reporter struct {
-	kubeClient *kubernetes.Clientset
+	kubeClient kubernetes.Interface
+	cache      *kube.Cache
 	conf       conf.PodStatus
 	mongo      *mongo.Client
+	// namespaces are the namespaces selected by the configuration, resolved
+	// on every Collect if they are nil.
+	namespaces *kube.Namespaces
 }
 
-// NewReporter creates a new pod status reporter.
-func NewReporter(c conf.PodStatus, k *kubernetes.Clientset, mongo *mongo.Client) Reporter {
+// NewReporter creates a new pod status reporter. The cache must include
+// deployments, statefulsets and pods.
+func NewReporter(c conf.PodStatus, k kubernetes.Interface, cache *kube.Cache, mongo *mongo.Client) Reporter {
 	return Reporter{
 		conf:       c,
 		kubeClient: k,
+		cache:      cache,
 		mongo:      mongo,
 	}
 }
 
+// WithNamespaces returns a copy of r limited to ns, the namespaces already
+// resolved from the configuration, e.g., by the job, instead of resolving them
+// again.
+func (r Reporter) WithNamespaces(ns kube.Namespaces) Reporter {
+	r.namespaces = &ns
+	return r
+}
+
 // Report satisfies the report.Reporter interface by fetching the status of
-// pods from the Kubernetes API server, and writes the report to the database.
+// pods from the informer cache, and writes the report to the database.
 func (r Reporter) Report(ctx context.Context, now time.Time) error {
-	depls, err := r.deployments(ctx)
+	metrics, err := r.Collect(ctx, now)
 	if err != nil {
-		slog.LogAttrs(
-			ctx,
-			slog.LevelError,
-			"fetching deployment resources",
-			slog.String("error", err.Error()),
-		)
-		return fmt.Errorf("fetching deployments: %w", err)
-	}
-
-	ss, err := r.statefulsets(ctx)
-	if err != nil {
-		slog.LogAttrs(
-			ctx,
-			slog.LevelError,
-			"fetching statefulset resources",
-			slog.String("error", err.Error()),
-		)
-		return fmt.Errorf("fetching statefulsets: %w", err)
-	}
-
-	metrics := types.Metrics{
-		Timestamp:    now,
-		Deployments:  depls,
-		Statefulsets: ss,
+		return err
 	}
 
 	result, err := db.Database(r.mongo).
@@ -115,197 +106,220 @@ func (r Reporter) Report(ctx context.Context, now time.Time) error {
 	return nil
 }
 
-func (r Reporter) deployments(ctx context.Context) ([]types.Resource, error) {
+// Collect fetches the status of pods from the informer cache without writing
+// anything to the database.
+func (r Reporter) Collect(ctx context.Context, now time.Time) (types.Metrics, error) {
+	ns, err := r.resolveNamespaces(ctx)
+	if err != nil {
+		slog.LogAttrs(
+			ctx,
+			slog.LevelError,
+			"resolving namespaces",
+			slog.String("error", err.Error()),
+		)
+		return types.Metrics{}, fmt.Errorf("resolving namespaces: %w", err)
+	}
+
+	selector, err := labels.Parse(r.conf.LabelSelector)
+	if err != nil {
+		return types.Metrics{}, fmt.Errorf("parsing label selector: %w", err)
+	}
+
+	depls, err := r.deployments(ctx, ns, selector)
+	if err != nil {
+		slog.LogAttrs(
+			ctx,
+			slog.LevelError,
+			"fetching deployment resources",
+			slog.String("error", err.Error()),
+		)
+		return types.Metrics{}, fmt.Errorf("fetching deployments: %w", err)
+	}
+
+	ss, err := r.statefulsets(ctx, ns, selector)
+	if err != nil {
+		slog.LogAttrs(
+			ctx,
+			slog.LevelError,
+			"fetching statefulset resources",
+			slog.String("error", err.Error()),
+		)
+		return types.Metrics{}, fmt.Errorf("fetching statefulsets: %w", err)
+	}
+
+	metrics := types.Metrics{
+		Timestamp:    now,
+		Deployments:  depls,
+		Statefulsets: ss,
+	}
+
+	return metrics, nil
+}
+
+func (r Reporter) deployments(ctx context.Context, ns kube.Namespaces, selector labels.Selector) ([]types.Resource, error) {
 	var deployments []types.Resource
-	var cntinue string
+	depls, err := r.cache.Deployments(ns, selector)
+	if err != nil {
+		slog.LogAttrs(
+			ctx,
+			slog.LevelError,
+			"listing deployments",
+			slog.String("namespace", ns.String()),
+			slog.String("error", err.Error()),
+		)
+		return nil, fmt.Errorf("listing deployments in ns %q: %w",
+			ns, err)
+	}
 
-	for {
-		depls, err := r.kubeClient.
-			AppsV1().
-			Deployments(r.conf.Namespace).
-			List(ctx, metav1.ListOptions{
-				Continue: cntinue,
-				Limit:    15,
-			})
+	for _, d := range depls {
+		podList, err := r.cache.PodsSelectedBy(d.Namespace, d.Spec.Selector)
 		if err != nil {
 			slog.LogAttrs(
 				ctx,
 				slog.LevelError,
-				"listing deployments",
-				slog.String("namespace", r.conf.Namespace),
+				"listing pods",
+				slog.String("kind", d.Kind),
+				slog.String("for", d.Name),
+				slog.String("namespace", d.Namespace),
 				slog.String("error", err.Error()),
 			)
-			return nil, fmt.Errorf("listing deployments in ns %q: %w",
-				r.conf.Namespace, err)
+			continue
 		}
-
-		for _, d := range depls.Items {
-			podList, err := r.pods(ctx, d.Namespace, d.Spec.Selector.MatchLabels)
-			if err != nil {
-				slog.LogAttrs(
-					ctx,
-					slog.LevelError,
-					"listing pods",
-					slog.String("kind", d.Kind),
-					slog.String("for", d.Name),
-					slog.String("namespace", d.Namespace),
-					slog.String("error", err.Error()),
-				)
-				continue
-			}
-			pods := make([]types.Pod, len(podList.Items))
-			for idx, pod := range podList.Items {
-				var containers []types.Container
-				for _, c := range pod.Status.InitContainerStatuses {
-					var lastTermState string
-					if c.LastTerminationState.Terminated != nil {
-						lastTermState = c.LastTerminationState.Terminated.Reason
-					}
-					containers = append(containers, types.Container{
-						Name:                 c.Name,
-						IsInit:               true,
-						Ready:                c.Ready,
-						State:                containerState(c.State),
-						RestartCount:         c.RestartCount,
-						LastTerminationState: lastTermState,
-					})
-				}
-				for _, c := range pod.Status.ContainerStatuses {
-					var lastTermState string
-					if c.LastTerminationState.Terminated != nil {
-						lastTermState = c.LastTerminationState.Terminated.Reason
-					}
-					containers = append(containers, types.Container{
-						Name:                 c.Name,
-						Ready:                c.Ready,
-						State:                containerState(c.State),
-						RestartCount:         c.RestartCount,
-						LastTerminationState: lastTermState,
-					})
+		pods := make([]types.Pod, len(podList))
+		for idx, pod := range podList {
+			var containers []types.Container
+			for _, c := range pod.Status.InitContainerStatuses {
+				var lastTermState string
+				if c.LastTerminationState.Terminated != nil {
+					lastTermState = c.LastTerminationState.Terminated.Reason
 				}
-				pods[idx] = types.Pod{
-					Name:       pod.Name,
-					Status:     podStatus(pod.Status),
-					QOSClass:   string(pod.Status.QOSClass),
-					StartTime:  pod.Status.StartTime.Time,
-					Containers: containers,
+				containers = append(containers, types.Container{
+					Name:                 c.Name,
+					IsInit:               true,
+					Ready:                c.Ready,
+					State:                containerState(c.State),
+					RestartCount:         c.RestartCount,
+					LastTerminationState: lastTermState,
+				})
+			}
+			for _, c := range pod.Status.ContainerStatuses {
+				var lastTermState string
+				if c.LastTerminationState.Terminated != nil {
+					lastTermState = c.LastTerminationState.Terminated.Reason
 				}
+				containers = append(containers, types.Container{
+					Name:                 c.Name,
+					Ready:                c.Ready,
+					State:                containerState(c.State),
+					RestartCount:         c.RestartCount,
+					LastTerminationState: lastTermState,
+				})
+			}
+			var startTime time.Time
+			if pod.Status.StartTime != nil {
+				// unscheduled pods have no start time
+				startTime = pod.Status.StartTime.Time
+			}
+			pods[idx] = types.Pod{
+				Name:       pod.Name,
+				Status:     podStatus(pod.Status),
+				QOSClass:   string(pod.Status.QOSClass),
+				StartTime:  startTime,
+				Containers: containers,
 			}
-			deployments = append(deployments, types.Resource{
-				Name:      d.Name,
-				Namespace: d.Namespace,
-				Pods:      pods,
-			})
-		}
-
-		cntinue = depls.Continue
-		if cntinue == "" {
-			break
 		}
+		deployments = append(deployments, types.Resource{
+			Name:      d.Name,
+			Namespace: d.Namespace,
+			Pods:      pods,
+		})
 	}
 
 	return deployments, nil
 }
 
-func (r Reporter) statefulsets(ctx context.Context) ([]types.Resource, error) {
+func (r Reporter) statefulsets(ctx context.Context, ns kube.Namespaces, selector labels.Selector) ([]types.Resource, error) {
 	var statefulsets []types.Resource
-	var cntinue string
+	ss, err := r.cache.StatefulSets(ns, selector)
+	if err != nil {
+		slog.LogAttrs(
+			ctx,
+			slog.LevelError,
+			"listing statefulsets",
+			slog.String("namespace", ns.String()),
+			slog.String("error", err.Error()),
+		)
+		return nil, fmt.Errorf("listing statefulsets in ns %q: %w",
+			ns, err)
+	}
 
-	for {
-		ss, err := r.kubeClient.
-			AppsV1().
-			StatefulSets(r.conf.Namespace).
-			List(ctx, metav1.ListOptions{
-				Continue: cntinue,
-				Limit:    15,
-			})
+	for _, d := range ss {
+		podList, err := r.cache.PodsSelectedBy(d.Namespace, d.Spec.Selector)
 		if err != nil {
 			slog.LogAttrs(
 				ctx,
 				slog.LevelError,
-				"listing statefulsets",
-				slog.String("namespace", r.conf.Namespace),
+				"listing pods",
+				slog.String("kind", d.Kind),
+				slog.String("for", d.Name),
+				slog.String("namespace", d.Namespace),
 				slog.String("error", err.Error()),
 			)
-			return nil, fmt.Errorf("listing statefulsets in ns %q: %w",
-				r.conf.Namespace, err)
+			continue
 		}
-
-		for _, d := range ss.Items {
-			podList, err := r.pods(ctx, d.Namespace, d.Spec.Selector.MatchLabels)
-			if err != nil {
-				slog.LogAttrs(
-					ctx,
-					slog.LevelError,
-					"listing pods",
-					slog.String("kind", d.Kind),
-					slog.String("for", d.Name),
-					slog.String("namespace", d.Namespace),
-					slog.String("error", err.Error()),
-				)
-				continue
-			}
-			pods := make([]types.Pod, len(podList.Items))
-			for idx, pod := range podList.Items {
-				var containers []types.Container
-				for _, c := range pod.Status.InitContainerStatuses {
-					var lastTermState string
-					if c.LastTerminationState.Terminated != nil {
-						lastTermState = c.LastTerminationState.Terminated.Reason
-					}
-					containers = append(containers, types.Container{
-						Name:                 c.Name,
-						IsInit:               true,
-						Ready:                c.Ready,
-						State:                containerState(c.State),
-						RestartCount:         c.RestartCount,
-						LastTerminationState: lastTermState,
-					})
+		pods := make([]types.Pod, len(podList))
+		for idx, pod := range podList {
+			var containers []types.Container
+			for _, c := range pod.Status.InitContainerStatuses {
+				var lastTermState string
+				if c.LastTerminationState.Terminated != nil {
+					lastTermState = c.LastTerminationState.Terminated.Reason
 				}
-				for _, c := range pod.Status.ContainerStatuses {
-					var lastTermState string
-					if c.LastTerminationState.Terminated != nil {
-						lastTermState = c.LastTerminationState.Terminated.Reason
-					}
-					containers = append(containers, types.Container{
-						Name:                 c.Name,
-						Ready:                c.Ready,
-						State:                containerState(c.State),
-						RestartCount:         c.RestartCount,
-						LastTerminationState: lastTermState,
-					})
-				}
-				pods[idx] = types.Pod{
-					Name:       pod.Name,
-					Status:     podStatus(pod.Status),
-					QOSClass:   string(pod.Status.QOSClass),
-					StartTime:  pod.Status.StartTime.Time,
-					Containers: containers,
+				containers = append(containers, types.Container{
+					Name:                 c.Name,
+					IsInit:               true,
+					Ready:                c.Ready,
+					State:                containerState(c.State),
+					RestartCount:         c.RestartCount,
+					LastTerminationState: lastTermState,
+				})
+			}
+			for _, c := range pod.Status.ContainerStatuses {
+				var lastTermState string
+				if c.LastTerminationState.Terminated != nil {
+					lastTermState = c.LastTerminationState.Terminated.Reason
 				}
+				containers = append(containers, types.Container{
+					Name:                 c.Name,
+					Ready:                c.Ready,
+					State:                containerState(c.State),
+					RestartCount:         c.RestartCount,
+					LastTerminationState: lastTermState,
+				})
+			}
+			var startTime time.Time
+			if pod.Status.StartTime != nil {
+				// unscheduled pods have no start time
+				startTime = pod.Status.StartTime.Time
+			}
+			pods[idx] = types.Pod{
+				Name:       pod.Name,
+				Status:     podStatus(pod.Status),
+				QOSClass:   string(pod.Status.QOSClass),
+				StartTime:  startTime,
+				Containers: containers,
 			}
-			statefulsets = append(statefulsets, types.Resource{
-				Name:      d.Name,
-				Namespace: d.Namespace,
-				Pods:      pods,
-			})
-		}
-
-		cntinue = ss.Continue
-		if cntinue == "" {
-			break
 		}
+		statefulsets = append(statefulsets, types.Resource{
+			Name:      d.Name,
+			Namespace: d.Namespace,
+			Pods:      pods,
+		})
 	}
 
 	return statefulsets, nil
 }
 
-func (r Reporter) pods(ctx context.Context, ns string, labels labels.Set) (*corev1.PodList, error) {
-	selector := metav1.FormatLabelSelector(metav1.SetAsLabelSelector(labels))
-	return r.kubeClient.CoreV1().Pods(ns).List(ctx, metav1.ListOptions{
-		LabelSelector: selector,
-	})
-}
-
 func containerState(s corev1.ContainerState) string {
 	if s.Running != nil {
 		return "RUNNING"
@@ -373,3 +387,12 @@ func podStatus(s corev1.PodStatus) string {
 	}
 	return "Unknown" // fallback status if none of the above applies
 }
+
+// resolveNamespaces returns the namespaces passed with WithNamespaces, or
+// resolves the ones selected by the configuration.
+func (r Reporter) resolveNamespaces(ctx context.Context) (kube.Namespaces, error) {
+	if r.namespaces != nil {
+		return *r.namespaces, nil
+	}
+	return kube.ResolveNamespaces(ctx, r.kubeClient, r.conf.Namespaces)
+}
@@ -0,0 +1,197 @@
+package pod
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/accuknox/rinc/internal/conf"
+	"github.com/accuknox/rinc/internal/kube"
+	"github.com/accuknox/rinc/internal/kube/kubetest"
+	types "github.com/accuknox/rinc/types/pod"
+
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestCollect(t *testing.T) {
+	a := assert.New(t)
+	started := time.Date(2024, 10, 1, 12, 0, 0, 0, time.UTC)
+	selector := func(app string) *metav1.LabelSelector {
+		return &metav1.LabelSelector{MatchLabels: map[string]string{"app": app}}
+	}
+	pod := func(ns, name, app string, status corev1.PodStatus) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: ns,
+				Name:      name,
+				Labels:    map[string]string{"app": app},
+			},
+			Status: status,
+		}
+	}
+	client := fake.NewSimpleClientset(
+		&appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "accuknox", Name: "api"},
+			Spec:       appsv1.DeploymentSpec{Selector: selector("api")},
+		},
+		&appsv1.StatefulSet{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "accuknox", Name: "mongodb"},
+			Spec:       appsv1.StatefulSetSpec{Selector: selector("mongodb")},
+		},
+		pod("accuknox", "api-1", "api", corev1.PodStatus{
+			Phase:     corev1.PodRunning,
+			QOSClass:  corev1.PodQOSBurstable,
+			StartTime: &metav1.Time{Time: started},
+			InitContainerStatuses: []corev1.ContainerStatus{{
+				Name: "migrate",
+				State: corev1.ContainerState{
+					Terminated: &corev1.ContainerStateTerminated{Reason: "Completed"},
+				},
+			}},
+			ContainerStatuses: []corev1.ContainerStatus{{
+				Name:         "api",
+				Ready:        true,
+				RestartCount: 2,
+				State: corev1.ContainerState{
+					Running: &corev1.ContainerStateRunning{},
+				},
+				LastTerminationState: corev1.ContainerState{
+					Terminated: &corev1.ContainerStateTerminated{Reason: "OOMKilled"},
+				},
+			}},
+		}),
+		// an api pod in another namespace isn't selected by the deployment
+		pod("monitoring", "api-2", "api", corev1.PodStatus{}),
+		pod("accuknox", "mongodb-0", "mongodb", corev1.PodStatus{
+			Phase:    corev1.PodPending,
+			QOSClass: corev1.PodQOSBestEffort,
+			ContainerStatuses: []corev1.ContainerStatus{{
+				Name: "mongodb",
+				State: corev1.ContainerState{
+					Waiting: &corev1.ContainerStateWaiting{Reason: "ImagePullBackOff"},
+				},
+			}},
+		}),
+	)
+	cache := kubetest.NewCache(t, client,
+		kube.ResourceDeployments, kube.ResourceStatefulSets, kube.ResourcePods)
+
+	now := time.Now().UTC()
+	r := NewReporter(conf.PodStatus{}, client, cache, nil)
+	got, err := r.Collect(context.Background(), now)
+	if !a.NoError(err) {
+		return
+	}
+	a.Equal(types.Metrics{
+		Timestamp: now,
+		Deployments: []types.Resource{{
+			Name:      "api",
+			Namespace: "accuknox",
+			Pods: []types.Pod{{
+				Name:      "api-1",
+				Status:    "Running",
+				QOSClass:  "Burstable",
+				StartTime: started,
+				Containers: []types.Container{
+					{
+						Name:   "migrate",
+						IsInit: true,
+						State:  "TERMINATED: Reason=Completed",
+					},
+					{
+						Name:                 "api",
+						Ready:                true,
+						State:                "RUNNING",
+						RestartCount:         2,
+						LastTerminationState: "OOMKilled",
+					},
+				},
+			}},
+		}},
+		Statefulsets: []types.Resource{{
+			Name:      "mongodb",
+			Namespace: "accuknox",
+			Pods: []types.Pod{{
+				Name:     "mongodb-0",
+				Status:   "ImagePullBackOff",
+				QOSClass: "BestEffort",
+				Containers: []types.Container{{
+					Name:  "mongodb",
+					State: "WAITING: Reason=ImagePullBackOff",
+				}},
+			}},
+		}},
+	}, got)
+}
+
+func TestPodStatus(t *testing.T) {
+	running := corev1.ContainerStatus{
+		State: corev1.ContainerState{Running: &corev1.ContainerStateRunning{}},
+	}
+	terminated := func(reason string) corev1.ContainerStatus {
+		return corev1.ContainerStatus{
+			State: corev1.ContainerState{
+				Terminated: &corev1.ContainerStateTerminated{Reason: reason},
+			},
+		}
+	}
+	tests := []struct {
+		name   string
+		status corev1.PodStatus
+		want   string
+	}{
+		{
+			name:   "evicted",
+			status: corev1.PodStatus{Phase: corev1.PodFailed, Reason: "Evicted"},
+			want:   "Evicted",
+		},
+		{
+			name: "pending",
+			status: corev1.PodStatus{ContainerStatuses: []corev1.ContainerStatus{
+				running,
+				{State: corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{}}},
+			}},
+			want: "Pending",
+		},
+		{
+			name: "error",
+			status: corev1.PodStatus{ContainerStatuses: []corev1.ContainerStatus{
+				running,
+				terminated("Error"),
+			}},
+			want: "Error",
+		},
+		{
+			name: "completed",
+			status: corev1.PodStatus{ContainerStatuses: []corev1.ContainerStatus{
+				terminated("Completed"),
+			}},
+			want: "Completed",
+		},
+		{
+			name: "running",
+			status: corev1.PodStatus{ContainerStatuses: []corev1.ContainerStatus{
+				running,
+				terminated("Completed"),
+			}},
+			want: "Running",
+		},
+		{
+			name: "unknown",
+			status: corev1.PodStatus{ContainerStatuses: []corev1.ContainerStatus{
+				terminated("OOMKilled"),
+			}},
+			want: "Unknown",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, podStatus(tt.status))
+		})
+	}
+}
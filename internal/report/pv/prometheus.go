@@ -31,11 +31,12 @@ var queries = map[int]string{
 	metricUtilization: queryUtilization,
 }
 
-func query(ctx context.Context, api promV1.API, q string) (model.Vector, error) {
+// query evaluates q at the instant at.
+func query(ctx context.Context, api promV1.API, q string, at time.Time) (model.Vector, error) {
 	ctx, cancel := context.WithTimeout(ctx, time.Second*15)
 	defer cancel()
 
-	result, warnings, err := api.Query(ctx, q, time.Now())
+	result, warnings, err := api.Query(ctx, q, at)
 	if err != nil {
 		return nil, fmt.Errorf("querying prometheus: %w", err)
 	}
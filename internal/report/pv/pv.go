@@ -4,11 +4,15 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
+	"maps"
+	"slices"
 	"time"
 
 	"github.com/accuknox/rinc/internal/conf"
 	"github.com/accuknox/rinc/internal/db"
 	"github.com/accuknox/rinc/internal/report"
+	"github.com/accuknox/rinc/internal/snapshot"
+	"github.com/accuknox/rinc/internal/telemetry"
 	types "github.com/accuknox/rinc/types/pv"
 
 	"github.com/prometheus/client_golang/api"
@@ -20,16 +24,19 @@ import (
 
 // Reporter is the PV utilization reporter.
 type Reporter struct {
-	kubeClient *kubernetes.Clientset
+	kubeClient kubernetes.Interface
+	network    snapshot.Network
 	conf       conf.PVUtilization
 	mongo      *mongo.Client
 }
 
-// NewReporter creates a new PV utilization reporter.
-func NewReporter(c conf.PVUtilization, k *kubernetes.Clientset, mongo *mongo.Client) Reporter {
+// NewReporter creates a new PV utilization reporter. Prometheus is reached
+// through the network n.
+func NewReporter(c conf.PVUtilization, k kubernetes.Interface, n snapshot.Network, mongo *mongo.Client) Reporter {
 	return Reporter{
 		conf:       c,
 		kubeClient: k,
+		network:    n,
 		mongo:      mongo,
 	}
 }
@@ -37,54 +44,9 @@ func NewReporter(c conf.PVUtilization, k *kubernetes.Clientset, mongo *mongo.Cli
 // Report satisfies the report.Reporter interface by fetching the PV
 // utilizations by querying prometheus, and writes the report to the database.
 func (r Reporter) Report(ctx context.Context, now time.Time) error {
-	client, err := api.NewClient(api.Config{
-		Address: r.conf.PrometheusURL,
-	})
+	metrics, err := r.Collect(ctx, now)
 	if err != nil {
-		return fmt.Errorf("creating prometheus client: %w", err)
-	}
-
-	api := promV1.NewAPI(client)
-	pvs := make(types.PVs, 0)
-
-	for metric, q := range queries {
-		vector, err := query(ctx, api, q)
-		if err != nil {
-			return err
-		}
-		for _, sample := range vector {
-			var ns, pvc string
-			if label := sample.Metric["namespace"]; label.IsValid() {
-				ns = string(label)
-			}
-			if label := sample.Metric["persistentvolumeclaim"]; label.IsValid() {
-				pvc = string(label)
-			}
-			slog.LogAttrs(
-				ctx,
-				slog.LevelDebug,
-				"sample",
-				slog.Int("metric", metric),
-				slog.String("namespace", ns),
-				slog.String("pvc", pvc),
-				slog.Float64("value", float64(sample.Value)),
-			)
-			switch metric {
-			case metricCapacity:
-				pvs = pvs.AppendCapacity(pvc, ns, float64(sample.Value))
-			case metricUsed:
-				pvs = pvs.AppendUsed(pvc, ns, float64(sample.Value))
-			case metricAvailable:
-				pvs = pvs.AppendAvailable(pvc, ns, float64(sample.Value))
-			case metricUtilization:
-				pvs = pvs.AppendUtilization(pvc, ns, float64(sample.Value))
-			}
-		}
-	}
-
-	metrics := types.Metrics{
-		Timestamp: now,
-		PVs:       pvs,
+		return err
 	}
 
 	result, err := db.
@@ -136,3 +98,61 @@ func (r Reporter) Report(ctx context.Context, now time.Time) error {
 
 	return nil
 }
+
+// Collect fetches the PV utilizations by querying prometheus without writing
+// anything to the database.
+func (r Reporter) Collect(ctx context.Context, now time.Time) (types.Metrics, error) {
+	client, err := api.NewClient(api.Config{
+		Address:      r.conf.PrometheusURL,
+		RoundTripper: telemetry.Transport(telemetry.APIPrometheus, r.network.Transport(api.DefaultRoundTripper)),
+	})
+	if err != nil {
+		return types.Metrics{}, fmt.Errorf("creating prometheus client: %w", err)
+	}
+
+	api := promV1.NewAPI(client)
+	pvs := types.NewBuilder()
+
+	// query in a fixed order, which is the order of the PVs
+	for _, metric := range slices.Sorted(maps.Keys(queries)) {
+		vector, err := query(ctx, api, queries[metric], now)
+		if err != nil {
+			return types.Metrics{}, err
+		}
+		for _, sample := range vector {
+			var ns, pvc string
+			if label := sample.Metric["namespace"]; label.IsValid() {
+				ns = string(label)
+			}
+			if label := sample.Metric["persistentvolumeclaim"]; label.IsValid() {
+				pvc = string(label)
+			}
+			slog.LogAttrs(
+				ctx,
+				slog.LevelDebug,
+				"sample",
+				slog.Int("metric", metric),
+				slog.String("namespace", ns),
+				slog.String("pvc", pvc),
+				slog.Float64("value", float64(sample.Value)),
+			)
+			switch metric {
+			case metricCapacity:
+				pvs.SetCapacity(pvc, ns, float64(sample.Value))
+			case metricUsed:
+				pvs.SetUsed(pvc, ns, float64(sample.Value))
+			case metricAvailable:
+				pvs.SetAvailable(pvc, ns, float64(sample.Value))
+			case metricUtilization:
+				pvs.SetUtilization(pvc, ns, float64(sample.Value))
+			}
+		}
+	}
+
+	metrics := types.Metrics{
+		Timestamp: now,
+		PVs:       pvs.PVs(),
+	}
+
+	return metrics, nil
+}
@@ -4,7 +4,6 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
-	"net"
 
 	"github.com/accuknox/rinc/internal/util"
 
@@ -16,7 +15,7 @@ import (
 // verifying that at least one RabbitMQ pod is in the READY state, followed by
 // calling the management health check endpoint.
 func (r Reporter) IsClusterUp(ctx context.Context) (bool, error) {
-	ips, err := net.LookupIP(r.conf.HeadlessSvcAddr)
+	ips, err := r.network.LookupIP(ctx, r.conf.HeadlessSvcAddr)
 	if err != nil {
 		return false, fmt.Errorf("lookup %q: %w", r.conf.HeadlessSvcAddr, err)
 	}
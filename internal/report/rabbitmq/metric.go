@@ -53,7 +53,7 @@ func (r Reporter) callEndpoint(ctx context.Context, endp string, v any) error {
 		return fmt.Errorf("creating new http request: %w", err)
 	}
 	req.SetBasicAuth(r.conf.Management.Username, r.conf.Management.Password)
-	resp, err := http.DefaultClient.Do(req)
+	resp, err := r.httpClient.Do(req)
 	if err != nil {
 		return fmt.Errorf("rabbitmq management api request: %w", err)
 	}
@@ -78,7 +78,7 @@ func (r Reporter) callEndpointReturnStatus(ctx context.Context, endp string) (in
 		return 0, fmt.Errorf("creating new http request: %w", err)
 	}
 	req.SetBasicAuth(r.conf.Management.Username, r.conf.Management.Password)
-	resp, err := http.DefaultClient.Do(req)
+	resp, err := r.httpClient.Do(req)
 	if err != nil {
 		return 0, fmt.Errorf("rabbitmq management api request: %w", err)
 	}
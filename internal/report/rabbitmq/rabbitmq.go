@@ -4,11 +4,14 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
+	"net/http"
 	"time"
 
 	"github.com/accuknox/rinc/internal/conf"
 	"github.com/accuknox/rinc/internal/db"
 	"github.com/accuknox/rinc/internal/report"
+	"github.com/accuknox/rinc/internal/snapshot"
+	"github.com/accuknox/rinc/internal/telemetry"
 	types "github.com/accuknox/rinc/types/rabbitmq"
 
 	"go.mongodb.org/mongo-driver/v2/bson"
@@ -18,77 +21,35 @@ import (
 
 // Reporter is the rabbitmq health metrics reporter.
 type Reporter struct {
-	kubeClient *kubernetes.Clientset
+	kubeClient kubernetes.Interface
+	network    snapshot.Network
+	httpClient *http.Client
 	conf       conf.RabbitMQ
 	mongo      *mongo.Client
 }
 
-// NewReporter creates a new of the rabbitmq reporter.
-func NewReporter(c conf.RabbitMQ, k *kubernetes.Clientset, mongo *mongo.Client) Reporter {
+// NewReporter creates a new of the rabbitmq reporter. The management API and
+// the RabbitMQ nodes are reached through the network n.
+func NewReporter(c conf.RabbitMQ, k kubernetes.Interface, n snapshot.Network, mongo *mongo.Client) Reporter {
 	return Reporter{
 		conf:       c,
 		kubeClient: k,
-		mongo:      mongo,
+		network:    n,
+		httpClient: &http.Client{
+			Transport: telemetry.Transport(telemetry.APIRabbitMQ, n.Transport(http.DefaultTransport)),
+		},
+		mongo: mongo,
 	}
 }
 
 // Report satisfies the report.Reporter interface by writing the RabbitMQ
 // cluster status and fetched metrics to the mongodb database.
 func (r Reporter) Report(ctx context.Context, now time.Time) error {
-	up, err := r.IsClusterUp(ctx)
+	metrics, err := r.Collect(ctx, now)
 	if err != nil {
-		slog.LogAttrs(
-			ctx,
-			slog.LevelError,
-			"fetching rabbitmq health status",
-			slog.String("error", err.Error()),
-		)
-		return fmt.Errorf("fetching rabbitmq health status: %w", err)
-	}
-	if !up {
-		slog.LogAttrs(
-			ctx,
-			slog.LevelInfo,
-			"rabbitmq cluster is down",
-		)
-		result, err := db.
-			Database(r.mongo).
-			Collection(db.CollectionRabbitmq).
-			InsertOne(ctx, types.Metrics{
-				Timestamp:   now,
-				IsClusterUp: false,
-			})
-		if err != nil {
-			slog.LogAttrs(
-				ctx,
-				slog.LevelError,
-				"inserting into mongodb",
-				slog.Time("timestamp", now),
-				slog.String("error", err.Error()),
-			)
-			return fmt.Errorf("inserting into mongodb: %w", err)
-		}
-		slog.LogAttrs(
-			ctx,
-			slog.LevelDebug,
-			"rabbitmq: inserted document into mongodb",
-			slog.Any("insertedId", result.InsertedID),
-		)
-		return nil
+		return err
 	}
 
-	metrics, err := r.GetMetrics(ctx)
-	if err != nil {
-		slog.LogAttrs(
-			ctx,
-			slog.LevelError,
-			"failed to fetch rabbitmq metrics",
-			slog.String("error", err.Error()),
-		)
-		return fmt.Errorf("failed to fetch rabbitmq metrics: %w", err)
-	}
-	metrics.Timestamp = now
-
 	result, err := db.Database(r.mongo).
 		Collection(db.CollectionRabbitmq).
 		InsertOne(ctx, metrics)
@@ -108,6 +69,9 @@ func (r Reporter) Report(ctx context.Context, now time.Time) error {
 		"rabbitmq: inserted document into mongodb",
 		slog.Any("insertedId", result.InsertedID),
 	)
+	if !metrics.IsClusterUp {
+		return nil
+	}
 
 	alerts := report.SoftEvaluateAlerts(ctx, r.conf.Alerts, metrics)
 	result, err = db.
@@ -137,3 +101,43 @@ func (r Reporter) Report(ctx context.Context, now time.Time) error {
 
 	return nil
 }
+
+// Collect fetches the RabbitMQ cluster status, and its metrics if it is up,
+// without writing anything to the database.
+func (r Reporter) Collect(ctx context.Context, now time.Time) (types.Metrics, error) {
+	up, err := r.IsClusterUp(ctx)
+	if err != nil {
+		slog.LogAttrs(
+			ctx,
+			slog.LevelError,
+			"fetching rabbitmq health status",
+			slog.String("error", err.Error()),
+		)
+		return types.Metrics{}, fmt.Errorf("fetching rabbitmq health status: %w", err)
+	}
+	if !up {
+		slog.LogAttrs(
+			ctx,
+			slog.LevelInfo,
+			"rabbitmq cluster is down",
+		)
+		return types.Metrics{
+			Timestamp:   now,
+			IsClusterUp: false,
+		}, nil
+	}
+
+	metrics, err := r.GetMetrics(ctx)
+	if err != nil {
+		slog.LogAttrs(
+			ctx,
+			slog.LevelError,
+			"failed to fetch rabbitmq metrics",
+			slog.String("error", err.Error()),
+		)
+		return types.Metrics{}, fmt.Errorf("failed to fetch rabbitmq metrics: %w", err)
+	}
+	metrics.Timestamp = now
+
+	return *metrics, nil
+}
@@ -8,13 +8,16 @@ import (
 
 	"github.com/accuknox/rinc/internal/conf"
 	"github.com/accuknox/rinc/internal/db"
+	"github.com/accuknox/rinc/internal/kube"
 	"github.com/accuknox/rinc/internal/report"
 	types "github.com/accuknox/rinc/types/resource"
 
 	"go.mongodb.org/mongo-driver/v2/bson"
 	"go.mongodb.org/mongo-driver/v2/mongo"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/client-go/kubernetes"
 	v1beta1 "k8s.io/metrics/pkg/apis/metrics/v1beta1"
 	metrics "k8s.io/metrics/pkg/client/clientset/versioned"
@@ -23,38 +26,40 @@ import (
 // Reporter is the resource utilization reporter.
 type Reporter struct {
 	Config
+	// namespaces are the namespaces selected by the configuration, resolved
+	// on every Collect if they are nil.
+	namespaces *kube.Namespaces
 }
 
 type Config struct {
 	ResourceUtilizationConfig conf.ResourceUtilization
-	KubeClient                *kubernetes.Clientset
-	MetricsClient             *metrics.Clientset
+	KubeClient                kubernetes.Interface
+	Cache                     *kube.Cache
+	MetricsClient             metrics.Interface
 	MongoClient               *mongo.Client
 }
 
-// NewReporter creates a new resource utilization reporter.
+// NewReporter creates a new resource utilization reporter. The cache must
+// include pods and nodes.
 func NewReporter(c Config) Reporter {
 	return Reporter{Config: c}
 }
 
+// WithNamespaces returns a copy of r limited to ns, the namespaces already
+// resolved from the configuration, e.g., by the job, instead of resolving them
+// again.
+func (r Reporter) WithNamespaces(ns kube.Namespaces) Reporter {
+	r.namespaces = &ns
+	return r
+}
+
 // Report satisfies the report.Reporter interface by fetching the resource
 // utilizations of nodes & pods from the Kubernetes metrics API server, and
 // writes the report to the database.
 func (r Reporter) Report(ctx context.Context, now time.Time) error {
-	nodes, err := r.nodeUsage(ctx)
+	metrics, err := r.Collect(ctx, now)
 	if err != nil {
-		return fmt.Errorf("fetching node usage: %w", err)
-	}
-
-	containers, err := r.containerUsage(ctx)
-	if err != nil {
-		return fmt.Errorf("fetching pod usage: %w", err)
-	}
-
-	metrics := types.Metrics{
-		Timestamp:  now,
-		Nodes:      nodes,
-		Containers: containers,
+		return err
 	}
 
 	result, err := db.
@@ -111,10 +116,36 @@ func (r Reporter) Report(ctx context.Context, now time.Time) error {
 	return nil
 }
 
+// Collect fetches the resource utilizations of nodes & pods without writing
+// anything to the database.
+func (r Reporter) Collect(ctx context.Context, now time.Time) (types.Metrics, error) {
+	nodes, err := r.nodeUsage(ctx)
+	if err != nil {
+		return types.Metrics{}, fmt.Errorf("fetching node usage: %w", err)
+	}
+
+	ns, err := r.resolveNamespaces(ctx)
+	if err != nil {
+		return types.Metrics{}, fmt.Errorf("resolving namespaces: %w", err)
+	}
+
+	containers, err := r.containerUsage(ctx, ns)
+	if err != nil {
+		return types.Metrics{}, fmt.Errorf("fetching pod usage: %w", err)
+	}
+
+	metrics := types.Metrics{
+		Timestamp:  now,
+		Nodes:      nodes,
+		Containers: containers,
+	}
+
+	return metrics, nil
+}
+
 func (r Reporter) nodeUsage(ctx context.Context) ([]types.Node, error) {
 	var (
-		nodes   []types.Node
-		metric  []nodeMetric
+		metric  = make(map[string]nodeMetric)
 		cntinue string
 	)
 	for {
@@ -129,11 +160,10 @@ func (r Reporter) nodeUsage(ctx context.Context) ([]types.Node, error) {
 			return nil, fmt.Errorf("fetching node metrics: %w", err)
 		}
 		for _, m := range metrics.Items {
-			metric = append(metric, nodeMetric{
-				name: m.Name,
-				cpu:  m.Usage.Cpu(),
-				mem:  m.Usage.Memory(),
-			})
+			metric[m.Name] = nodeMetric{
+				cpu: m.Usage.Cpu(),
+				mem: m.Usage.Memory(),
+			}
 		}
 		cntinue = metrics.Continue
 		if cntinue == "" {
@@ -146,68 +176,29 @@ func (r Reporter) nodeUsage(ctx context.Context) ([]types.Node, error) {
 		}
 	}
 
-	cntinue = ""
-	for {
-		nodeList, err := r.KubeClient.
-			CoreV1().
-			Nodes().
-			List(ctx, metav1.ListOptions{
-				Limit:    30,
-				Continue: cntinue,
-			})
-		if err != nil {
-			return nil, fmt.Errorf("fetching nodes: %w", err)
-		}
-		for _, n := range nodeList.Items {
-			var nmetric *nodeMetric = nil
-			for _, m := range metric {
-				if m.name == n.Name {
-					nmetric = &m
-				}
-			}
-			if nmetric == nil {
-				continue
-			}
-			cpu := percentage(*nmetric.cpu, *n.Status.Capacity.Cpu())
-			mem := percentage(*nmetric.mem, *n.Status.Capacity.Memory())
-			slog.LogAttrs(
-				ctx,
-				slog.LevelDebug,
-				"NODE UTILIZATION %",
-				slog.String("name", n.Name),
-				slog.Float64("cpu", cpu),
-				slog.Float64("mem", mem),
-			)
-			nodes = append(nodes, types.Node{
-				Name:           n.Name,
-				CPUUsedPercent: cpu,
-				MemUsedPercent: mem,
-			})
-		}
-		cntinue = nodeList.Continue
-		if cntinue == "" {
-			slog.LogAttrs(
-				ctx,
-				slog.LevelInfo,
-				"received capacity of all nodes",
-			)
-			break
-		}
+	nodeList, err := r.Cache.Nodes()
+	if err != nil {
+		return nil, fmt.Errorf("fetching nodes: %w", err)
 	}
+	nodes := joinNodeUsage(ctx, metric, nodeList)
+	slog.LogAttrs(
+		ctx,
+		slog.LevelInfo,
+		"received capacity of all nodes",
+	)
 
 	return nodes, nil
 }
 
-func (r Reporter) containerUsage(ctx context.Context) ([]types.Container, error) {
+func (r Reporter) containerUsage(ctx context.Context, ns kube.Namespaces) ([]types.Container, error) {
 	var (
-		containers []types.Container
-		metric     []podMetric
-		cntinue    string
+		metric  = make(map[podKey]podMetric)
+		cntinue string
 	)
 	for {
 		metrics, err := r.MetricsClient.
 			MetricsV1beta1().
-			PodMetricses(r.ResourceUtilizationConfig.Namespace).
+			PodMetricses(ns.List).
 			List(ctx, metav1.ListOptions{
 				Limit:    30,
 				Continue: cntinue,
@@ -216,11 +207,16 @@ func (r Reporter) containerUsage(ctx context.Context) ([]types.Container, error)
 			return nil, fmt.Errorf("fetching pod metrics: %w", err)
 		}
 		for _, m := range metrics.Items {
-			metric = append(metric, podMetric{
-				name:       m.Name,
-				namespace:  m.Namespace,
-				containers: m.Containers,
-			})
+			if !ns.Contains(m.Namespace) {
+				continue
+			}
+			containers := make(map[string]v1beta1.ContainerMetrics, len(m.Containers))
+			for _, c := range m.Containers {
+				containers[c.Name] = c
+			}
+			metric[podKey{namespace: m.Namespace, name: m.Name}] = podMetric{
+				containers: containers,
+			}
 		}
 		cntinue = metrics.Continue
 		if cntinue == "" {
@@ -233,91 +229,107 @@ func (r Reporter) containerUsage(ctx context.Context) ([]types.Container, error)
 		}
 	}
 
-	cntinue = ""
-	for {
-		podList, err := r.KubeClient.
-			CoreV1().
-			Pods(r.ResourceUtilizationConfig.Namespace).
-			List(ctx, metav1.ListOptions{
-				Limit:    30,
-				Continue: cntinue,
-			})
-		if err != nil {
-			return nil, fmt.Errorf("fetching pods: %w", err)
+	podList, err := r.Cache.Pods(ns, labels.Everything())
+	if err != nil {
+		return nil, fmt.Errorf("fetching pods: %w", err)
+	}
+	containers := joinContainerUsage(ctx, metric, podList)
+	slog.LogAttrs(
+		ctx,
+		slog.LevelInfo,
+		"received capacity of all pods",
+	)
+
+	return containers, nil
+}
+
+// joinNodeUsage joins the usage metrics of nodes, keyed by node name, with
+// their capacity.
+func joinNodeUsage(ctx context.Context, metric map[string]nodeMetric, nodeList []*corev1.Node) []types.Node {
+	var nodes []types.Node
+	for _, n := range nodeList {
+		nmetric, ok := metric[n.Name]
+		if !ok {
+			continue
 		}
-		for _, p := range podList.Items {
-			var pmetric *podMetric = nil
-			for _, m := range metric {
-				if m.name == p.Name && m.namespace == p.Namespace {
-					pmetric = &m
-				}
-			}
-			if pmetric == nil {
+		cpu := percentage(*nmetric.cpu, *n.Status.Capacity.Cpu())
+		mem := percentage(*nmetric.mem, *n.Status.Capacity.Memory())
+		slog.LogAttrs(
+			ctx,
+			slog.LevelDebug,
+			"NODE UTILIZATION %",
+			slog.String("name", n.Name),
+			slog.Float64("cpu", cpu),
+			slog.Float64("mem", mem),
+		)
+		nodes = append(nodes, types.Node{
+			Name:           n.Name,
+			CPUUsedPercent: cpu,
+			MemUsedPercent: mem,
+		})
+	}
+	return nodes
+}
+
+// joinContainerUsage joins the usage metrics of pods, keyed by namespace and
+// name, with the limits of their containers.
+func joinContainerUsage(ctx context.Context, metric map[podKey]podMetric, podList []*corev1.Pod) []types.Container {
+	var containers []types.Container
+	for _, p := range podList {
+		pmetric, ok := metric[podKey{namespace: p.Namespace, name: p.Name}]
+		if !ok {
+			continue
+		}
+		for _, c := range p.Spec.Containers {
+			cmetric, ok := pmetric.containers[c.Name]
+			if !ok {
 				continue
 			}
-			for _, c := range p.Spec.Containers {
-				var cmetric *v1beta1.ContainerMetrics = nil
-				for _, mc := range pmetric.containers {
-					if c.Name == mc.Name {
-						cmetric = &mc
-					}
-				}
-				if cmetric == nil {
-					continue
-				}
-				cpuCap := c.Resources.Limits.Cpu()
-				memCap := c.Resources.Limits.Memory()
-				cpuUsed := cmetric.Usage.Cpu()
-				memUsed := cmetric.Usage.Memory()
-				cpu := percentage(*cpuUsed, *cpuCap)
-				mem := percentage(*memUsed, *memCap)
-				containers = append(containers, types.Container{
-					PodName:        p.Name,
-					Namespace:      p.Namespace,
-					Name:           c.Name,
-					CPULimit:       cpuCap.AsApproximateFloat64(),
-					MemLimit:       memCap.AsApproximateFloat64(),
-					CPUUsed:        cpuUsed.AsApproximateFloat64(),
-					MemUsed:        memUsed.AsApproximateFloat64(),
-					CPUUsedPercent: cpu,
-					MemUsedPercent: mem,
-				})
-				slog.LogAttrs(
-					ctx,
-					slog.LevelDebug,
-					"CONTAINER UTILIZATION %",
-					slog.String("name", c.Name),
-					slog.String("pod", p.Name),
-					slog.String("namespace", p.Namespace),
-					slog.Float64("cpu", cpu),
-					slog.Float64("mem", mem),
-				)
-			}
-		}
-		cntinue = podList.Continue
-		if cntinue == "" {
+			cpuCap := c.Resources.Limits.Cpu()
+			memCap := c.Resources.Limits.Memory()
+			cpuUsed := cmetric.Usage.Cpu()
+			memUsed := cmetric.Usage.Memory()
+			cpu := percentage(*cpuUsed, *cpuCap)
+			mem := percentage(*memUsed, *memCap)
+			containers = append(containers, types.Container{
+				PodName:        p.Name,
+				Namespace:      p.Namespace,
+				Name:           c.Name,
+				CPULimit:       cpuCap.AsApproximateFloat64(),
+				MemLimit:       memCap.AsApproximateFloat64(),
+				CPUUsed:        cpuUsed.AsApproximateFloat64(),
+				MemUsed:        memUsed.AsApproximateFloat64(),
+				CPUUsedPercent: cpu,
+				MemUsedPercent: mem,
+			})
 			slog.LogAttrs(
 				ctx,
-				slog.LevelInfo,
-				"received capacity of all pods",
+				slog.LevelDebug,
+				"CONTAINER UTILIZATION %",
+				slog.String("name", c.Name),
+				slog.String("pod", p.Name),
+				slog.String("namespace", p.Namespace),
+				slog.Float64("cpu", cpu),
+				slog.Float64("mem", mem),
 			)
-			break
 		}
 	}
-
-	return containers, nil
+	return containers
 }
 
 type nodeMetric struct {
-	name string
-	cpu  *resource.Quantity
-	mem  *resource.Quantity
+	cpu *resource.Quantity
+	mem *resource.Quantity
+}
+
+type podKey struct {
+	namespace string
+	name      string
 }
 
 type podMetric struct {
-	name       string
-	namespace  string
-	containers []v1beta1.ContainerMetrics
+	// containers are the container metrics keyed by container name.
+	containers map[string]v1beta1.ContainerMetrics
 }
 
 func percentage(used, total resource.Quantity) float64 {
@@ -328,3 +340,12 @@ func percentage(used, total resource.Quantity) float64 {
 	}
 	return usedFloat * 100 / totalFloat
 }
+
+// resolveNamespaces returns the namespaces passed with WithNamespaces, or
+// resolves the ones selected by the configuration.
+func (r Reporter) resolveNamespaces(ctx context.Context) (kube.Namespaces, error) {
+	if r.namespaces != nil {
+		return *r.namespaces, nil
+	}
+	return kube.ResolveNamespaces(ctx, r.KubeClient, r.ResourceUtilizationConfig.Namespaces)
+}
@@ -0,0 +1,351 @@
+package resource
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/accuknox/rinc/internal/conf"
+	"github.com/accuknox/rinc/internal/kube"
+	"github.com/accuknox/rinc/internal/kube/kubetest"
+	types "github.com/accuknox/rinc/types/resource"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	v1beta1 "k8s.io/metrics/pkg/apis/metrics/v1beta1"
+	metricsfake "k8s.io/metrics/pkg/client/clientset/versioned/fake"
+)
+
+func TestCollect(t *testing.T) {
+	a := assert.New(t)
+	client := fake.NewSimpleClientset(
+		newNode("node-1"),
+		newPod("accuknox", "api", "app"),
+		newPod("monitoring", "grafana", "app"),
+	)
+	cache := kubetest.NewCache(t, client, kube.ResourcePods, kube.ResourceNodes)
+
+	// The fake metrics clientset guesses the resources of the objects it is
+	// created with from their kinds, i.e., "nodemetricses", while the client
+	// reads "nodes" and "pods", so the metrics are added to the tracker with
+	// the resources the client reads.
+	metricsClient := metricsfake.NewSimpleClientset()
+	nodeMetrics := &v1beta1.NodeMetrics{
+		ObjectMeta: metav1.ObjectMeta{Name: "node-1"},
+		Usage: corev1.ResourceList{
+			corev1.ResourceCPU:    resource.MustParse("2"),
+			corev1.ResourceMemory: resource.MustParse("4Gi"),
+		},
+	}
+	err := metricsClient.Tracker().Create(
+		v1beta1.SchemeGroupVersion.WithResource("nodes"), nodeMetrics, "")
+	if !a.NoError(err) {
+		return
+	}
+	for _, p := range []*corev1.Pod{
+		newPod("accuknox", "api"),
+		newPod("monitoring", "grafana"),
+	} {
+		podMetrics := &v1beta1.PodMetrics{
+			ObjectMeta: p.ObjectMeta,
+			Containers: []v1beta1.ContainerMetrics{
+				newPodMetric("app").containers["app"],
+			},
+		}
+		err := metricsClient.Tracker().Create(
+			v1beta1.SchemeGroupVersion.WithResource("pods"), podMetrics, p.Namespace)
+		if !a.NoError(err) {
+			return
+		}
+	}
+
+	now := time.Now().UTC()
+	r := NewReporter(Config{
+		ResourceUtilizationConfig: conf.ResourceUtilization{
+			Namespaces: conf.NamespaceSelector{Include: []string{"accuknox"}},
+		},
+		KubeClient:    client,
+		Cache:         cache,
+		MetricsClient: metricsClient,
+	})
+	got, err := r.Collect(context.Background(), now)
+	if !a.NoError(err) {
+		return
+	}
+	a.Equal(types.Metrics{
+		Timestamp: now,
+		Nodes: []types.Node{
+			{Name: "node-1", CPUUsedPercent: 25, MemUsedPercent: 12.5},
+		},
+		Containers: []types.Container{{
+			PodName:        "api",
+			Namespace:      "accuknox",
+			Name:           "app",
+			CPULimit:       1,
+			MemLimit:       1 << 30,
+			CPUUsed:        0.1,
+			MemUsed:        100 << 20,
+			CPUUsedPercent: 10,
+			MemUsedPercent: 100.0 * 100 / 1024,
+		}},
+	}, got)
+}
+
+func TestJoinNodeUsage(t *testing.T) {
+	a := assert.New(t)
+	metric := map[string]nodeMetric{
+		"node-1": newNodeMetric("2", "4Gi"),
+		"node-3": newNodeMetric("1", "1Gi"),
+	}
+	nodes := []*corev1.Node{
+		newNode("node-1"),
+		newNode("node-2"),
+	}
+	a.Equal([]types.Node{
+		{Name: "node-1", CPUUsedPercent: 25, MemUsedPercent: 12.5},
+	}, joinNodeUsage(context.Background(), metric, nodes))
+}
+
+func TestJoinContainerUsage(t *testing.T) {
+	a := assert.New(t)
+	metric := map[podKey]podMetric{
+		{namespace: "accuknox", name: "api"}: newPodMetric("app", "sidecar"),
+		// same pod name, different namespace
+		{namespace: "monitoring", name: "web"}: newPodMetric("app"),
+	}
+	pods := []*corev1.Pod{
+		newPod("accuknox", "api", "app", "init"),
+		newPod("accuknox", "web", "app"),
+		newPod("monitoring", "web", "app"),
+	}
+	a.Equal([]types.Container{
+		{
+			PodName:        "api",
+			Namespace:      "accuknox",
+			Name:           "app",
+			CPULimit:       1,
+			MemLimit:       1 << 30,
+			CPUUsed:        0.1,
+			MemUsed:        100 << 20,
+			CPUUsedPercent: 10,
+			MemUsedPercent: 100.0 * 100 / 1024,
+		},
+		{
+			PodName:        "web",
+			Namespace:      "monitoring",
+			Name:           "app",
+			CPULimit:       1,
+			MemLimit:       1 << 30,
+			CPUUsed:        0.1,
+			MemUsed:        100 << 20,
+			CPUUsedPercent: 10,
+			MemUsedPercent: 100.0 * 100 / 1024,
+		},
+	}, joinContainerUsage(context.Background(), metric, pods))
+}
+
+func TestScanBaseline(t *testing.T) {
+	a := assert.New(t)
+	nodes := []*corev1.Node{newNode("node-1"), newNode("node-2")}
+	metric := map[string]nodeMetric{"node-1": newNodeMetric("2", "4Gi")}
+	scanned := []scannedNodeMetric{{name: "node-1", nodeMetric: metric["node-1"]}}
+	a.Equal(joinNodeUsage(context.Background(), metric, nodes), scanNodeUsage(scanned, nodes))
+
+	pods := []*corev1.Pod{
+		newPod("accuknox", "api", "app", "init"),
+		newPod("monitoring", "api", "app"),
+	}
+	podMetrics := map[podKey]podMetric{
+		{namespace: "accuknox", name: "api"}: newPodMetric("app", "sidecar"),
+	}
+	scannedPods := []scannedPodMetric{newScannedPodMetric("accuknox", "api", "app", "sidecar")}
+	a.Equal(joinContainerUsage(context.Background(), podMetrics, pods), scanContainerUsage(scannedPods, pods))
+}
+
+func BenchmarkJoinNodeUsage(b *testing.B) {
+	for _, n := range []int{100, 1000, 5000} {
+		metric := make(map[string]nodeMetric, n)
+		scanned := make([]scannedNodeMetric, 0, n)
+		nodes := make([]*corev1.Node, n)
+		for idx := range nodes {
+			name := fmt.Sprintf("node-%d", idx)
+			nodes[idx] = newNode(name)
+			metric[name] = newNodeMetric("1", "1Gi")
+			scanned = append(scanned, scannedNodeMetric{name: name, nodeMetric: metric[name]})
+		}
+		b.Run(fmt.Sprintf("map/%d", n), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				joinNodeUsage(context.Background(), metric, nodes)
+			}
+		})
+		b.Run(fmt.Sprintf("scan/%d", n), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				scanNodeUsage(scanned, nodes)
+			}
+		})
+	}
+}
+
+func BenchmarkJoinContainerUsage(b *testing.B) {
+	for _, n := range []int{100, 1000, 5000} {
+		metric := make(map[podKey]podMetric, n)
+		scanned := make([]scannedPodMetric, 0, n)
+		pods := make([]*corev1.Pod, n)
+		for idx := range pods {
+			ns := fmt.Sprintf("ns-%d", idx%10)
+			name := fmt.Sprintf("pod-%d", idx)
+			pods[idx] = newPod(ns, name, "c-0", "c-1", "c-2")
+			metric[podKey{namespace: ns, name: name}] = newPodMetric("c-0", "c-1", "c-2")
+			scanned = append(scanned, newScannedPodMetric(ns, name, "c-0", "c-1", "c-2"))
+		}
+		b.Run(fmt.Sprintf("map/%d", n), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				joinContainerUsage(context.Background(), metric, pods)
+			}
+		})
+		b.Run(fmt.Sprintf("scan/%d", n), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				scanContainerUsage(scanned, pods)
+			}
+		})
+	}
+}
+
+// The scan baselines join the metrics the way the reporter did before they
+// were keyed by name, i.e., by scanning all of them for every node, pod and
+// container, so that the benchmarks compare both.
+
+type scannedNodeMetric struct {
+	name string
+	nodeMetric
+}
+
+type scannedPodMetric struct {
+	namespace  string
+	name       string
+	containers []v1beta1.ContainerMetrics
+}
+
+func newScannedPodMetric(namespace, name string, containers ...string) scannedPodMetric {
+	m := scannedPodMetric{namespace: namespace, name: name}
+	for _, c := range containers {
+		m.containers = append(m.containers, newPodMetric(c).containers[c])
+	}
+	return m
+}
+
+func scanNodeUsage(metric []scannedNodeMetric, nodeList []*corev1.Node) []types.Node {
+	var nodes []types.Node
+	for _, n := range nodeList {
+		var nmetric *scannedNodeMetric
+		for idx := range metric {
+			if metric[idx].name == n.Name {
+				nmetric = &metric[idx]
+			}
+		}
+		if nmetric == nil {
+			continue
+		}
+		nodes = append(nodes, types.Node{
+			Name:           n.Name,
+			CPUUsedPercent: percentage(*nmetric.cpu, *n.Status.Capacity.Cpu()),
+			MemUsedPercent: percentage(*nmetric.mem, *n.Status.Capacity.Memory()),
+		})
+	}
+	return nodes
+}
+
+func scanContainerUsage(metric []scannedPodMetric, podList []*corev1.Pod) []types.Container {
+	var containers []types.Container
+	for _, p := range podList {
+		var pmetric *scannedPodMetric
+		for idx := range metric {
+			if metric[idx].name == p.Name && metric[idx].namespace == p.Namespace {
+				pmetric = &metric[idx]
+			}
+		}
+		if pmetric == nil {
+			continue
+		}
+		for _, c := range p.Spec.Containers {
+			var cmetric *v1beta1.ContainerMetrics
+			for idx := range pmetric.containers {
+				if pmetric.containers[idx].Name == c.Name {
+					cmetric = &pmetric.containers[idx]
+				}
+			}
+			if cmetric == nil {
+				continue
+			}
+			cpuCap := c.Resources.Limits.Cpu()
+			memCap := c.Resources.Limits.Memory()
+			cpuUsed := cmetric.Usage.Cpu()
+			memUsed := cmetric.Usage.Memory()
+			containers = append(containers, types.Container{
+				PodName:        p.Name,
+				Namespace:      p.Namespace,
+				Name:           c.Name,
+				CPULimit:       cpuCap.AsApproximateFloat64(),
+				MemLimit:       memCap.AsApproximateFloat64(),
+				CPUUsed:        cpuUsed.AsApproximateFloat64(),
+				MemUsed:        memUsed.AsApproximateFloat64(),
+				CPUUsedPercent: percentage(*cpuUsed, *cpuCap),
+				MemUsedPercent: percentage(*memUsed, *memCap),
+			})
+		}
+	}
+	return containers
+}
+
+func newNode(name string) *corev1.Node {
+	return &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Status: corev1.NodeStatus{
+			Capacity: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse("8"),
+				corev1.ResourceMemory: resource.MustParse("32Gi"),
+			},
+		},
+	}
+}
+
+func newNodeMetric(cpu, mem string) nodeMetric {
+	c, m := resource.MustParse(cpu), resource.MustParse(mem)
+	return nodeMetric{cpu: &c, mem: &m}
+}
+
+func newPod(namespace, name string, containers ...string) *corev1.Pod {
+	p := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name},
+	}
+	for _, c := range containers {
+		p.Spec.Containers = append(p.Spec.Containers, corev1.Container{
+			Name: c,
+			Resources: corev1.ResourceRequirements{
+				Limits: corev1.ResourceList{
+					corev1.ResourceCPU:    resource.MustParse("1"),
+					corev1.ResourceMemory: resource.MustParse("1Gi"),
+				},
+			},
+		})
+	}
+	return p
+}
+
+func newPodMetric(containers ...string) podMetric {
+	m := podMetric{containers: make(map[string]v1beta1.ContainerMetrics, len(containers))}
+	for _, c := range containers {
+		m.containers[c] = v1beta1.ContainerMetrics{
+			Name: c,
+			Usage: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse("100m"),
+				corev1.ResourceMemory: resource.MustParse("100Mi"),
+			},
+		}
+	}
+	return m
+}
@@ -0,0 +1,215 @@
+// Code generated by gen/main.go; DO NOT EDIT.
+
+package schema
+
+// configComments contains the doc comments of the configuration types
+// and their fields by fully qualified name.
+var configComments = map[string]string{
+	"github.com/accuknox/rinc/internal/conf.Alert":                                       "Alert includes a message template, a severity level, and a conditional expression to trigger the alert.",
+	"github.com/accuknox/rinc/internal/conf.Alert.Message":                               "Message can be a go template literal or a string literal.",
+	"github.com/accuknox/rinc/internal/conf.Alert.Severity":                              "Severity can be \"info\", \"warning\", \"critical\"",
+	"github.com/accuknox/rinc/internal/conf.Alert.When":                                  "When is a gval boolean expressions that when evaluated to true, fires\nthe alert.",
+	"github.com/accuknox/rinc/internal/conf.Auth":                                        "Auth contains configuration related to authenticating the users of the web server.",
+	"github.com/accuknox/rinc/internal/conf.Auth.Basic":                                  "Basic contains the configuration of the basic auth method.",
+	"github.com/accuknox/rinc/internal/conf.Auth.Method":                                 "Method is the authentication method. Users aren't authenticated if it\nis empty.\nPossible values: \"\", \"basic\", \"oidc\".",
+	"github.com/accuknox/rinc/internal/conf.Auth.OIDC":                                   "OIDC contains the configuration of the OpenID Connect login method.",
+	"github.com/accuknox/rinc/internal/conf.Auth.Session":                                "Session contains configuration related to the session cookies.",
+	"github.com/accuknox/rinc/internal/conf.BasicAuth":                                   "BasicAuth contains the configuration of the basic auth method.",
+	"github.com/accuknox/rinc/internal/conf.BasicAuth.Users":                             "Users are the users allowed to log in.",
+	"github.com/accuknox/rinc/internal/conf.BasicAuthUser":                               "BasicAuthUser is a user allowed to log in with basic auth.",
+	"github.com/accuknox/rinc/internal/conf.BasicAuthUser.PasswordHash":                  "PasswordHash is the bcrypt hash of the password of the user, e.g.,\ngenerated with `htpasswd -nbB <username> <password>`.",
+	"github.com/accuknox/rinc/internal/conf.C":                                           "C contains all configuration data that can be passed to the reporter.",
+	"github.com/accuknox/rinc/internal/conf.C.Auth":                                      "Auth contains configuration related to authenticating the users of\nthe web server.",
+	"github.com/accuknox/rinc/internal/conf.C.Ceph":                                      "Ceph contains configuration related to the ceph status reporter.",
+	"github.com/accuknox/rinc/internal/conf.C.Check":                                     "Check contains the options of the `check` subcommand.",
+	"github.com/accuknox/rinc/internal/conf.C.ConfFiles":                                 "ConfFiles are the configuration files the configuration was loaded\nfrom.",
+	"github.com/accuknox/rinc/internal/conf.C.Connectivity":                              "Connectivity contains configuration related to the connectivity status\nreporter.",
+	"github.com/accuknox/rinc/internal/conf.C.DaSS":                                      "DaSS contains configuration related to the deployment and statefulset\nstatus reporter.",
+	"github.com/accuknox/rinc/internal/conf.C.ImageTag":                                  "ImageTag contains configuration related to the image tag\nreporter.",
+	"github.com/accuknox/rinc/internal/conf.C.KubernetesClient":                          "KubernetesClient contains the configuration needed to communicate with\nthe Kubernetes API server.",
+	"github.com/accuknox/rinc/internal/conf.C.Log":                                       "Log contains configuration for logs.",
+	"github.com/accuknox/rinc/internal/conf.C.LongJobs":                                  "LongJobs contains configuration related to the long-running job\nreporter.",
+	"github.com/accuknox/rinc/internal/conf.C.Output":                                    "Output contains the options to write the scraped reports to files or\nstdout instead of MongoDB.",
+	"github.com/accuknox/rinc/internal/conf.C.PVUtilization":                             "PVUtilization contains configuration related to the PV utilization\nreporter.",
+	"github.com/accuknox/rinc/internal/conf.C.PodStatus":                                 "PodStatus contains configuration related to the pod status reporter.",
+	"github.com/accuknox/rinc/internal/conf.C.REPL":                                      "REPL contains the options of the `expr` subcommand.",
+	"github.com/accuknox/rinc/internal/conf.C.RabbitMQ":                                  "RabbitMQ contains the rabbitmq configuration.",
+	"github.com/accuknox/rinc/internal/conf.C.Readiness":                                 "Readiness contains configuration related to the readiness endpoint of\nthe web server.",
+	"github.com/accuknox/rinc/internal/conf.C.ResourceUtilization":                       "ResourceUtilization contains configuration related to the resource\nutilization reporter.",
+	"github.com/accuknox/rinc/internal/conf.C.Snapshot":                                  "Snapshot contains the options to record or replay a snapshot.",
+	"github.com/accuknox/rinc/internal/conf.C.Telemetry":                                 "Telemetry contains configuration related to the instrumentation of\nRINC itself.",
+	"github.com/accuknox/rinc/internal/conf.C.TerminationGracePeriod":                    "TerminationGracePeriod is the period after which the web server\nmust be forcefully terminated. A value of 0 implies no forceful\ntermination.",
+	"github.com/accuknox/rinc/internal/conf.C.Vault":                                     "Vault contains the configuration needed to resolve configuration\nvalues referencing secrets stored in vault.",
+	"github.com/accuknox/rinc/internal/conf.Ceph":                                        "Ceph contains all configuration related to ceph status reporter.",
+	"github.com/accuknox/rinc/internal/conf.Ceph.Alerts":                                 "Alerts contain a message template, a severity level, and a conditional\nexpression to trigger the respective alert.",
+	"github.com/accuknox/rinc/internal/conf.Ceph.DashboardAPI":                           "DashboardAPI contains configuration to access the ceph dashboard api.\n\nRequired.",
+	"github.com/accuknox/rinc/internal/conf.Ceph.Enable":                                 "Enable enables ceph status reporter.",
+	"github.com/accuknox/rinc/internal/conf.CephDashboardAPI":                            "CephDashboardAPI contains configuration to access the ceph dashboard API.",
+	"github.com/accuknox/rinc/internal/conf.CephDashboardAPI.Password":                   "Password to authenticate with ceph dashboard API.\n\nRequired.",
+	"github.com/accuknox/rinc/internal/conf.CephDashboardAPI.PasswordFile":               "PasswordFile is the path to a file containing the password. It takes\nprecedence over Password.",
+	"github.com/accuknox/rinc/internal/conf.CephDashboardAPI.URL":                        "URL is the ceph dashboard API url.\n\nFor example:\nhttps://rook-ceph-mgr-dashboard.rook-ceph.svc.cluster.local:8443\n\nRequired.",
+	"github.com/accuknox/rinc/internal/conf.CephDashboardAPI.Username":                   "Username to authenticate with ceph dashboard API.\n\nRequired.",
+	"github.com/accuknox/rinc/internal/conf.CephDashboardAPI.UsernameFile":               "UsernameFile is the path to a file containing the username. It takes\nprecedence over Username.",
+	"github.com/accuknox/rinc/internal/conf.Check":                                       "Check contains the options of the health gate started with the `check` subcommand, which generates the selected reports repeatedly and exits with a code reflecting the worst severity of the alerts that fired.",
+	"github.com/accuknox/rinc/internal/conf.Check.Enable":                                "Enable is set when the `check` subcommand is used.",
+	"github.com/accuknox/rinc/internal/conf.Check.Interval":                              "Interval is the duration between the starts of two runs.",
+	"github.com/accuknox/rinc/internal/conf.Check.Reporters":                             "Reporters are the collections of the reports to generate, e.g., dass\nand podstatus. All enabled reporters are run if it is empty.",
+	"github.com/accuknox/rinc/internal/conf.Check.Timeout":                               "Timeout is the duration to check for.",
+	"github.com/accuknox/rinc/internal/conf.Connectivity":                                "Connectivity contains all configuration related to connectivity status reporter.",
+	"github.com/accuknox/rinc/internal/conf.Connectivity.Alerts":                         "Alerts contain a message template, a severity level, and a\nconditional expression to trigger the respective alert.",
+	"github.com/accuknox/rinc/internal/conf.Connectivity.Metabase":                       "Metabase contains all configuration related to metabase connectivity\ncheck.",
+	"github.com/accuknox/rinc/internal/conf.Connectivity.Mongodb":                        "Mongodb contains all configuration related to mongodb connectivity\ncheck.",
+	"github.com/accuknox/rinc/internal/conf.Connectivity.Neo4j":                          "Neo4j contains all configuration related to neo4j connectivity check.",
+	"github.com/accuknox/rinc/internal/conf.Connectivity.Postgres":                       "Postgres contains all configuration related to postgres connectivity check.",
+	"github.com/accuknox/rinc/internal/conf.Connectivity.Redis":                          "Redis contains all configuration related to redis/keydb connectivity\ncheck.",
+	"github.com/accuknox/rinc/internal/conf.Connectivity.Vault":                          "Vault contains all configuration related to vault connectivity check.",
+	"github.com/accuknox/rinc/internal/conf.DaSS":                                        "DaSS contains configuration related to the deployment and statefulset status reporter.",
+	"github.com/accuknox/rinc/internal/conf.DaSS.Alerts":                                 "Alerts contain a message template, a severity level, and a conditional\nexpression to trigger the respective alert.",
+	"github.com/accuknox/rinc/internal/conf.DaSS.Enable":                                 "Enable specifies whether the deployment and statefulset status (DaSS)\nreporter is enabled.",
+	"github.com/accuknox/rinc/internal/conf.DaSS.LabelSelector":                          "LabelSelector limits the reporter to the deployments and statefulsets\nmatching the label selector, e.g., \"app.kubernetes.io/part-of=accuknox\".\nLeave blank for all deployments and statefulsets.",
+	"github.com/accuknox/rinc/internal/conf.DaSS.Namespace":                              "Namespace is the single Kubernetes namespace that the DaSS reporter\nwill be limited to.\n\nDeprecated: use Namespaces.",
+	"github.com/accuknox/rinc/internal/conf.DaSS.Namespaces":                             "Namespaces selects the Kubernetes namespaces that the DaSS reporter\nwill be limited to. Leave blank for all namespaces.",
+	"github.com/accuknox/rinc/internal/conf.Expr":                                        "Expr consists of an evaluable gval expression.",
+	"github.com/accuknox/rinc/internal/conf.FieldError":                                  "FieldError is a validation error of a configuration field.",
+	"github.com/accuknox/rinc/internal/conf.FieldError.Err":                              "Err describes what is wrong with the field.",
+	"github.com/accuknox/rinc/internal/conf.FieldError.Path":                             "Path is the configuration key of the field, e.g., `ceph.alerts[0].when`.",
+	"github.com/accuknox/rinc/internal/conf.ImageTag":                                    "ImageTag contains configuration related to the image tag reporter.",
+	"github.com/accuknox/rinc/internal/conf.ImageTag.Alerts":                             "Alerts contain a message template, a severity level, and a conditional\nexpression to trigger the respective alert.",
+	"github.com/accuknox/rinc/internal/conf.ImageTag.Enable":                             "Enable specifies whether the image tag reporter is enabled.",
+	"github.com/accuknox/rinc/internal/conf.ImageTag.LabelSelector":                      "LabelSelector limits the reporter to the deployments and statefulsets\nmatching the label selector, e.g., \"app.kubernetes.io/part-of=accuknox\".\nLeave blank for all deployments and statefulsets.",
+	"github.com/accuknox/rinc/internal/conf.ImageTag.Namespace":                          "Namespace is the single Kubernetes namespace that the image tag reporter\nwill be limited to.\n\nDeprecated: use Namespaces.",
+	"github.com/accuknox/rinc/internal/conf.ImageTag.Namespaces":                         "Namespaces selects the Kubernetes namespaces that the image tag reporter\nwill be limited to. Leave blank for all namespaces.",
+	"github.com/accuknox/rinc/internal/conf.KubernetesClient":                            "KubernetesClient contains the configuration needed to communicate with the Kubernetes API server.",
+	"github.com/accuknox/rinc/internal/conf.KubernetesClient.InCluster":                  "InCluster, when set to true, attempts to authenticate with the API\nserver using a service account token.\n\nEither `InCluster` must be set to true or the path to a kubeconfig file\nmust be provided below.",
+	"github.com/accuknox/rinc/internal/conf.KubernetesClient.Kubeconfig":                 "Kubeconfig is the path to the `kubeconfig` file. This is useful when\nrunning the application outside the cluster.\n\nEither `InCluster` must be set to true or the path to a kubeconfig file\nmust be provided here.",
+	"github.com/accuknox/rinc/internal/conf.Log":                                         "Log contains configuration for logs.",
+	"github.com/accuknox/rinc/internal/conf.Log.Format":                                  "Format specifies the format of the logs.\nPossible values: \"text\", \"json\"\n\nDefault: \"text\"",
+	"github.com/accuknox/rinc/internal/conf.Log.Level":                                   "Level is the log level.\nPossible values: \"debug\", \"info\", \"warn\", \"error\".\n\nDefault: \"info\"",
+	"github.com/accuknox/rinc/internal/conf.LongJobs":                                    "LongJobs contains configuration related to the long-running job reporter.",
+	"github.com/accuknox/rinc/internal/conf.LongJobs.Alerts":                             "Alerts contain a message template, a severity level, and a conditional\nexpression to trigger the respective alert.",
+	"github.com/accuknox/rinc/internal/conf.LongJobs.Enable":                             "Enable specifies whether the long-running job reporter should be\nenabled.",
+	"github.com/accuknox/rinc/internal/conf.LongJobs.IncludeSuspended":                   "IncludeSuspended specifies whether long-running suspended jobs should be\nincluded in the report.",
+	"github.com/accuknox/rinc/internal/conf.LongJobs.LabelSelector":                      "LabelSelector limits the reporter to the jobs matching the label\nselector, e.g., \"app.kubernetes.io/part-of=accuknox\". Leave blank for\nall jobs.",
+	"github.com/accuknox/rinc/internal/conf.LongJobs.Namespace":                          "Namespace is the single Kubernetes namespace that the long-running job\nreporter will be limited to.\n\nDeprecated: use Namespaces.",
+	"github.com/accuknox/rinc/internal/conf.LongJobs.Namespaces":                         "Namespaces selects the Kubernetes namespaces that the long-running job\nreporter will be limited to. Leave blank for all namespaces.",
+	"github.com/accuknox/rinc/internal/conf.LongJobs.OlderThan":                          "OlderThan defines the duration threshold; jobs older than this\nvalue will be reported.",
+	"github.com/accuknox/rinc/internal/conf.MetabaseCheck":                               "Metabase contains all configuration related to metabase connectivity check.",
+	"github.com/accuknox/rinc/internal/conf.MetabaseCheck.BaseURL":                       "BaseURL is the metabase base URL.\n\nE.g., http://metabase-service.metabase.svc.cluster.local",
+	"github.com/accuknox/rinc/internal/conf.MetabaseCheck.Enable":                        "Enable enables metabase connectivity check.",
+	"github.com/accuknox/rinc/internal/conf.Mongodb":                                     "",
+	"github.com/accuknox/rinc/internal/conf.Mongodb.PasswordFile":                        "PasswordFile is the path to a file containing the password. It takes\nprecedence over Password.",
+	"github.com/accuknox/rinc/internal/conf.Mongodb.UsernameFile":                        "UsernameFile is the path to a file containing the username. It takes\nprecedence over Username.",
+	"github.com/accuknox/rinc/internal/conf.MongodbCheck":                                "Mongodb contains all configuration related to mongodb connectivity check.",
+	"github.com/accuknox/rinc/internal/conf.MongodbCheck.Enable":                         "Enable enables mongodb connectivity check.",
+	"github.com/accuknox/rinc/internal/conf.MongodbCheck.URI":                            "URI is the mongodb connection uri.\n\nE.g., mongodb://accuknox-mongodb-rs0.accuknox-mongodb.svc.cluster.local:27017",
+	"github.com/accuknox/rinc/internal/conf.NamespaceSelector":                           "NamespaceSelector selects the Kubernetes namespaces a reporter is limited to.",
+	"github.com/accuknox/rinc/internal/conf.NamespaceSelector.Exclude":                   "Exclude lists the namespaces to exclude. Entries can be glob patterns.\nExclusions take precedence over inclusions.",
+	"github.com/accuknox/rinc/internal/conf.NamespaceSelector.Include":                   "Include lists the namespaces to include. Entries can be glob patterns,\ne.g., \"accuknox-*\". Leave blank to include all namespaces.",
+	"github.com/accuknox/rinc/internal/conf.NamespaceSelector.LabelSelector":             "LabelSelector limits the namespaces to the ones matching the label\nselector, e.g., \"team=platform\".",
+	"github.com/accuknox/rinc/internal/conf.Neo4jCheck":                                  "Neo4j contains all configuration related to neo4j connectivity check.",
+	"github.com/accuknox/rinc/internal/conf.Neo4jCheck.Enable":                           "Enable enables neo4j connectivity check.",
+	"github.com/accuknox/rinc/internal/conf.Neo4jCheck.Password":                         "Password is the neo4j basic auth password.",
+	"github.com/accuknox/rinc/internal/conf.Neo4jCheck.PasswordFile":                     "PasswordFile is the path to a file containing the password. It takes\nprecedence over Password.",
+	"github.com/accuknox/rinc/internal/conf.Neo4jCheck.URI":                              "URI is the neo4j connection uri.\n\nE.g., neo4j://neo4j.accuknox-neo4j.svc.cluster.local:7687",
+	"github.com/accuknox/rinc/internal/conf.Neo4jCheck.Username":                         "Username is the neo4j basic auth username.",
+	"github.com/accuknox/rinc/internal/conf.Neo4jCheck.UsernameFile":                     "UsernameFile is the path to a file containing the username. It takes\nprecedence over Username.",
+	"github.com/accuknox/rinc/internal/conf.OIDC":                                        "OIDC contains the configuration of the OpenID Connect login method, using the authorization code flow.",
+	"github.com/accuknox/rinc/internal/conf.OIDC.AllowedGroups":                          "AllowedGroups are the groups whose members are allowed to log in. All\nusers authenticated by the provider are allowed if it is empty.",
+	"github.com/accuknox/rinc/internal/conf.OIDC.ClientID":                               "ClientID is the ID of the client registered with the provider.",
+	"github.com/accuknox/rinc/internal/conf.OIDC.ClientSecret":                           "ClientSecret is the secret of the client registered with the\nprovider.",
+	"github.com/accuknox/rinc/internal/conf.OIDC.ClientSecretFile":                       "ClientSecretFile is the path to a file containing the client secret.\nIt takes precedence over ClientSecret.",
+	"github.com/accuknox/rinc/internal/conf.OIDC.GroupsClaim":                            "GroupsClaim is the claim of the ID token containing the groups of the\nuser.\n\nDefault: \"groups\"",
+	"github.com/accuknox/rinc/internal/conf.OIDC.IssuerURL":                              "IssuerURL is the URL of the OpenID provider, which must serve its\nconfiguration at /.well-known/openid-configuration.\n\nE.g., https://keycloak.example.com/realms/accuknox",
+	"github.com/accuknox/rinc/internal/conf.OIDC.RedirectURL":                            "RedirectURL is the URL of the login callback of the web server, which\nmust be registered with the provider.\n\nE.g., https://rinc.example.com/auth/callback",
+	"github.com/accuknox/rinc/internal/conf.OIDC.Scopes":                                 "Scopes are the scopes requested besides \"openid\".\n\nDefault: [\"profile\", \"email\", \"groups\"]",
+	"github.com/accuknox/rinc/internal/conf.Output":                                      "Output contains the options to write the scraped reports, i.e., the metrics document of every reporter and the firing alerts, to files or stdout instead of MongoDB.",
+	"github.com/accuknox/rinc/internal/conf.Output.Dir":                                  "Dir is the directory to write a file per report into. The reports are\nwritten to stdout if it is empty.",
+	"github.com/accuknox/rinc/internal/conf.Output.Format":                               "Format is the format of the written reports, either \"json\" or \"yaml\".\nReports are stored in MongoDB if it is empty.",
+	"github.com/accuknox/rinc/internal/conf.PVUtilization":                               "PVUtilization contains configuration related to the PV utilization reporter.",
+	"github.com/accuknox/rinc/internal/conf.PVUtilization.Alerts":                        "Alerts contain a message template, a severity level, and a conditional\nexpression to trigger the respective alert.",
+	"github.com/accuknox/rinc/internal/conf.PVUtilization.Enable":                        "Enable specifies whether the PV utilization reporter is enabled.",
+	"github.com/accuknox/rinc/internal/conf.PVUtilization.PrometheusURL":                 "PrometheusURL is the prometheus service url. PV utilization reporter\ndepend on Prometheus to fetch the utilization.\n\nE.g., http://prometheus.monitoring.svc.cluster.local:9090",
+	"github.com/accuknox/rinc/internal/conf.PodStatus":                                   "PodStatus contains configuration related to the pod status reporter.",
+	"github.com/accuknox/rinc/internal/conf.PodStatus.Alerts":                            "Alerts contain a message template, a severity level, and a conditional\nexpression to trigger the respective alert.",
+	"github.com/accuknox/rinc/internal/conf.PodStatus.Enable":                            "Enable specifies whether the pod status reporter is enabled.",
+	"github.com/accuknox/rinc/internal/conf.PodStatus.LabelSelector":                     "LabelSelector limits the reporter to the deployments and statefulsets\nmatching the label selector, e.g., \"app.kubernetes.io/part-of=accuknox\".\nLeave blank for all deployments and statefulsets.",
+	"github.com/accuknox/rinc/internal/conf.PodStatus.Namespace":                         "Namespace is the single Kubernetes namespace that the pod status reporter\nwill be limited to.\n\nDeprecated: use Namespaces.",
+	"github.com/accuknox/rinc/internal/conf.PodStatus.Namespaces":                        "Namespaces selects the Kubernetes namespaces that the pod status reporter\nwill be limited to. Leave blank for all namespaces.",
+	"github.com/accuknox/rinc/internal/conf.PostgresCheck":                               "Postgres contains all configuration related to postgres connectivity check.",
+	"github.com/accuknox/rinc/internal/conf.PostgresCheck.Enable":                        "Enable enables postgres connectivity check.",
+	"github.com/accuknox/rinc/internal/conf.PostgresCheck.Host":                          "Host is the postgresql server host (without the port).\n\nE.g., postgres-replicas.accuknox-postgresql.svc.cluster.local",
+	"github.com/accuknox/rinc/internal/conf.PostgresCheck.Password":                      "Password is the postgres auth password.",
+	"github.com/accuknox/rinc/internal/conf.PostgresCheck.PasswordFile":                  "PasswordFile is the path to a file containing the password. It takes\nprecedence over Password.",
+	"github.com/accuknox/rinc/internal/conf.PostgresCheck.Port":                          "Port is the postgresql server port.\n\nDefault: 5432",
+	"github.com/accuknox/rinc/internal/conf.PostgresCheck.Username":                      "Username is the postgres auth username.",
+	"github.com/accuknox/rinc/internal/conf.PostgresCheck.UsernameFile":                  "UsernameFile is the path to a file containing the username. It takes\nprecedence over Username.",
+	"github.com/accuknox/rinc/internal/conf.Pushgateway":                                 "Pushgateway contains configuration related to pushing the metrics of a scrape to a Prometheus Pushgateway once it is done, e.g., at the end of a CronJob run.",
+	"github.com/accuknox/rinc/internal/conf.Pushgateway.Job":                             "Job is the job label of the pushed metrics.\n\nDefault: \"rinc\"",
+	"github.com/accuknox/rinc/internal/conf.Pushgateway.URL":                             "URL is the Pushgateway URL, e.g., http://pushgateway.monitoring:9091.\nMetrics aren't pushed if it is empty.",
+	"github.com/accuknox/rinc/internal/conf.REPL":                                        "REPL contains the options of the interactive expression prompt started with the `expr` subcommand.",
+	"github.com/accuknox/rinc/internal/conf.REPL.At":                                     "At is the isosec timestamp of the stored report to load. The latest\nreport is loaded if it is empty.",
+	"github.com/accuknox/rinc/internal/conf.REPL.Collection":                             "Collection is the collection of the stored report to load, e.g., ceph.",
+	"github.com/accuknox/rinc/internal/conf.REPL.Enable":                                 "Enable is set when the `expr` subcommand is used.",
+	"github.com/accuknox/rinc/internal/conf.REPL.File":                                   "File is the path to a JSON file to load the report from instead of\nMongoDB.",
+	"github.com/accuknox/rinc/internal/conf.RabbitMQ":                                    "RabbitMQ contains all configuration related to rabbitmq.",
+	"github.com/accuknox/rinc/internal/conf.RabbitMQ.Alerts":                             "Alerts contain a message template, a severity level, and a conditional\nexpression to trigger the respective alert.",
+	"github.com/accuknox/rinc/internal/conf.RabbitMQ.Enable":                             "Enable enables rabbitmq metrics and stats in the reports.",
+	"github.com/accuknox/rinc/internal/conf.RabbitMQ.HeadlessSvcAddr":                    "HeadlessSvcAddr is the Kubernetes headless address pointing to\nrabbitmq nodes. On a DNS lookup, this address must resolve to\nrabbitmq node ips.\nFor example: rabbitmq-nodes.default.svc.cluster.local\n\nRequired.",
+	"github.com/accuknox/rinc/internal/conf.RabbitMQ.Management":                         "Management contains configuration to access the rabbitmq management api.\n\nRequired.",
+	"github.com/accuknox/rinc/internal/conf.RabbitMQManagement":                          "RabbitMQManagement contains configuration to access the rabbitmq management api.",
+	"github.com/accuknox/rinc/internal/conf.RabbitMQManagement.Password":                 "Password is the basic auth password credential for the management api.\n\nRequired.",
+	"github.com/accuknox/rinc/internal/conf.RabbitMQManagement.PasswordFile":             "PasswordFile is the path to a file containing the password. It takes\nprecedence over Password.",
+	"github.com/accuknox/rinc/internal/conf.RabbitMQManagement.URL":                      "URL is the rabbitmq management url.\nFor example: http://rabbitmq.default.svc.cluster.local:15672\n\nRequired.",
+	"github.com/accuknox/rinc/internal/conf.RabbitMQManagement.Username":                 "Username is the basic auth username credential for the management api.\n\nRequired.",
+	"github.com/accuknox/rinc/internal/conf.RabbitMQManagement.UsernameFile":             "UsernameFile is the path to a file containing the username. It takes\nprecedence over Username.",
+	"github.com/accuknox/rinc/internal/conf.Readiness":                                   "Readiness contains configuration related to the readiness endpoint of the web server, which fails if MongoDB is unreachable or the latest scrape is stale.",
+	"github.com/accuknox/rinc/internal/conf.Readiness.MaxScrapeAge":                      "MaxScrapeAge is the age after which the latest scrape is stale, e.g.,\nbecause the scraper CronJob is broken. A value of 0 disables the\ncheck, which otherwise also fails until the first scrape.",
+	"github.com/accuknox/rinc/internal/conf.RedisCheck":                                  "Redis contains all configuration related to redis connectivity check.",
+	"github.com/accuknox/rinc/internal/conf.RedisCheck.Addr":                             "Addr is the redis/keydb address.\n\nE.g., keydb-service.keydb.svc.cluster.local:6379",
+	"github.com/accuknox/rinc/internal/conf.RedisCheck.Enable":                           "Enable enables redis/keydb connectivity check.",
+	"github.com/accuknox/rinc/internal/conf.ReloadStatus":                                "ReloadStatus describes the configuration reloads of a Reloader.",
+	"github.com/accuknox/rinc/internal/conf.ReloadStatus.Failures":                       "Failures is the number of failed reloads.",
+	"github.com/accuknox/rinc/internal/conf.ReloadStatus.LastError":                      "LastError is the error of the last failed reload. It is cleared on the\nnext successful reload.",
+	"github.com/accuknox/rinc/internal/conf.ReloadStatus.LastErrorTime":                  "LastErrorTime is the time of the last failed reload.",
+	"github.com/accuknox/rinc/internal/conf.ReloadStatus.LastReload":                     "LastReload is the time of the last successful reload.",
+	"github.com/accuknox/rinc/internal/conf.ReloadStatus.Reloads":                        "Reloads is the number of successful reloads.",
+	"github.com/accuknox/rinc/internal/conf.Reloader":                                    "Reloader keeps a configuration up to date with the configuration files it was loaded from.",
+	"github.com/accuknox/rinc/internal/conf.ResourceUtilization":                         "ResourceUtilization contains configuration related to the resource utilization reporter.",
+	"github.com/accuknox/rinc/internal/conf.ResourceUtilization.Alerts":                  "Alerts contain a message template, a severity level, and a conditional\nexpression to trigger the respective alert.",
+	"github.com/accuknox/rinc/internal/conf.ResourceUtilization.Enable":                  "Enable specifies whether the resource utilization reporter is enabled.",
+	"github.com/accuknox/rinc/internal/conf.ResourceUtilization.Namespace":               "Namespace is the single Kubernetes namespace that the resource utilization\nreporter will be limited to.\n\nDeprecated: use Namespaces.",
+	"github.com/accuknox/rinc/internal/conf.ResourceUtilization.Namespaces":              "Namespaces selects the Kubernetes namespaces that the resource utilization\nreporter will be limited to. Leave blank for all namespaces.",
+	"github.com/accuknox/rinc/internal/conf.Session":                                     "Session contains configuration related to the session cookies.",
+	"github.com/accuknox/rinc/internal/conf.Session.Insecure":                            "Insecure allows the session cookies to be sent over plain HTTP, e.g.,\nwhen the web server isn't served over HTTPS.",
+	"github.com/accuknox/rinc/internal/conf.Session.MaxAge":                              "MaxAge is the duration after which users must log in again.\n\nDefault: 12h",
+	"github.com/accuknox/rinc/internal/conf.Session.Secret":                              "Secret is the key the session cookies are signed with. It must be at\nleast 32 bytes long, and shared by all replicas of the web server.",
+	"github.com/accuknox/rinc/internal/conf.Session.SecretFile":                          "SecretFile is the path to a file containing the secret. It takes\nprecedence over Secret.",
+	"github.com/accuknox/rinc/internal/conf.Severity":                                    "Severity defines different levels of alert severity.",
+	"github.com/accuknox/rinc/internal/conf.Snapshot":                                    "Snapshot contains the options to record the inputs of the reporters into a bundle, or to replay a bundle through the reporters.",
+	"github.com/accuknox/rinc/internal/conf.Snapshot.Record":                             "Record is the path of the bundle to record the inputs of the reporters\ninto.",
+	"github.com/accuknox/rinc/internal/conf.Snapshot.Replay":                             "Replay is the path of the bundle to replay through the reporters\ninstead of fetching their inputs from the live services.",
+	"github.com/accuknox/rinc/internal/conf.StringExpr":                                  "",
+	"github.com/accuknox/rinc/internal/conf.Telemetry":                                   "Telemetry contains configuration related to the instrumentation of RINC itself.",
+	"github.com/accuknox/rinc/internal/conf.Telemetry.Pushgateway":                       "Pushgateway contains configuration related to pushing the metrics of\na scrape to a Prometheus Pushgateway.",
+	"github.com/accuknox/rinc/internal/conf.Telemetry.Tracing":                           "Tracing contains configuration related to exporting traces of scrapes\nand web requests to an OTLP collector.",
+	"github.com/accuknox/rinc/internal/conf.Tracing":                                     "Tracing contains configuration related to exporting traces over OTLP/HTTP, e.g., to an OpenTelemetry collector.",
+	"github.com/accuknox/rinc/internal/conf.Tracing.Endpoint":                            "Endpoint is the URL of the OTLP/HTTP traces endpoint, e.g.,\nhttp://otel-collector.monitoring:4318/v1/traces. Traces aren't exported\nif it is empty.",
+	"github.com/accuknox/rinc/internal/conf.Tracing.Headers":                             "Headers are sent with every export request, e.g., for authentication.",
+	"github.com/accuknox/rinc/internal/conf.Tracing.SampleRatio":                         "SampleRatio is the ratio of traces to sample, between 0 and 1. Spans\nof web requests follow the sampling decision of the caller, if any.\n\nDefault: 1",
+	"github.com/accuknox/rinc/internal/conf.Tracing.ServiceName":                         "ServiceName is the service.name resource attribute of the exported\nspans.\n\nDefault: \"rinc\"",
+	"github.com/accuknox/rinc/internal/conf.Vault":                                       "Vault contains the configuration needed to resolve configuration values referencing secrets stored in vault.",
+	"github.com/accuknox/rinc/internal/conf.Vault.Addr":                                  "Addr is the vault address. Defaults to the VAULT_ADDR environment\nvariable.\n\nE.g., http://accuknox-vault.accuknox-vault.svc.cluster.local:8200",
+	"github.com/accuknox/rinc/internal/conf.Vault.Auth":                                  "Auth contains the configuration to authenticate with vault.",
+	"github.com/accuknox/rinc/internal/conf.VaultAuth":                                   "VaultAuth contains the configuration to authenticate with vault.",
+	"github.com/accuknox/rinc/internal/conf.VaultAuth.Kubernetes":                        "Kubernetes contains the configuration of the kubernetes auth method.",
+	"github.com/accuknox/rinc/internal/conf.VaultAuth.Method":                            "Method is the authentication method.\nPossible values: \"kubernetes\", \"token\".\n\nDefault: \"kubernetes\"",
+	"github.com/accuknox/rinc/internal/conf.VaultAuth.Token":                             "Token is the vault token used by the token auth method. Defaults to\nthe VAULT_TOKEN environment variable.",
+	"github.com/accuknox/rinc/internal/conf.VaultAuth.TokenFile":                         "TokenFile is the path to a file containing the vault token. It takes\nprecedence over Token.",
+	"github.com/accuknox/rinc/internal/conf.VaultCheck":                                  "VaultCheck contains all configuration related to vault connectivity check.",
+	"github.com/accuknox/rinc/internal/conf.VaultCheck.Addr":                             "Addr is the vault address.\n\nE.g., http://accuknox-vault.accuknox-vault.svc.cluster.local:8200",
+	"github.com/accuknox/rinc/internal/conf.VaultCheck.Enable":                           "Enable enables vault connectivity check.",
+	"github.com/accuknox/rinc/internal/conf.VaultKubernetesAuth":                         "VaultKubernetesAuth contains the configuration of the vault kubernetes auth method.",
+	"github.com/accuknox/rinc/internal/conf.VaultKubernetesAuth.MountPath":               "MountPath is the path the kubernetes auth method is mounted at.\n\nDefault: \"kubernetes\"",
+	"github.com/accuknox/rinc/internal/conf.VaultKubernetesAuth.Role":                    "Role is the vault role to log in with.",
+	"github.com/accuknox/rinc/internal/conf.VaultKubernetesAuth.ServiceAccountTokenFile": "ServiceAccountTokenFile is the path to the service account token used\nto log in.\n\nDefault: \"/var/run/secrets/kubernetes.io/serviceaccount/token\"",
+}
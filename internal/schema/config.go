@@ -0,0 +1,109 @@
+package schema
+
+//go:generate go run ./gen
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/accuknox/rinc/internal/conf"
+
+	"github.com/invopop/jsonschema"
+)
+
+// TargetConfig is the schema generation target for the configuration file.
+const TargetConfig = "config"
+
+// durationPattern matches the durations accepted by time.ParseDuration.
+const durationPattern = `^(0|(\d+(\.\d+)?(ns|us|µs|ms|s|m|h))+)$`
+
+// enums contains the possible values of configuration fields by key.
+var enums = map[string][]any{
+	"log.level":         {"debug", "info", "warn", "error"},
+	"log.format":        {"text", "json"},
+	"vault.auth.method": {"kubernetes", "token"},
+}
+
+// configSchema generates the json schema of the configuration file.
+func configSchema() (*jsonschema.Schema, error) {
+	r := &jsonschema.Reflector{
+		FieldNameTag:   "koanf",
+		DoNotReference: true,
+		// all fields are optional, either defaulted or validated later
+		RequiredFromJSONSchemaTags: true,
+		CommentMap:                 configComments,
+		Mapper:                     configMapper,
+	}
+	schema := r.Reflect(conf.C{})
+
+	for key, enum := range enums {
+		prop, err := property(schema, key)
+		if err != nil {
+			return nil, err
+		}
+		prop.Enum = enum
+	}
+	for key, value := range conf.Defaults() {
+		prop, err := property(schema, key)
+		if err != nil {
+			return nil, err
+		}
+		if d, ok := value.(time.Duration); ok {
+			value = d.String()
+		}
+		prop.Default = value
+	}
+	return schema, nil
+}
+
+// configMapper maps configuration types that are unmarshalled from strings to
+// string schemas.
+func configMapper(t reflect.Type) *jsonschema.Schema {
+	switch t {
+	case reflect.TypeOf(time.Duration(0)):
+		return &jsonschema.Schema{
+			Type:     "string",
+			Pattern:  durationPattern,
+			Examples: []any{"30s", "12h", "1h30m"},
+		}
+	case reflect.TypeOf(conf.Severity("")):
+		return &jsonschema.Schema{
+			Type: "string",
+			Enum: []any{
+				conf.SeverityInfo,
+				conf.SeverityWarning,
+				conf.SeverityCritical,
+			},
+		}
+	case reflect.TypeOf(conf.Expr{}):
+		return &jsonschema.Schema{
+			Type:      "string",
+			MinLength: ptr(uint64(1)),
+		}
+	case reflect.TypeOf(conf.StringExpr{}):
+		return &jsonschema.Schema{Type: "string"}
+	}
+	return nil
+}
+
+// property returns the schema of the property at the dotted key.
+func property(schema *jsonschema.Schema, key string) (*jsonschema.Schema, error) {
+	s := schema
+	for _, name := range strings.Split(key, ".") {
+		if s.Properties == nil {
+			return nil, fmt.Errorf("property %q not found", key)
+		}
+		prop, ok := s.Properties.Get(name)
+		if !ok {
+			return nil, fmt.Errorf("property %q not found", key)
+		}
+		s = prop
+	}
+	return s, nil
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
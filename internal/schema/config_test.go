@@ -0,0 +1,71 @@
+package schema
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/invopop/jsonschema"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConfigCommentsUpToDate(t *testing.T) {
+	a := assert.New(t)
+	comments := make(map[string]string)
+	err := jsonschema.ExtractGoComments(
+		"github.com/accuknox/rinc/internal/schema",
+		"../conf",
+		comments,
+	)
+	if a.NoError(err) {
+		a.Equal(comments, configComments, "run `go generate ./internal/schema`")
+	}
+}
+
+func TestGenerateConfig(t *testing.T) {
+	a := assert.New(t)
+	out, err := Generate(TargetConfig)
+	if !a.NoError(err) {
+		return
+	}
+	var schema jsonschema.Schema
+	if !a.NoError(json.Unmarshal(out, &schema)) {
+		return
+	}
+
+	inputs := []struct {
+		key         string
+		typ         string
+		def         any
+		enum        []any
+		pattern     bool
+		description string
+	}{
+		{key: "log.level", typ: "string", def: "info", enum: []any{"debug", "info", "warn", "error"}},
+		{key: "longRunningJobs.olderThan", typ: "string", def: "12h0m0s", pattern: true},
+		{key: "connectivity.postgres.port", typ: "integer", def: float64(5432)},
+		{key: "ceph.dashboardAPI.url", typ: "string", description: "URL is the ceph dashboard API url."},
+		{key: "rabbitmq.management.passwordFile", typ: "string"},
+	}
+	for _, i := range inputs {
+		prop, err := property(&schema, i.key)
+		if !a.NoError(err, "key=%s", i.key) {
+			continue
+		}
+		a.Equal(i.typ, prop.Type, "key=%s", i.key)
+		a.Equal(i.def, prop.Default, "key=%s", i.key)
+		a.Equal(i.enum, prop.Enum, "key=%s", i.key)
+		a.Equal(i.pattern, prop.Pattern != "", "key=%s", i.key)
+		a.Contains(prop.Description, i.description, "key=%s", i.key)
+	}
+
+	alerts, err := property(&schema, "ceph.alerts")
+	if a.NoError(err) && a.NotNil(alerts.Items) {
+		severity, ok := alerts.Items.Properties.Get("severity")
+		if a.True(ok) {
+			a.Equal([]any{"info", "warning", "critical"}, severity.Enum)
+		}
+	}
+
+	_, err = property(&schema, "runAsScraper")
+	a.Error(err)
+}
@@ -0,0 +1,55 @@
+// Command gen extracts the doc comments of the configuration types into a Go
+// source file, so that the configuration schema can include them without
+// access to the source code at runtime.
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"log"
+	"os"
+	"sort"
+
+	"github.com/invopop/jsonschema"
+)
+
+// base is the import path of the package the generator is run in. Paths are
+// resolved relative to it.
+const base = "github.com/accuknox/rinc/internal/schema"
+
+func main() {
+	comments := make(map[string]string)
+	err := jsonschema.ExtractGoComments(base, "../conf", comments)
+	if err != nil {
+		log.Fatalf("extracting comments: %s", err.Error())
+	}
+
+	keys := make([]string, 0, len(comments))
+	for k := range comments {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var buf bytes.Buffer
+	fmt.Fprintln(&buf, "// Code generated by gen/main.go; DO NOT EDIT.")
+	fmt.Fprintln(&buf)
+	fmt.Fprintln(&buf, "package schema")
+	fmt.Fprintln(&buf)
+	fmt.Fprintln(&buf, "// configComments contains the doc comments of the configuration types")
+	fmt.Fprintln(&buf, "// and their fields by fully qualified name.")
+	fmt.Fprintln(&buf, "var configComments = map[string]string{")
+	for _, k := range keys {
+		fmt.Fprintf(&buf, "\t%q: %q,\n", k, comments[k])
+	}
+	fmt.Fprintln(&buf, "}")
+
+	out, err := format.Source(buf.Bytes())
+	if err != nil {
+		log.Fatalf("formatting source: %s", err.Error())
+	}
+	err = os.WriteFile("comments.go", out, 0o644)
+	if err != nil {
+		log.Fatalf("writing comments.go: %s", err.Error())
+	}
+}
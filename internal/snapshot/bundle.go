@@ -0,0 +1,171 @@
+package snapshot
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"strings"
+	"time"
+)
+
+// A bundle is a gzipped tarball with the following files:
+//
+//	manifest.json          the manifest
+//	lookups.json           the recorded IP address lookups
+//	results.json           the recorded results of checks
+//	exchanges/000001.json  the first recorded HTTP request and response
+//	exchanges/000001.body  the body of its response
+//	...
+const (
+	manifestFile  = "manifest.json"
+	lookupsFile   = "lookups.json"
+	resultsFile   = "results.json"
+	exchangesDir  = "exchanges"
+	exchangeExt   = ".json"
+	exchangeBody  = ".body"
+	exchangeWidth = 6
+)
+
+func writeBundle(name string, m manifest, exchanges []exchange, lookups []lookup, results []result) (err error) {
+	f, err := os.Create(name)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		err = errors.Join(err, f.Close())
+	}()
+	gw := gzip.NewWriter(f)
+	tw := tar.NewWriter(gw)
+
+	modTime := time.Now()
+	write := func(name string, data []byte) error {
+		err := tw.WriteHeader(&tar.Header{
+			Name:    name,
+			Mode:    0o600,
+			Size:    int64(len(data)),
+			ModTime: modTime,
+		})
+		if err != nil {
+			return fmt.Errorf("writing header of %q: %w", name, err)
+		}
+		_, err = tw.Write(data)
+		if err != nil {
+			return fmt.Errorf("writing %q: %w", name, err)
+		}
+		return nil
+	}
+	writeJSON := func(name string, v any) error {
+		data, err := json.MarshalIndent(v, "", "  ")
+		if err != nil {
+			return fmt.Errorf("marshaling %q: %w", name, err)
+		}
+		return write(name, data)
+	}
+
+	err = writeJSON(manifestFile, m)
+	if err != nil {
+		return err
+	}
+	err = writeJSON(lookupsFile, lookups)
+	if err != nil {
+		return err
+	}
+	err = writeJSON(resultsFile, results)
+	if err != nil {
+		return err
+	}
+	for idx, ex := range exchanges {
+		base := path.Join(exchangesDir, fmt.Sprintf("%0*d", exchangeWidth, idx+1))
+		err = writeJSON(base+exchangeExt, ex)
+		if err != nil {
+			return err
+		}
+		if ex.Error != "" {
+			continue
+		}
+		err = write(base+exchangeBody, ex.body)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = tw.Close()
+	if err != nil {
+		return fmt.Errorf("closing tar writer: %w", err)
+	}
+	err = gw.Close()
+	if err != nil {
+		return fmt.Errorf("closing gzip writer: %w", err)
+	}
+	return nil
+}
+
+func readBundle(name string) (manifest, []exchange, []lookup, []result, error) {
+	var (
+		m         manifest
+		lookups   []lookup
+		results   []result
+		exchanges []exchange
+		// bodies are keyed by the path of their exchange without the
+		// extension.
+		bodies  = make(map[string][]byte)
+		indexes = make(map[string]int)
+	)
+	f, err := os.Open(name)
+	if err != nil {
+		return m, nil, nil, nil, err
+	}
+	defer f.Close()
+	gr, err := gzip.NewReader(f)
+	if err != nil {
+		return m, nil, nil, nil, fmt.Errorf("reading gzip header: %w", err)
+	}
+	tr := tar.NewReader(gr)
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return m, nil, nil, nil, fmt.Errorf("reading tar header: %w", err)
+		}
+		data, err := io.ReadAll(tr)
+		if err != nil {
+			return m, nil, nil, nil, fmt.Errorf("reading %q: %w", hdr.Name, err)
+		}
+		switch {
+		case hdr.Name == manifestFile:
+			err = json.Unmarshal(data, &m)
+		case hdr.Name == lookupsFile:
+			err = json.Unmarshal(data, &lookups)
+		case hdr.Name == resultsFile:
+			err = json.Unmarshal(data, &results)
+		case strings.HasSuffix(hdr.Name, exchangeExt):
+			var ex exchange
+			err = json.Unmarshal(data, &ex)
+			indexes[strings.TrimSuffix(hdr.Name, exchangeExt)] = len(exchanges)
+			exchanges = append(exchanges, ex)
+		case strings.HasSuffix(hdr.Name, exchangeBody):
+			bodies[strings.TrimSuffix(hdr.Name, exchangeBody)] = data
+		}
+		if err != nil {
+			return m, nil, nil, nil, fmt.Errorf("unmarshaling %q: %w", hdr.Name, err)
+		}
+	}
+	if m.Version != version {
+		return m, nil, nil, nil, fmt.Errorf("unsupported bundle version %d, want %d", m.Version, version)
+	}
+	for base, body := range bodies {
+		idx, ok := indexes[base]
+		if !ok {
+			return m, nil, nil, nil, fmt.Errorf("%q has no exchange", base+exchangeBody)
+		}
+		exchanges[idx].body = body
+	}
+	return m, exchanges, lookups, results, nil
+}
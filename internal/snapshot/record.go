@@ -0,0 +1,138 @@
+package snapshot
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"k8s.io/client-go/rest"
+)
+
+// Recorder is a Network that sends requests and lookups to the live network,
+// and records their responses.
+type Recorder struct {
+	mu        sync.Mutex
+	kubeHost  string
+	exchanges []exchange
+	lookups   []lookup
+	results   []result
+}
+
+// NewRecorder creates an empty recorder.
+func NewRecorder() *Recorder {
+	return new(Recorder)
+}
+
+// RESTConfig returns a copy of the Kubernetes client configuration c whose
+// requests are recorded.
+func (r *Recorder) RESTConfig(c *rest.Config) *rest.Config {
+	c = rest.CopyConfig(c)
+	c.Wrap(r.Transport)
+	r.mu.Lock()
+	r.kubeHost = c.Host
+	r.mu.Unlock()
+	return c
+}
+
+// Transport satisfies the Network interface by wrapping base in a transport
+// that records all requests except Kubernetes watch requests.
+func (r *Recorder) Transport(base http.RoundTripper) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return recordingTransport{recorder: r, base: base}
+}
+
+// LookupIP satisfies the Network interface by looking up host and recording
+// the result.
+func (r *Recorder) LookupIP(ctx context.Context, host string) ([]net.IP, error) {
+	ips, err := Live.LookupIP(ctx, host)
+	l := lookup{Host: host, IPs: ips}
+	if err != nil {
+		l.Error = err.Error()
+	}
+	r.mu.Lock()
+	r.lookups = append(r.lookups, l)
+	r.mu.Unlock()
+	return ips, err
+}
+
+// Check satisfies the Network interface by running check and recording the
+// value it stores in v.
+func (r *Recorder) Check(name string, v any, check func() error) error {
+	err := check()
+	res := result{Name: name}
+	if err != nil {
+		res.Error = err.Error()
+	}
+	value, merr := json.Marshal(v)
+	if merr != nil {
+		return fmt.Errorf("marshaling result of check %s: %w", name, merr)
+	}
+	res.Value = value
+	r.mu.Lock()
+	r.results = append(r.results, res)
+	r.mu.Unlock()
+	return err
+}
+
+// Save writes the recorded responses into a bundle at path. The bundle is a
+// gzipped tarball, see Open.
+func (r *Recorder) Save(path string, now time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	m := manifest{
+		Version:   version,
+		Timestamp: now,
+		KubeHost:  r.kubeHost,
+	}
+	err := writeBundle(path, m, r.exchanges, r.lookups, r.results)
+	if err != nil {
+		return fmt.Errorf("writing bundle %q: %w", path, err)
+	}
+	return nil
+}
+
+type recordingTransport struct {
+	recorder *Recorder
+	base     http.RoundTripper
+}
+
+func (t recordingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if isWatch(req) {
+		return t.base.RoundTrip(req)
+	}
+	ex, req, err := readRequest(req)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := t.base.RoundTrip(req)
+	if err != nil {
+		ex.Error = err.Error()
+		t.recorder.add(ex)
+		return nil, err
+	}
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, fmt.Errorf("reading response body: %w", err)
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	ex.Status = resp.StatusCode
+	ex.ContentType = resp.Header.Get("Content-Type")
+	ex.body = withoutSecrets(ex.ContentType, body)
+	t.recorder.add(ex)
+	return resp, nil
+}
+
+func (r *Recorder) add(ex exchange) {
+	r.mu.Lock()
+	r.exchanges = append(r.exchanges, ex)
+	r.mu.Unlock()
+}
@@ -0,0 +1,180 @@
+package snapshot
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"k8s.io/client-go/rest"
+)
+
+// Bundle is a Network that replays the responses recorded in a bundle.
+type Bundle struct {
+	manifest manifest
+
+	mu        sync.Mutex
+	exchanges map[key][]exchange
+	lookups   map[string][]lookup
+	results   map[string][]result
+}
+
+// Open reads the bundle at path, written by Recorder.Save.
+func Open(path string) (*Bundle, error) {
+	m, exchanges, lookups, results, err := readBundle(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading bundle %q: %w", path, err)
+	}
+	b := &Bundle{
+		manifest:  m,
+		exchanges: make(map[key][]exchange),
+		lookups:   make(map[string][]lookup),
+		results:   make(map[string][]result),
+	}
+	for _, ex := range exchanges {
+		b.exchanges[ex.key()] = append(b.exchanges[ex.key()], ex)
+	}
+	for _, l := range lookups {
+		b.lookups[l.Host] = append(b.lookups[l.Host], l)
+	}
+	for _, res := range results {
+		b.results[res.Name] = append(b.results[res.Name], res)
+	}
+	return b, nil
+}
+
+// Timestamp returns the time of the recorded reports.
+func (b *Bundle) Timestamp() time.Time {
+	return b.manifest.Timestamp
+}
+
+// RESTConfig returns a Kubernetes client configuration whose requests are
+// replayed from the bundle.
+func (b *Bundle) RESTConfig() *rest.Config {
+	c := &rest.Config{Host: b.manifest.KubeHost}
+	c.Wrap(b.Transport)
+	return c
+}
+
+// Transport satisfies the Network interface by returning a transport that
+// replays the recorded responses. base is never used.
+func (b *Bundle) Transport(http.RoundTripper) http.RoundTripper {
+	return replayTransport{bundle: b}
+}
+
+// LookupIP satisfies the Network interface by replaying the recorded lookups
+// of host.
+func (b *Bundle) LookupIP(_ context.Context, host string) ([]net.IP, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	l, ok := next(b.lookups, host)
+	if !ok {
+		return nil, fmt.Errorf("lookup %s: %w", host, ErrNotRecorded)
+	}
+	if l.Error != "" {
+		return nil, errors.New(l.Error)
+	}
+	return l.IPs, nil
+}
+
+// Check satisfies the Network interface by storing the recorded result of the
+// check named name in v. check is never run.
+func (b *Bundle) Check(name string, v any, _ func() error) error {
+	b.mu.Lock()
+	res, ok := next(b.results, name)
+	b.mu.Unlock()
+	if !ok {
+		return fmt.Errorf("check %s: %w", name, ErrNotRecorded)
+	}
+	if len(res.Value) > 0 {
+		err := json.Unmarshal(res.Value, v)
+		if err != nil {
+			return fmt.Errorf("unmarshaling result of check %s: %w", name, err)
+		}
+	}
+	if res.Error != "" {
+		return errors.New(res.Error)
+	}
+	return nil
+}
+
+type replayTransport struct {
+	bundle *Bundle
+}
+
+func (t replayTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if isWatch(req) {
+		return response(req, http.StatusOK, "application/json",
+			blockingBody{ctx: req.Context()}, -1), nil
+	}
+	recorded, _, err := readRequest(req)
+	if err != nil {
+		return nil, err
+	}
+	t.bundle.mu.Lock()
+	ex, ok := next(t.bundle.exchanges, recorded.key())
+	t.bundle.mu.Unlock()
+	if !ok {
+		return nil, fmt.Errorf("%s %s: %w", req.Method, req.URL, ErrNotRecorded)
+	}
+	if ex.Error != "" {
+		return nil, errors.New(ex.Error)
+	}
+	return response(req, ex.Status, ex.ContentType,
+		io.NopCloser(bytes.NewReader(ex.body)), int64(len(ex.body))), nil
+}
+
+// next returns the next recorded value of k, keeping the last one to replay
+// it again.
+func next[K comparable, V any](recorded map[K][]V, k K) (V, bool) {
+	values := recorded[k]
+	if len(values) == 0 {
+		var zero V
+		return zero, false
+	}
+	v := values[0]
+	if len(values) > 1 {
+		recorded[k] = values[1:]
+	}
+	return v, true
+}
+
+func response(req *http.Request, status int, contentType string, body io.ReadCloser, length int64) *http.Response {
+	header := make(http.Header)
+	if contentType != "" {
+		header.Set("Content-Type", contentType)
+	}
+	return &http.Response{
+		Status:        strconv.Itoa(status) + " " + http.StatusText(status),
+		StatusCode:    status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          body,
+		ContentLength: length,
+		Request:       req,
+	}
+}
+
+// blockingBody is the body of a replayed watch request, which has no events
+// and blocks until the request is canceled.
+type blockingBody struct {
+	ctx context.Context
+}
+
+func (b blockingBody) Read([]byte) (int, error) {
+	<-b.ctx.Done()
+	return 0, b.ctx.Err()
+}
+
+func (b blockingBody) Close() error {
+	return nil
+}
@@ -0,0 +1,298 @@
+// Package snapshot records the raw inputs fetched by the reporters, i.e., the
+// responses of the Kubernetes, metrics, RabbitMQ, Ceph and Prometheus APIs,
+// into a bundle, and replays a bundle through the reporters without any live
+// services.
+//
+// Inputs are recorded at the HTTP transport, and looked up by request method,
+// URL and body on replay, leaving credentials out of the body. Secrets in
+// JSON responses, e.g., login tokens, are redacted. Requests with the same
+// key are replayed in the order they were recorded, repeating the last
+// response once they are exhausted.
+// Kubernetes watch requests are not recorded; on replay, they stay open
+// without any events until the request is canceled.
+//
+// Checks that reach services without HTTP, e.g., the connectivity checks
+// using database drivers, are recorded by their result instead, see
+// Network.Check.
+package snapshot
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// ErrNotRecorded is returned on replay for requests and lookups that are not
+// in the bundle, e.g., when a reporter that wasn't enabled while recording is
+// enabled.
+var ErrNotRecorded = errors.New("not recorded")
+
+// Network is how reporters reach the services they scrape.
+type Network interface {
+	// Transport wraps the base transport of an HTTP client.
+	Transport(base http.RoundTripper) http.RoundTripper
+	// LookupIP looks up the IPv4 and IPv6 addresses of host.
+	LookupIP(ctx context.Context, host string) ([]net.IP, error)
+	// Check runs check, which stores its result in v, a pointer to a value
+	// that can be marshaled to JSON. The result is replayed without running
+	// check.
+	Check(name string, v any, check func() error) error
+}
+
+// Live is the live network, where requests and lookups are sent as they are.
+var Live Network = live{}
+
+type live struct{}
+
+func (live) Transport(base http.RoundTripper) http.RoundTripper {
+	return base
+}
+
+func (live) LookupIP(ctx context.Context, host string) ([]net.IP, error) {
+	return net.DefaultResolver.LookupIP(ctx, "ip", host)
+}
+
+func (live) Check(_ string, _ any, check func() error) error {
+	return check()
+}
+
+// version is the version of the bundle format.
+//
+// Version 2 leaves credentials out of the hashes of request bodies.
+const version = 2
+
+// manifest describes a bundle.
+type manifest struct {
+	Version int `json:"version"`
+	// Timestamp is the time of the recorded reports.
+	Timestamp time.Time `json:"timestamp"`
+	// KubeHost is the address of the recorded Kubernetes API server.
+	KubeHost string `json:"kubeHost"`
+}
+
+// exchange is a recorded HTTP request and its response. Request headers and
+// bodies are not recorded since they may contain credentials; requests are
+// matched by the hash of their body without credentials instead, see
+// bodyHash.
+type exchange struct {
+	Method      string `json:"method"`
+	URL         string `json:"url"`
+	BodySHA256  string `json:"bodySHA256,omitempty"`
+	Status      int    `json:"status,omitempty"`
+	ContentType string `json:"contentType,omitempty"`
+	// Error is the error returned instead of a response, e.g., when the
+	// service was unreachable.
+	Error string `json:"error,omitempty"`
+	body  []byte
+}
+
+func (e exchange) key() key {
+	return key{method: e.Method, url: e.URL, bodySHA256: e.BodySHA256}
+}
+
+type key struct {
+	method     string
+	url        string
+	bodySHA256 string
+}
+
+// lookup is a recorded IP address lookup.
+type lookup struct {
+	Host  string   `json:"host"`
+	IPs   []net.IP `json:"ips,omitempty"`
+	Error string   `json:"error,omitempty"`
+}
+
+// result is the recorded result of a check.
+type result struct {
+	Name  string          `json:"name"`
+	Value json.RawMessage `json:"value,omitempty"`
+	Error string          `json:"error,omitempty"`
+}
+
+// readRequest returns the exchange of req without a response, and a copy of
+// req with its body restored.
+func readRequest(req *http.Request) (exchange, *http.Request, error) {
+	ex := exchange{
+		Method: req.Method,
+		URL:    req.URL.String(),
+	}
+	if req.Body == nil || req.Body == http.NoBody {
+		return ex, req, nil
+	}
+	body, err := io.ReadAll(req.Body)
+	req.Body.Close()
+	if err != nil {
+		return ex, nil, fmt.Errorf("reading request body: %w", err)
+	}
+	ex.BodySHA256 = bodyHash(req.Header.Get("Content-Type"), body)
+	req = req.Clone(req.Context())
+	req.Body = io.NopCloser(bytes.NewReader(body))
+	return ex, req, nil
+}
+
+// bodyHash returns the hash of a request body without its credentials, so
+// that a bundle can be replayed with other credentials, e.g., the Ceph
+// dashboard login, and never contains a hash of them. The hash is empty if
+// the body contains nothing but credentials, matching the request by method
+// and URL only.
+func bodyHash(contentType string, body []byte) string {
+	body = withoutCredentials(contentType, body)
+	if len(body) == 0 {
+		return ""
+	}
+	sum := sha256.Sum256(body)
+	return hex.EncodeToString(sum[:])
+}
+
+// withoutCredentials returns a JSON object or form body without its
+// credential fields, and other bodies as they are.
+func withoutCredentials(contentType string, body []byte) []byte {
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	switch mediaType {
+	case "application/json":
+		var obj map[string]json.RawMessage
+		if err := json.Unmarshal(body, &obj); err != nil {
+			return body
+		}
+		for field := range obj {
+			if isCredential(field) {
+				delete(obj, field)
+			}
+		}
+		if len(obj) == 0 {
+			return nil
+		}
+		// the keys of a map are marshalled in order
+		redacted, err := json.Marshal(obj)
+		if err != nil {
+			return body
+		}
+		return redacted
+	case "application/x-www-form-urlencoded":
+		form, err := url.ParseQuery(string(body))
+		if err != nil {
+			return body
+		}
+		for field := range form {
+			if isCredential(field) {
+				delete(form, field)
+			}
+		}
+		// encoded in order of the keys
+		return []byte(form.Encode())
+	}
+	return body
+}
+
+// isCredential reports whether a body field holds credentials, e.g.,
+// "username", "password" or "client_secret".
+func isCredential(field string) bool {
+	field = strings.ToLower(field)
+	return field == "user" || field == "username" || isSecret(field)
+}
+
+// isSecret reports whether a body field holds a secret, e.g., "password" or
+// "access_token", unlike the usernames that are credentials too.
+func isSecret(field string) bool {
+	field = strings.ToLower(field)
+	for _, s := range []string{"password", "passwd", "secret", "token", "apikey", "api_key"} {
+		if strings.Contains(field, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// redacted replaces the secrets in recorded response bodies.
+const redacted = "REDACTED"
+
+// withoutSecrets returns a JSON response body with the string values of its
+// secret fields replaced at any depth, so that a bundle doesn't contain the
+// tokens of logins, e.g., of the Ceph dashboard. Other bodies, and bodies
+// without secrets, are returned as they are.
+func withoutSecrets(contentType string, body []byte) []byte {
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	if mediaType != "application/json" && !strings.HasSuffix(mediaType, "+json") {
+		return body
+	}
+	dec := json.NewDecoder(bytes.NewReader(body))
+	dec.UseNumber()
+	var v any
+	if err := dec.Decode(&v); err != nil || !redactSecrets(v) {
+		return body
+	}
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(v); err != nil {
+		return body
+	}
+	return bytes.TrimSuffix(buf.Bytes(), []byte("\n"))
+}
+
+// redactSecrets replaces the string values of the secret fields in v, a
+// decoded JSON value, and reports whether there were any.
+func redactSecrets(v any) bool {
+	var found bool
+	switch v := v.(type) {
+	case map[string]any:
+		for field, value := range v {
+			if s, ok := value.(string); ok && isSecret(field) {
+				v[field] = redactedSecret(s)
+				found = true
+				continue
+			}
+			found = redactSecrets(value) || found
+		}
+	case []any:
+		for _, item := range v {
+			found = redactSecrets(item) || found
+		}
+	}
+	return found
+}
+
+// redactedSecret returns the placeholder of secret. JWTs are replaced with
+// unsigned ones that keep nothing but their expiry, which reporters check
+// before reusing them, e.g., the Ceph reporter.
+func redactedSecret(secret string) string {
+	parts := strings.Split(secret, ".")
+	if len(parts) != 3 {
+		return redacted
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return redacted
+	}
+	var claims struct {
+		Exp json.Number `json:"exp,omitempty"`
+	}
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return redacted
+	}
+	payload, err = json.Marshal(claims)
+	if err != nil {
+		return redacted
+	}
+	header := base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"none","typ":"JWT"}`))
+	return header + "." + base64.RawURLEncoding.EncodeToString(payload) + "."
+}
+
+// isWatch reports whether req is a Kubernetes watch request.
+func isWatch(req *http.Request) bool {
+	w := req.URL.Query().Get("watch")
+	return w == "true" || w == "1"
+}
@@ -0,0 +1,362 @@
+package snapshot
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/accuknox/rinc/internal/kube"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/rest"
+)
+
+func TestRecordReplay(t *testing.T) {
+	a := assert.New(t)
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/counter":
+			w.Header().Set("Content-Type", "text/plain")
+			fmt.Fprintf(w, "call %d", calls.Add(1))
+		case "/auth":
+			body, _ := io.ReadAll(r.Body)
+			w.WriteHeader(http.StatusCreated)
+			fmt.Fprintf(w, "token for %s", body)
+		default:
+			http.Error(w, "not found", http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	type result struct {
+		status int
+		body   string
+		err    bool
+	}
+	do := func(client *http.Client, method, path, body string) result {
+		req, err := http.NewRequest(method, srv.URL+path, strings.NewReader(body))
+		if !a.NoError(err) {
+			return result{}
+		}
+		resp, err := client.Do(req)
+		if err != nil {
+			return result{err: true}
+		}
+		defer resp.Body.Close()
+		b, err := io.ReadAll(resp.Body)
+		a.NoError(err)
+		return result{status: resp.StatusCode, body: string(b)}
+	}
+	requests := []struct {
+		method string
+		path   string
+		body   string
+	}{
+		{http.MethodGet, "/counter", ""},
+		{http.MethodGet, "/counter", ""},
+		{http.MethodPost, "/auth", "alice"},
+		{http.MethodPost, "/auth", "bob"},
+		{http.MethodGet, "/missing", ""},
+	}
+
+	recorder := NewRecorder()
+	client := &http.Client{Transport: recorder.Transport(nil)}
+	var recorded []result
+	for _, r := range requests {
+		recorded = append(recorded, do(client, r.method, r.path, r.body))
+	}
+	ips, err := recorder.LookupIP(context.Background(), "localhost")
+	a.NoError(err)
+
+	path := filepath.Join(t.TempDir(), "bundle.tar.gz")
+	now := time.Date(2024, 10, 18, 12, 0, 0, 0, time.UTC)
+	if !a.NoError(recorder.Save(path, now)) {
+		return
+	}
+	srv.Close()
+
+	bundle, err := Open(path)
+	if !a.NoError(err) {
+		return
+	}
+	a.Equal(now, bundle.Timestamp())
+
+	client = &http.Client{Transport: bundle.Transport(nil)}
+	var replayed []result
+	for _, r := range requests {
+		replayed = append(replayed, do(client, r.method, r.path, r.body))
+	}
+	a.Equal(recorded, replayed)
+	a.Equal(result{status: http.StatusOK, body: "call 1"}, recorded[0])
+	a.Equal(result{status: http.StatusOK, body: "call 2"}, recorded[1])
+
+	// the last response is repeated once the recorded ones are exhausted
+	a.Equal(recorded[1], do(client, http.MethodGet, "/counter", ""))
+
+	// requests that weren't recorded fail
+	_, err = client.Get(srv.URL + "/unknown")
+	a.ErrorIs(err, ErrNotRecorded)
+	a.True(do(client, http.MethodPost, "/auth", "mallory").err)
+
+	replayedIPs, err := bundle.LookupIP(context.Background(), "localhost")
+	a.NoError(err)
+	a.Equal(ips, replayedIPs)
+	_, err = bundle.LookupIP(context.Background(), "example.com")
+	a.ErrorIs(err, ErrNotRecorded)
+}
+
+func TestRecordReplayKubernetes(t *testing.T) {
+	a := assert.New(t)
+	namespaces := corev1.NamespaceList{
+		TypeMeta: metav1.TypeMeta{Kind: "NamespaceList", APIVersion: "v1"},
+		ListMeta: metav1.ListMeta{ResourceVersion: "1"},
+		Items: []corev1.Namespace{
+			{ObjectMeta: metav1.ObjectMeta{Name: "accuknox", ResourceVersion: "1"}},
+			{ObjectMeta: metav1.ObjectMeta{Name: "monitoring", ResourceVersion: "1"}},
+		},
+	}
+	nodes := corev1.NodeList{
+		TypeMeta: metav1.TypeMeta{Kind: "NodeList", APIVersion: "v1"},
+		ListMeta: metav1.ListMeta{ResourceVersion: "1"},
+		Items: []corev1.Node{
+			{ObjectMeta: metav1.ObjectMeta{Name: "node-1", ResourceVersion: "1"}},
+		},
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("watch") == "true" {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusOK)
+			w.(http.Flusher).Flush()
+			<-r.Context().Done()
+			return
+		}
+		var v any
+		switch r.URL.Path {
+		case "/api/v1/namespaces":
+			v = namespaces
+		case "/api/v1/nodes":
+			v = nodes
+		default:
+			http.Error(w, "not found", http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(v)
+	}))
+	defer srv.Close()
+
+	names := func(c *rest.Config) []string {
+		client, err := kube.NewClient(c)
+		if !a.NoError(err) {
+			return nil
+		}
+		list, err := client.CoreV1().Namespaces().List(context.Background(), metav1.ListOptions{})
+		if !a.NoError(err) {
+			return nil
+		}
+		cache, err := kube.NewCache(client, kube.Scopes{kube.ResourceNodes: {}})
+		if !a.NoError(err) {
+			return nil
+		}
+		ctx, cancel := context.WithCancel(context.Background())
+		defer func() {
+			cancel()
+			cache.Shutdown()
+		}()
+		if !a.NoError(cache.Start(ctx)) {
+			return nil
+		}
+		nodes, err := cache.Nodes()
+		if !a.NoError(err) {
+			return nil
+		}
+		var names []string
+		for _, ns := range list.Items {
+			names = append(names, ns.Name)
+		}
+		for _, n := range nodes {
+			names = append(names, n.Name)
+		}
+		return names
+	}
+
+	recorder := NewRecorder()
+	want := []string{"accuknox", "monitoring", "node-1"}
+	a.Equal(want, names(recorder.RESTConfig(&rest.Config{Host: srv.URL})))
+
+	path := filepath.Join(t.TempDir(), "bundle.tar.gz")
+	if !a.NoError(recorder.Save(path, time.Now())) {
+		return
+	}
+	srv.Close()
+
+	bundle, err := Open(path)
+	if !a.NoError(err) {
+		return
+	}
+	a.Equal(want, names(bundle.RESTConfig()))
+}
+
+func TestRecordReplayCredentials(t *testing.T) {
+	a := assert.New(t)
+	// a JWT expiring in 2030, signed by the Ceph dashboard
+	jwt := base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"HS256","typ":"JWT"}`)) + "." +
+		base64.RawURLEncoding.EncodeToString([]byte(`{"exp":1893456000,"username":"admin"}`)) + "." +
+		"c2lnbmF0dXJl"
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if r.URL.Path == "/api/token" {
+			w.Header().Set("Content-Type", "application/vnd.ceph.api.v1.0+json")
+			fmt.Fprintf(w, `{"token":%q,"permissions":{"hosts":["read"]},"api_key":"k3y","tokenTTL":60}`, jwt)
+			return
+		}
+		fmt.Fprintf(w, "%s %s", r.URL.Path, body)
+	}))
+	defer srv.Close()
+
+	post := func(client *http.Client, path, contentType, body string) string {
+		resp, err := client.Post(srv.URL+path, contentType, strings.NewReader(body))
+		if !a.NoError(err, "%s %s", path, body) {
+			return ""
+		}
+		defer resp.Body.Close()
+		b, err := io.ReadAll(resp.Body)
+		a.NoError(err)
+		return string(b)
+	}
+	const (
+		jsonType = "application/json"
+		formType = "application/x-www-form-urlencoded"
+	)
+
+	recorder := NewRecorder()
+	client := &http.Client{Transport: recorder.Transport(nil)}
+	login := post(client, "/api/auth", jsonType, `{"username":"admin","password":"s3cr3t"}`)
+	query1 := post(client, "/api/v1/query", formType, "query=up&token=abc")
+	query2 := post(client, "/api/v1/query", formType, "query=down&token=abc")
+	// the live response isn't redacted
+	a.Contains(post(client, "/api/token", jsonType, `{}`), jwt)
+
+	path := filepath.Join(t.TempDir(), "bundle.tar.gz")
+	if !a.NoError(recorder.Save(path, time.Now())) {
+		return
+	}
+	_, exchanges, _, _, err := readBundle(path)
+	if !a.NoError(err) {
+		return
+	}
+	// nothing is left of the credentials of the login
+	if a.Len(exchanges, 4) {
+		a.Empty(exchanges[0].BodySHA256)
+		a.NotEmpty(exchanges[1].BodySHA256)
+		a.NotEqual(exchanges[1].BodySHA256, exchanges[2].BodySHA256)
+	}
+	srv.Close()
+
+	bundle, err := Open(path)
+	if !a.NoError(err) {
+		return
+	}
+	client = &http.Client{Transport: bundle.Transport(nil)}
+	// replayed with other credentials
+	a.Equal(login, post(client, "/api/auth", jsonType, `{"password":"other","username":"me"}`))
+	a.Equal(query2, post(client, "/api/v1/query", formType, "token=xyz&query=down"))
+	a.Equal(query1, post(client, "/api/v1/query", formType, "query=up"))
+
+	// nothing is left of the secrets of the token response but the expiry
+	// of the JWT
+	data, err := os.ReadFile(path)
+	if !a.NoError(err) {
+		return
+	}
+	gr, err := gzip.NewReader(bytes.NewReader(data))
+	if !a.NoError(err) {
+		return
+	}
+	raw, err := io.ReadAll(gr)
+	a.NoError(err)
+	a.NotContains(string(raw), jwt)
+	a.NotContains(string(raw), "c2lnbmF0dXJl")
+	a.NotContains(string(raw), "k3y")
+	unsigned := base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"none","typ":"JWT"}`)) + "." +
+		base64.RawURLEncoding.EncodeToString([]byte(`{"exp":1893456000}`)) + "."
+	a.JSONEq(
+		fmt.Sprintf(`{"token":%q,"permissions":{"hosts":["read"]},"api_key":"REDACTED","tokenTTL":60}`, unsigned),
+		post(client, "/api/token", jsonType, `{}`),
+	)
+}
+
+func TestRecordReplayChecks(t *testing.T) {
+	a := assert.New(t)
+	type status struct {
+		Reachable bool
+		Error     string
+	}
+	checks := []struct {
+		name   string
+		result status
+		err    error
+	}{
+		{"connectivity", status{Reachable: true}, nil},
+		{"connectivity", status{Error: "connection refused"}, nil},
+		{"vault", status{}, errors.New("no token")},
+	}
+
+	recorder := NewRecorder()
+	for _, c := range checks {
+		var got status
+		err := recorder.Check(c.name, &got, func() error {
+			got = c.result
+			return c.err
+		})
+		a.Equal(c.err, err)
+		a.Equal(c.result, got)
+	}
+	path := filepath.Join(t.TempDir(), "bundle.tar.gz")
+	if !a.NoError(recorder.Save(path, time.Now())) {
+		return
+	}
+
+	bundle, err := Open(path)
+	if !a.NoError(err) {
+		return
+	}
+	run := func() error {
+		t.Error("replayed check was run")
+		return nil
+	}
+	for _, c := range checks {
+		var got status
+		err := bundle.Check(c.name, &got, run)
+		if c.err != nil {
+			a.EqualError(err, c.err.Error())
+		} else {
+			a.NoError(err)
+		}
+		a.Equal(c.result, got)
+	}
+
+	// the last result is repeated once the recorded ones are exhausted
+	var got status
+	a.NoError(bundle.Check("connectivity", &got, run))
+	a.Equal(checks[1].result, got)
+
+	// checks that weren't recorded fail
+	a.ErrorIs(bundle.Check("neo4j", &got, run), ErrNotRecorded)
+}
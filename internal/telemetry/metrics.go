@@ -0,0 +1,198 @@
+// Package telemetry instruments RINC itself. Metrics record the duration of
+// scrapes and reporters, the requests sent to the scraped APIs, the MongoDB
+// writes and the alert evaluation errors. Traces follow a scrape through its
+// reporters, their API requests and MongoDB commands, and the web requests.
+package telemetry
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/accuknox/rinc/internal/conf"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/push"
+	"go.mongodb.org/mongo-driver/v2/event"
+)
+
+// Registry is the registry of the metrics of RINC itself.
+var Registry = prometheus.NewRegistry()
+
+const (
+	resultOK    = "ok"
+	resultError = "error"
+)
+
+// buckets are the histogram buckets in seconds, from 5ms to about 80s.
+var buckets = prometheus.ExponentialBuckets(0.005, 2, 15)
+
+// The scrape gauges are vectors without labels, so that they are only exposed
+// by processes that scraped, e.g., not by the web server.
+var (
+	scrapeDuration = promauto.With(Registry).NewGaugeVec(prometheus.GaugeOpts{
+		Name: "rinc_scrape_duration_seconds",
+		Help: "Duration of the latest scrape.",
+	}, nil)
+	scrapeSuccess = promauto.With(Registry).NewGaugeVec(prometheus.GaugeOpts{
+		Name: "rinc_scrape_success",
+		Help: "Whether the latest scrape generated all reports.",
+	}, nil)
+	scrapeTimestamp = promauto.With(Registry).NewGaugeVec(prometheus.GaugeOpts{
+		Name: "rinc_scrape_timestamp_seconds",
+		Help: "Time the latest scrape finished at.",
+	}, nil)
+	reporterDuration = promauto.With(Registry).NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "rinc_reporter_duration_seconds",
+		Help:    "Duration of generating the report of a reporter.",
+		Buckets: buckets,
+	}, []string{"reporter", "result"})
+	apiRequests = promauto.With(Registry).NewCounterVec(prometheus.CounterOpts{
+		Name: "rinc_api_requests_total",
+		Help: "Number of HTTP requests sent to the scraped APIs by status code, or \"error\" if no response was received.",
+	}, []string{"api", "method", "code"})
+	apiRequestDuration = promauto.With(Registry).NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "rinc_api_request_duration_seconds",
+		Help:    "Duration of HTTP requests sent to the scraped APIs until the response headers were received.",
+		Buckets: buckets,
+	}, []string{"api"})
+	mongoWriteDuration = promauto.With(Registry).NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "rinc_mongodb_write_duration_seconds",
+		Help:    "Duration of MongoDB write commands.",
+		Buckets: buckets,
+	}, []string{"command", "result"})
+	alertErrors = promauto.With(Registry).NewCounterVec(prometheus.CounterOpts{
+		Name: "rinc_alert_evaluation_errors_total",
+		Help: "Number of alerts skipped because their expressions failed to evaluate.",
+	}, []string{"reporter"})
+)
+
+// APIs whose requests are instrumented.
+const (
+	APIKubernetes = "kubernetes"
+	APIRabbitMQ   = "rabbitmq"
+	APICeph       = "ceph"
+	APIPrometheus = "prometheus"
+	APIMetabase   = "metabase"
+)
+
+// ObserveScrape records a scrape that started at start and failed if err
+// isn't nil.
+func ObserveScrape(start time.Time, err error) {
+	now := time.Now()
+	scrapeDuration.WithLabelValues().Set(now.Sub(start).Seconds())
+	scrapeTimestamp.WithLabelValues().Set(float64(now.Unix()))
+	if err != nil {
+		scrapeSuccess.WithLabelValues().Set(0)
+		return
+	}
+	scrapeSuccess.WithLabelValues().Set(1)
+}
+
+// ObserveReporter records the generation of the report stored in collection
+// that started at start and failed if err isn't nil.
+func ObserveReporter(collection string, start time.Time, err error) {
+	reporterDuration.
+		WithLabelValues(collection, result(err)).
+		Observe(time.Since(start).Seconds())
+}
+
+type reporterKey struct{}
+
+// WithReporter returns a copy of ctx in which the report stored in collection
+// is being generated.
+func WithReporter(ctx context.Context, collection string) context.Context {
+	return context.WithValue(ctx, reporterKey{}, collection)
+}
+
+// AlertEvaluationFailed records an alert of the reporter of ctx, see
+// WithReporter, that failed to evaluate.
+func AlertEvaluationFailed(ctx context.Context) {
+	collection, _ := ctx.Value(reporterKey{}).(string)
+	alertErrors.WithLabelValues(collection).Inc()
+}
+
+// Transport wraps base in a transport that records and traces the requests
+// sent to api, e.g., APIRabbitMQ.
+func Transport(api string, base http.RoundTripper) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return transport{api: api, base: base}
+}
+
+// KubeTransport wraps base in a transport that records and traces the
+// requests sent to the Kubernetes API server, see rest.Config.Wrap.
+func KubeTransport(base http.RoundTripper) http.RoundTripper {
+	return Transport(APIKubernetes, base)
+}
+
+type transport struct {
+	api  string
+	base http.RoundTripper
+}
+
+func (t transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req, end := traceRequest(t.api, req)
+	start := time.Now()
+	resp, err := t.base.RoundTrip(req)
+	end(resp, err)
+	apiRequestDuration.WithLabelValues(t.api).Observe(time.Since(start).Seconds())
+	code := resultError
+	if err == nil {
+		code = strconv.Itoa(resp.StatusCode)
+	}
+	apiRequests.WithLabelValues(t.api, req.Method, code).Inc()
+	return resp, err
+}
+
+// writeCommands are the MongoDB commands whose duration is recorded.
+var writeCommands = map[string]bool{
+	"insert":        true,
+	"update":        true,
+	"delete":        true,
+	"findAndModify": true,
+}
+
+// MongoMonitor is a MongoDB command monitor that traces the commands and
+// records the duration of write commands.
+var MongoMonitor = &event.CommandMonitor{
+	Started: startMongoSpan,
+	Succeeded: func(_ context.Context, e *event.CommandSucceededEvent) {
+		endMongoSpan(e.CommandFinishedEvent, nil)
+		observeMongo(e.CommandFinishedEvent, nil)
+	},
+	Failed: func(_ context.Context, e *event.CommandFailedEvent) {
+		endMongoSpan(e.CommandFinishedEvent, e.Failure)
+		observeMongo(e.CommandFinishedEvent, e.Failure)
+	},
+}
+
+func observeMongo(e event.CommandFinishedEvent, err error) {
+	if !writeCommands[e.CommandName] {
+		return
+	}
+	mongoWriteDuration.
+		WithLabelValues(e.CommandName, result(err)).
+		Observe(e.Duration.Seconds())
+}
+
+func result(err error) string {
+	if err != nil {
+		return resultError
+	}
+	return resultOK
+}
+
+// Push replaces the metrics of the configured job on the Pushgateway with
+// the current ones.
+func Push(ctx context.Context, c conf.Pushgateway) error {
+	err := push.New(c.URL, c.Job).Gatherer(Registry).PushContext(ctx)
+	if err != nil {
+		return fmt.Errorf("pushing metrics to %s: %w", c.URL, err)
+	}
+	return nil
+}
@@ -0,0 +1,107 @@
+package telemetry
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/accuknox/rinc/internal/conf"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/v2/event"
+)
+
+func TestTransport(t *testing.T) {
+	a := assert.New(t)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/missing" {
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	client := http.Client{Transport: Transport(APIRabbitMQ, nil)}
+	for _, path := range []string{"/api/overview", "/api/nodes", "/missing"} {
+		resp, err := client.Get(srv.URL + path)
+		if a.NoError(err) {
+			resp.Body.Close()
+		}
+	}
+	_, err := client.Get("http://127.0.0.1:0")
+	a.Error(err)
+
+	a.Equal(2.0, testutil.ToFloat64(apiRequests.WithLabelValues(APIRabbitMQ, http.MethodGet, "200")))
+	a.Equal(1.0, testutil.ToFloat64(apiRequests.WithLabelValues(APIRabbitMQ, http.MethodGet, "404")))
+	a.Equal(1.0, testutil.ToFloat64(apiRequests.WithLabelValues(APIRabbitMQ, http.MethodGet, "error")))
+}
+
+func TestAlertEvaluationFailed(t *testing.T) {
+	a := assert.New(t)
+	ctx := WithReporter(context.Background(), "dass")
+	AlertEvaluationFailed(ctx)
+	AlertEvaluationFailed(ctx)
+	a.Equal(2.0, testutil.ToFloat64(alertErrors.WithLabelValues("dass")))
+}
+
+func TestMongoMonitor(t *testing.T) {
+	a := assert.New(t)
+	finished := func(command string) event.CommandFinishedEvent {
+		return event.CommandFinishedEvent{
+			CommandName: command,
+			Duration:    20 * time.Millisecond,
+		}
+	}
+	ctx := context.Background()
+	MongoMonitor.Succeeded(ctx, &event.CommandSucceededEvent{CommandFinishedEvent: finished("insert")})
+	MongoMonitor.Failed(ctx, &event.CommandFailedEvent{
+		CommandFinishedEvent: finished("insert"),
+		Failure:              errors.New("not primary"),
+	})
+	// reads aren't recorded
+	MongoMonitor.Succeeded(ctx, &event.CommandSucceededEvent{CommandFinishedEvent: finished("find")})
+
+	want := `
+# HELP rinc_mongodb_write_duration_seconds Duration of MongoDB write commands.
+# TYPE rinc_mongodb_write_duration_seconds histogram
+`
+	for _, result := range []string{"error", "ok"} {
+		for _, le := range []string{"0.005", "0.01", "0.02", "0.04", "0.08", "0.16", "0.32", "0.64", "1.28", "2.56", "5.12", "10.24", "20.48", "40.96", "81.92", "+Inf"} {
+			count := "1"
+			if le == "0.005" || le == "0.01" {
+				count = "0"
+			}
+			want += `rinc_mongodb_write_duration_seconds_bucket{command="insert",result="` + result + `",le="` + le + `"} ` + count + "\n"
+		}
+		want += `rinc_mongodb_write_duration_seconds_sum{command="insert",result="` + result + `"} 0.02` + "\n"
+		want += `rinc_mongodb_write_duration_seconds_count{command="insert",result="` + result + `"} 1` + "\n"
+	}
+	a.NoError(testutil.CollectAndCompare(mongoWriteDuration, strings.NewReader(want)))
+}
+
+func TestObserveScrape(t *testing.T) {
+	a := assert.New(t)
+	ObserveScrape(time.Now(), errors.New("generating reports"))
+	a.Equal(0.0, testutil.ToFloat64(scrapeSuccess))
+	ObserveScrape(time.Now(), nil)
+	a.Equal(1.0, testutil.ToFloat64(scrapeSuccess))
+}
+
+func TestPush(t *testing.T) {
+	a := assert.New(t)
+	var method, path string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		method, path = r.Method, r.URL.Path
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	ObserveScrape(time.Now(), nil)
+	a.NoError(Push(context.Background(), conf.Pushgateway{URL: srv.URL, Job: "rinc"}))
+	a.Equal(http.MethodPut, method)
+	a.Equal("/metrics/job/rinc", path)
+}
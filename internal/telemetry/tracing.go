@@ -0,0 +1,142 @@
+package telemetry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+
+	"github.com/accuknox/rinc/internal/conf"
+
+	"go.mongodb.org/mongo-driver/v2/event"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// tracerName is the name of the instrumentation scope of the spans.
+const tracerName = "github.com/accuknox/rinc"
+
+// SetupTracing sets up the global tracer provider exporting the spans to the
+// configured OTLP endpoint. The returned function flushes the pending spans
+// and must be called before exiting. Spans are dropped if no endpoint is
+// configured.
+func SetupTracing(ctx context.Context, c conf.Tracing) (func(context.Context) error, error) {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{},
+		propagation.Baggage{},
+	))
+	if c.Endpoint == "" {
+		return func(context.Context) error { return nil }, nil
+	}
+
+	exporter, err := otlptracehttp.New(ctx,
+		otlptracehttp.WithEndpointURL(c.Endpoint),
+		otlptracehttp.WithHeaders(c.Headers),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("creating otlp trace exporter: %w", err)
+	}
+	res, err := resource.Merge(
+		resource.Default(),
+		resource.NewWithAttributes(
+			semconv.SchemaURL,
+			semconv.ServiceName(c.ServiceName),
+		),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("creating trace resource: %w", err)
+	}
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(
+			sdktrace.TraceIDRatioBased(c.SampleRatio),
+		)),
+	)
+	otel.SetTracerProvider(provider)
+	return provider.Shutdown, nil
+}
+
+// Start starts a span named name as a child of the span in ctx, if any.
+func Start(ctx context.Context, name string, opts ...trace.SpanStartOption) (context.Context, trace.Span) {
+	return otel.Tracer(tracerName).Start(ctx, name, opts...)
+}
+
+// End ends span, which failed if err isn't nil.
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// startClientSpan starts a span of a request sent by RINC if ctx is traced.
+// Requests without a traced context, e.g., the List and Watch requests of
+// the informers, aren't traced, as they would start traces of their own.
+func startClientSpan(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span, bool) {
+	if !trace.SpanContextFromContext(ctx).IsValid() {
+		return ctx, nil, false
+	}
+	ctx, span := Start(ctx, name,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(attrs...),
+	)
+	return ctx, span, true
+}
+
+// traceRequest starts the span of the request req sent to api. The returned
+// function ends it with the response.
+func traceRequest(api string, req *http.Request) (*http.Request, func(*http.Response, error)) {
+	ctx, span, ok := startClientSpan(req.Context(), api+" "+req.Method,
+		semconv.HTTPRequestMethodKey.String(req.Method),
+		semconv.URLFull(req.URL.Redacted()),
+		semconv.ServerAddress(req.URL.Hostname()),
+	)
+	if !ok {
+		return req, func(*http.Response, error) {}
+	}
+	return req.WithContext(ctx), func(resp *http.Response, err error) {
+		if err == nil {
+			span.SetAttributes(semconv.HTTPResponseStatusCode(resp.StatusCode))
+			if resp.StatusCode >= http.StatusBadRequest {
+				err = errors.New(resp.Status)
+			}
+		}
+		End(span, err)
+	}
+}
+
+// mongoSpans are the spans of the MongoDB commands in progress by request ID.
+var mongoSpans sync.Map
+
+func startMongoSpan(ctx context.Context, e *event.CommandStartedEvent) {
+	attrs := []attribute.KeyValue{
+		semconv.DBSystemNameMongoDB,
+		semconv.DBOperationName(e.CommandName),
+		semconv.DBNamespace(e.DatabaseName),
+	}
+	// the collection is the value of the command, e.g., {insert: "dass"}
+	if coll, ok := e.Command.Lookup(e.CommandName).StringValueOK(); ok {
+		attrs = append(attrs, semconv.DBCollectionName(coll))
+	}
+	_, span, ok := startClientSpan(ctx, "mongodb "+e.CommandName, attrs...)
+	if ok {
+		mongoSpans.Store(e.RequestID, span)
+	}
+}
+
+func endMongoSpan(e event.CommandFinishedEvent, err error) {
+	span, ok := mongoSpans.LoadAndDelete(e.RequestID)
+	if ok {
+		End(span.(trace.Span), err)
+	}
+}
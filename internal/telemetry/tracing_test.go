@@ -0,0 +1,136 @@
+package telemetry
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/event"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// recordSpans sets up a global tracer provider recording the ended spans.
+func recordSpans(t *testing.T) *tracetest.SpanRecorder {
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	prev := otel.GetTracerProvider()
+	otel.SetTracerProvider(provider)
+	t.Cleanup(func() {
+		otel.SetTracerProvider(prev)
+	})
+	return recorder
+}
+
+func attrs(span sdktrace.ReadOnlySpan) map[attribute.Key]attribute.Value {
+	m := make(map[attribute.Key]attribute.Value)
+	for _, kv := range span.Attributes() {
+		m[kv.Key] = kv.Value
+	}
+	return m
+}
+
+func TestTransportSpans(t *testing.T) {
+	a := assert.New(t)
+	recorder := recordSpans(t)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/auth" {
+			w.WriteHeader(http.StatusUnauthorized)
+		}
+	}))
+	defer srv.Close()
+	client := http.Client{Transport: Transport(APICeph, nil)}
+	get := func(ctx context.Context, path string) {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, srv.URL+path, nil)
+		if !a.NoError(err) {
+			return
+		}
+		resp, err := client.Do(req)
+		if a.NoError(err) {
+			resp.Body.Close()
+		}
+	}
+
+	// requests without a traced context, e.g., of informers, aren't traced
+	get(context.Background(), "/api/health/minimal")
+	a.Empty(recorder.Ended())
+
+	ctx, parent := Start(context.Background(), "report ceph")
+	get(ctx, "/api/health/minimal")
+	get(ctx, "/api/auth")
+	parent.End()
+
+	spans := recorder.Ended()
+	if !a.Len(spans, 3) {
+		return
+	}
+	for _, span := range spans[:2] {
+		a.Equal("ceph GET", span.Name())
+		a.Equal(parent.SpanContext().SpanID(), span.Parent().SpanID())
+	}
+	a.Equal(int64(200), attrs(spans[0])["http.response.status_code"].AsInt64())
+	a.Equal(srv.URL+"/api/health/minimal", attrs(spans[0])["url.full"].AsString())
+	a.Equal(codes.Unset, spans[0].Status().Code)
+	a.Equal(int64(401), attrs(spans[1])["http.response.status_code"].AsInt64())
+	a.Equal(codes.Error, spans[1].Status().Code)
+}
+
+func TestMongoSpans(t *testing.T) {
+	a := assert.New(t)
+	recorder := recordSpans(t)
+	cmd, err := bson.Marshal(bson.D{{Key: "insert", Value: "dass"}})
+	if !a.NoError(err) {
+		return
+	}
+	ctx, parent := Start(context.Background(), "report dass")
+	MongoMonitor.Started(ctx, &event.CommandStartedEvent{
+		Command:      cmd,
+		DatabaseName: "rinc",
+		CommandName:  "insert",
+		RequestID:    42,
+	})
+	MongoMonitor.Succeeded(ctx, &event.CommandSucceededEvent{
+		CommandFinishedEvent: event.CommandFinishedEvent{
+			CommandName:  "insert",
+			DatabaseName: "rinc",
+			RequestID:    42,
+		},
+	})
+	parent.End()
+
+	spans := recorder.Ended()
+	if !a.Len(spans, 2) {
+		return
+	}
+	a.Equal("mongodb insert", spans[0].Name())
+	a.Equal(parent.SpanContext().SpanID(), spans[0].Parent().SpanID())
+	a.Equal("dass", attrs(spans[0])["db.collection.name"].AsString())
+	a.Equal("rinc", attrs(spans[0])["db.namespace"].AsString())
+}
+
+func TestLogHandler(t *testing.T) {
+	a := assert.New(t)
+	recordSpans(t)
+	var out bytes.Buffer
+	logger := slog.New(LogHandler(slog.NewTextHandler(&out, nil))).With("reporter", "dass")
+
+	logger.InfoContext(context.Background(), "untraced")
+	a.NotContains(out.String(), "trace_id")
+
+	ctx, span := Start(context.Background(), "report dass")
+	defer span.End()
+	out.Reset()
+	logger.InfoContext(ctx, "traced")
+	sc := span.SpanContext()
+	a.Contains(out.String(), "reporter=dass")
+	a.Contains(out.String(), "trace_id="+sc.TraceID().String())
+	a.Contains(out.String(), "span_id="+sc.SpanID().String())
+}
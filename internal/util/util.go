@@ -9,6 +9,7 @@ import (
 	"strings"
 
 	"github.com/accuknox/rinc/internal/conf"
+	"github.com/accuknox/rinc/internal/telemetry"
 )
 
 const (
@@ -28,7 +29,8 @@ func GetNamespaceFromFQDN(fqdn string) (string, error) {
 	return tokens[1], nil
 }
 
-// NewLogger creates a new slog logger from the provided configuration.
+// NewLogger creates a new slog logger from the provided configuration. The
+// IDs of the trace and span in the context of a record are logged, if any.
 func NewLogger(c conf.Log) *slog.Logger {
 	var level slog.Level
 	switch c.Level {
@@ -42,10 +44,11 @@ func NewLogger(c conf.Log) *slog.Logger {
 		level = slog.LevelInfo
 	}
 	opt := &slog.HandlerOptions{Level: level}
+	var h slog.Handler = slog.NewTextHandler(os.Stderr, opt)
 	if c.Format == "json" {
-		return slog.New(slog.NewJSONHandler(os.Stderr, opt))
+		h = slog.NewJSONHandler(os.Stderr, opt)
 	}
-	return slog.New(slog.NewTextHandler(os.Stderr, opt))
+	return slog.New(telemetry.LogHandler(h))
 }
 
 // FileExists checks whether a file exists at the specified path.
@@ -0,0 +1,402 @@
+package web
+
+import (
+	_ "embed"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/accuknox/rinc/internal/conf"
+	"github.com/accuknox/rinc/internal/db"
+	"github.com/accuknox/rinc/internal/util"
+
+	"github.com/labstack/echo/v4"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+)
+
+// openAPI is the OpenAPI document of the /api/v1 routes.
+//
+//go:embed openapi.yaml
+var openAPI []byte
+
+const (
+	defaultLimit = 50
+	maxLimit     = 500
+)
+
+// apiRoutes registers the JSON API routes on g, which is mounted at /api/v1.
+func (s Srv) apiRoutes(g *echo.Group) {
+	g.GET("/openapi.yaml", s.APIOpenAPI)
+	g.GET("/runs", s.APIRuns)
+	g.GET("/runs/:id", s.APIRun)
+	g.GET("/runs/:id/:reporter", s.APIReport)
+	g.GET("/alerts", s.APIAlerts)
+}
+
+// apiPage is a page of a paginated list.
+type apiPage[T any] struct {
+	Items []T `json:"items"`
+	// Total is the number of items on all pages.
+	Total  int `json:"total"`
+	Limit  int `json:"limit"`
+	Offset int `json:"offset"`
+}
+
+// apiRun is a scrape, i.e., the reports generated at the same time.
+type apiRun struct {
+	ID        string    `json:"id"`
+	Timestamp time.Time `json:"timestamp"`
+	// Reporters are the collections of the generated reports.
+	Reporters []string `json:"reporters"`
+	// Alerts are the numbers of the alerts that fired by severity.
+	Alerts map[conf.Severity]int `json:"alerts"`
+}
+
+type apiRunDetail struct {
+	apiRun
+	Reports []apiReport `json:"reports"`
+}
+
+type apiReport struct {
+	Reporter string     `json:"reporter"`
+	Alerts   []apiAlert `json:"alerts"`
+}
+
+type apiAlert struct {
+	Severity conf.Severity `json:"severity"`
+	Message  string        `json:"message"`
+}
+
+type apiAlertItem struct {
+	RunID     string        `json:"runId"`
+	Timestamp time.Time     `json:"timestamp"`
+	Reporter  string        `json:"reporter"`
+	Severity  conf.Severity `json:"severity"`
+	Message   string        `json:"message"`
+}
+
+type apiErrorBody struct {
+	Error string `json:"error"`
+}
+
+func apiError(c echo.Context, status int, err error) error {
+	return c.JSON(status, apiErrorBody{Error: err.Error()})
+}
+
+// APIOpenAPI responds with the OpenAPI document of the API.
+func (s Srv) APIOpenAPI(c echo.Context) error {
+	return c.Blob(http.StatusOK, "application/yaml", openAPI)
+}
+
+// APIRuns responds with the runs between the from and to query parameters,
+// newest first.
+func (s Srv) APIRuns(c echo.Context) error {
+	ctx := c.Request().Context()
+	lim, err := parsePagination(c)
+	if err != nil {
+		return apiError(c, http.StatusBadRequest, err)
+	}
+	filter, err := parseTimeRange(c)
+	if err != nil {
+		return apiError(c, http.StatusBadRequest, err)
+	}
+
+	runs, total, err := s.runs.runs(ctx, filter, lim)
+	if err != nil {
+		return apiError(c, http.StatusInternalServerError, err)
+	}
+	page := apiPage[apiRun]{
+		Items:  []apiRun{},
+		Total:  total,
+		Limit:  lim.limit,
+		Offset: lim.offset,
+	}
+	if len(runs) == 0 {
+		return c.JSON(http.StatusOK, page)
+	}
+	stamps := make([]time.Time, 0, len(runs))
+	for _, r := range runs {
+		stamps = append(stamps, r.Timestamp)
+	}
+	alerts, err := s.alertDocuments(c, bson.M{
+		"timestamp": bson.M{"$in": stamps},
+	})
+	if err != nil {
+		return apiError(c, http.StatusInternalServerError, err)
+	}
+	for _, r := range runs {
+		page.Items = append(page.Items, newAPIRun(r.Timestamp, r.Reporters, alerts))
+	}
+	return c.JSON(http.StatusOK, page)
+}
+
+// APIRun responds with the reports of a run and the alerts that fired on
+// them.
+func (s Srv) APIRun(c echo.Context) error {
+	ctx := c.Request().Context()
+	at, err := time.Parse(util.IsosecLayout, c.Param("id"))
+	if err != nil {
+		return apiError(c, http.StatusBadRequest, fmt.Errorf("parsing run id: %w", err))
+	}
+
+	runs, _, err := s.runs.runs(ctx, bson.M{"timestamp": at}, pagination{limit: 1})
+	if err != nil {
+		return apiError(c, http.StatusInternalServerError, err)
+	}
+	if len(runs) == 0 {
+		return apiError(c, http.StatusNotFound, fmt.Errorf("run %q not found", c.Param("id")))
+	}
+	reporters := runs[0].Reporters
+	alerts, err := s.alertDocuments(c, bson.M{"timestamp": at})
+	if err != nil {
+		return apiError(c, http.StatusInternalServerError, err)
+	}
+
+	run := apiRunDetail{
+		apiRun:  newAPIRun(at, reporters, alerts),
+		Reports: make([]apiReport, 0, len(reporters)),
+	}
+	for _, r := range reporters {
+		report := apiReport{Reporter: r, Alerts: []apiAlert{}}
+		for _, doc := range alerts {
+			if !doc.Timestamp.Equal(at) || doc.From != r {
+				continue
+			}
+			for _, a := range doc.Alerts {
+				report.Alerts = append(report.Alerts, apiAlert{
+					Severity: a.Severity,
+					Message:  a.Message,
+				})
+			}
+		}
+		run.Reports = append(run.Reports, report)
+	}
+	return c.JSON(http.StatusOK, run)
+}
+
+// APIReport responds with the metrics document of a reporter generated in a
+// run, as it is stored.
+func (s Srv) APIReport(c echo.Context) error {
+	at, err := time.Parse(util.IsosecLayout, c.Param("id"))
+	if err != nil {
+		return apiError(c, http.StatusBadRequest, fmt.Errorf("parsing run id: %w", err))
+	}
+	reporter := c.Param("reporter")
+	metrics, err := db.NewMetrics(reporter)
+	if err != nil {
+		return apiError(c, http.StatusNotFound, err)
+	}
+
+	result := db.
+		Database(s.mongo).
+		Collection(reporter).
+		FindOne(c.Request().Context(), bson.M{"timestamp": at})
+	if err := result.Err(); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return apiError(c, http.StatusNotFound, fmt.Errorf("no %s report in run %q", reporter, c.Param("id")))
+		}
+		return apiError(c, http.StatusInternalServerError, err)
+	}
+	if err := result.Decode(metrics); err != nil {
+		return apiError(c, http.StatusInternalServerError, err)
+	}
+	return c.JSON(http.StatusOK, metrics)
+}
+
+// APIAlerts responds with the alerts that fired between the from and to
+// query parameters, newest first, optionally only of the severities and
+// reporters in the comma-separated severity and reporter query parameters.
+func (s Srv) APIAlerts(c echo.Context) error {
+	lim, err := parsePagination(c)
+	if err != nil {
+		return apiError(c, http.StatusBadRequest, err)
+	}
+	match, err := parseTimeRange(c)
+	if err != nil {
+		return apiError(c, http.StatusBadRequest, err)
+	}
+	if reporters := splitParam(c, "reporter"); len(reporters) != 0 {
+		match["from"] = bson.M{"$in": reporters}
+	}
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: match}},
+		{{Key: "$unwind", Value: bson.M{
+			"path":              "$alerts",
+			"includeArrayIndex": "index",
+		}}},
+	}
+	if severities := splitParam(c, "severity"); len(severities) != 0 {
+		for _, sev := range severities {
+			if conf.Severity(sev).Rank() == 0 {
+				return apiError(c, http.StatusBadRequest, fmt.Errorf("invalid severity %q", sev))
+			}
+		}
+		pipeline = append(pipeline, bson.D{{Key: "$match", Value: bson.M{
+			"alerts.severity": bson.M{"$in": severities},
+		}}})
+	}
+	pipeline = append(pipeline,
+		bson.D{{Key: "$sort", Value: bson.D{
+			{Key: "timestamp", Value: -1},
+			{Key: "from", Value: 1},
+			{Key: "index", Value: 1},
+		}}},
+		bson.D{{Key: "$facet", Value: bson.M{
+			"total": bson.A{bson.M{"$count": "count"}},
+			"items": bson.A{
+				bson.M{"$skip": lim.offset},
+				bson.M{"$limit": lim.limit},
+			},
+		}}},
+	)
+
+	ctx := c.Request().Context()
+	cursor, err := db.
+		Database(s.mongo).
+		Collection(db.CollectionAlerts).
+		Aggregate(ctx, pipeline)
+	if err != nil {
+		return apiError(c, http.StatusInternalServerError, err)
+	}
+	var results []struct {
+		Total []struct {
+			Count int `bson:"count"`
+		} `bson:"total"`
+		Items []struct {
+			Timestamp time.Time `bson:"timestamp"`
+			From      string    `bson:"from"`
+			Alert     db.Alert  `bson:"alerts"`
+		} `bson:"items"`
+	}
+	err = cursor.All(ctx, &results)
+	if err != nil {
+		return apiError(c, http.StatusInternalServerError, err)
+	}
+
+	page := apiPage[apiAlertItem]{
+		Items:  []apiAlertItem{},
+		Limit:  lim.limit,
+		Offset: lim.offset,
+	}
+	if len(results) != 0 {
+		if len(results[0].Total) != 0 {
+			page.Total = results[0].Total[0].Count
+		}
+		for _, item := range results[0].Items {
+			page.Items = append(page.Items, apiAlertItem{
+				RunID:     item.Timestamp.Format(util.IsosecLayout),
+				Timestamp: item.Timestamp,
+				Reporter:  item.From,
+				Severity:  item.Alert.Severity,
+				Message:   item.Alert.Message,
+			})
+		}
+	}
+	return c.JSON(http.StatusOK, page)
+}
+
+// alertDocuments returns the alert documents matching filter.
+func (s Srv) alertDocuments(c echo.Context, filter bson.M) ([]db.AlertDocument, error) {
+	ctx := c.Request().Context()
+	cursor, err := db.
+		Database(s.mongo).
+		Collection(db.CollectionAlerts).
+		Find(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("fetching alerts: %w", err)
+	}
+	var docs []db.AlertDocument
+	err = cursor.All(ctx, &docs)
+	if err != nil {
+		return nil, fmt.Errorf("decoding alerts: %w", err)
+	}
+	return docs, nil
+}
+
+// newAPIRun returns the run at t, counting the alerts of the run in docs.
+func newAPIRun(t time.Time, reporters []string, docs []db.AlertDocument) apiRun {
+	run := apiRun{
+		ID:        t.Format(util.IsosecLayout),
+		Timestamp: t,
+		Reporters: reporters,
+		Alerts:    make(map[conf.Severity]int),
+	}
+	for _, doc := range docs {
+		if !doc.Timestamp.Equal(t) {
+			continue
+		}
+		for _, a := range doc.Alerts {
+			run.Alerts[a.Severity]++
+		}
+	}
+	return run
+}
+
+type pagination struct {
+	limit  int
+	offset int
+}
+
+// parsePagination parses the limit and offset query parameters.
+func parsePagination(c echo.Context) (pagination, error) {
+	p := pagination{limit: defaultLimit}
+	if v := c.QueryParam("limit"); v != "" {
+		limit, err := strconv.Atoi(v)
+		if err != nil || limit < 1 || limit > maxLimit {
+			return p, fmt.Errorf("limit must be an integer between 1 and %d, got %q", maxLimit, v)
+		}
+		p.limit = limit
+	}
+	if v := c.QueryParam("offset"); v != "" {
+		offset, err := strconv.Atoi(v)
+		if err != nil || offset < 0 {
+			return p, fmt.Errorf("offset must be a non-negative integer, got %q", v)
+		}
+		p.offset = offset
+	}
+	return p, nil
+}
+
+// parseTimeRange returns a filter of the documents whose timestamp is within
+// the from (inclusive) and to (exclusive) query parameters. Both are
+// optional, and either RFC 3339 or isosec timestamps.
+func parseTimeRange(c echo.Context) (bson.M, error) {
+	timestamp := bson.M{}
+	for param, op := range map[string]string{"from": "$gte", "to": "$lt"} {
+		v := c.QueryParam(param)
+		if v == "" {
+			continue
+		}
+		t, err := parseTime(v)
+		if err != nil {
+			return nil, fmt.Errorf("parsing %s: %w", param, err)
+		}
+		timestamp[op] = t
+	}
+	if len(timestamp) == 0 {
+		return bson.M{}, nil
+	}
+	return bson.M{"timestamp": timestamp}, nil
+}
+
+func parseTime(v string) (time.Time, error) {
+	if util.IsIsosec(v) {
+		return time.Parse(util.IsosecLayout, v)
+	}
+	return time.Parse(time.RFC3339, v)
+}
+
+// splitParam returns the values of a comma-separated query parameter.
+func splitParam(c echo.Context, name string) []string {
+	var values []string
+	for _, v := range strings.Split(c.QueryParam(name), ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			values = append(values, v)
+		}
+	}
+	return values
+}
@@ -0,0 +1,229 @@
+package web
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/accuknox/rinc/internal/conf"
+	"github.com/accuknox/rinc/internal/db"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"sigs.k8s.io/yaml"
+)
+
+func newContext(target string) echo.Context {
+	req := httptest.NewRequest(http.MethodGet, target, nil)
+	return echo.New().NewContext(req, httptest.NewRecorder())
+}
+
+func TestParsePagination(t *testing.T) {
+	tests := []struct {
+		query   string
+		want    pagination
+		wantErr bool
+	}{
+		{query: "", want: pagination{limit: defaultLimit}},
+		{query: "limit=10&offset=20", want: pagination{limit: 10, offset: 20}},
+		{query: "limit=500", want: pagination{limit: 500}},
+		{query: "limit=0", wantErr: true},
+		{query: "limit=501", wantErr: true},
+		{query: "limit=ten", wantErr: true},
+		{query: "offset=-1", wantErr: true},
+	}
+	for _, tt := range tests {
+		a := assert.New(t)
+		got, err := parsePagination(newContext("/api/v1/runs?" + tt.query))
+		if tt.wantErr {
+			a.Error(err, "query=%s", tt.query)
+			continue
+		}
+		a.NoError(err, "query=%s", tt.query)
+		a.Equal(tt.want, got, "query=%s", tt.query)
+	}
+}
+
+func TestRunsPipeline(t *testing.T) {
+	a := assert.New(t)
+	filter := bson.M{"timestamp": bson.M{"$gte": time.Now()}}
+	pipeline := runsPipeline(filter, pagination{limit: 20, offset: 40})
+
+	// the reports of the first collection are matched, and those of the
+	// others are unioned with them
+	a.Equal("$match", pipeline[0][0].Key)
+	a.Equal(filter, pipeline[0][0].Value)
+	var unioned []string
+	for _, stage := range pipeline {
+		if stage[0].Key == "$unionWith" {
+			unioned = append(unioned, stage[0].Value.(bson.M)["coll"].(string))
+		}
+	}
+	a.Equal(db.Collections[1:], unioned)
+
+	// the runs are paginated in MongoDB
+	facet := pipeline[len(pipeline)-1][0]
+	a.Equal("$facet", facet.Key)
+	a.Equal(bson.A{
+		bson.M{"$skip": 40},
+		bson.M{"$limit": 20},
+	}, facet.Value.(bson.M)["items"])
+}
+
+// fakeRuns is a runStore of the provided runs, newest first.
+type fakeRuns []storedRun
+
+func (f fakeRuns) runs(_ context.Context, filter bson.M, p pagination) ([]storedRun, int, error) {
+	var matched []storedRun
+	for _, r := range f {
+		if at, ok := filter["timestamp"].(time.Time); ok && !at.Equal(r.Timestamp) {
+			continue
+		}
+		matched = append(matched, r)
+	}
+	start := min(p.offset, len(matched))
+	return matched[start:min(start+p.limit, len(matched))], len(matched), nil
+}
+
+func TestAPIHandlers(t *testing.T) {
+	s := Srv{runs: fakeRuns{{
+		Timestamp: time.Date(2024, 10, 18, 12, 0, 0, 0, time.UTC),
+		Reporters: []string{db.CollectionCeph},
+	}}}
+	e := echo.New()
+	s.apiRoutes(e.Group("/api/v1"))
+	tests := []struct {
+		target     string
+		wantStatus int
+		wantBody   string
+	}{
+		{
+			target:     "/api/v1/runs?offset=1",
+			wantStatus: http.StatusOK,
+			wantBody:   `{"items":[],"total":1,"limit":50,"offset":1}`,
+		},
+		{
+			target:     "/api/v1/runs/20241017120000",
+			wantStatus: http.StatusNotFound,
+			wantBody:   `{"error":"run \"20241017120000\" not found"}`,
+		},
+		{
+			target:     "/api/v1/runs/yesterday",
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			target:     "/api/v1/runs/20241018120000/nodes",
+			wantStatus: http.StatusNotFound,
+			wantBody:   `{"error":"invalid collection: \"nodes\""}`,
+		},
+	}
+	for _, tt := range tests {
+		a := assert.New(t)
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, tt.target, nil))
+		a.Equal(tt.wantStatus, rec.Code, tt.target)
+		if tt.wantBody != "" {
+			a.JSONEq(tt.wantBody, rec.Body.String(), tt.target)
+		}
+	}
+}
+
+func TestParseTimeRange(t *testing.T) {
+	from := time.Date(2024, 10, 18, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2024, 10, 19, 12, 30, 0, 0, time.UTC)
+	tests := []struct {
+		query   string
+		want    bson.M
+		wantErr bool
+	}{
+		{query: "", want: bson.M{}},
+		{
+			query: "from=2024-10-18T00:00:00Z&to=20241019123000",
+			want: bson.M{"timestamp": bson.M{
+				"$gte": from,
+				"$lt":  to,
+			}},
+		},
+		{
+			query: "from=20241018000000",
+			want:  bson.M{"timestamp": bson.M{"$gte": from}},
+		},
+		{query: "to=yesterday", wantErr: true},
+	}
+	for _, tt := range tests {
+		a := assert.New(t)
+		got, err := parseTimeRange(newContext("/api/v1/alerts?" + tt.query))
+		if tt.wantErr {
+			a.Error(err, "query=%s", tt.query)
+			continue
+		}
+		a.NoError(err, "query=%s", tt.query)
+		a.Equal(tt.want, got, "query=%s", tt.query)
+	}
+}
+
+func TestNewAPIRun(t *testing.T) {
+	a := assert.New(t)
+	at := time.Date(2024, 10, 18, 12, 0, 0, 0, time.UTC)
+	docs := []db.AlertDocument{
+		{Timestamp: at, From: db.CollectionDass, Alerts: []db.Alert{
+			{Severity: conf.SeverityWarning},
+			{Severity: conf.SeverityCritical},
+		}},
+		{Timestamp: at, From: db.CollectionPodStatus, Alerts: []db.Alert{
+			{Severity: conf.SeverityWarning},
+		}},
+		{Timestamp: at.Add(time.Hour), From: db.CollectionDass, Alerts: []db.Alert{
+			{Severity: conf.SeverityInfo},
+		}},
+	}
+	a.Equal(apiRun{
+		ID:        "20241018120000",
+		Timestamp: at,
+		Reporters: []string{db.CollectionDass, db.CollectionPodStatus},
+		Alerts: map[conf.Severity]int{
+			conf.SeverityWarning:  2,
+			conf.SeverityCritical: 1,
+		},
+	}, newAPIRun(at, []string{db.CollectionDass, db.CollectionPodStatus}, docs))
+}
+
+// TestOpenAPI checks that the OpenAPI document describes every API route.
+func TestOpenAPI(t *testing.T) {
+	a := assert.New(t)
+	var doc struct {
+		Paths      map[string]map[string]any `json:"paths"`
+		Components struct {
+			Schemas struct {
+				Reporter struct {
+					Enum []string `json:"enum"`
+				} `json:"Reporter"`
+			} `json:"schemas"`
+		} `json:"components"`
+	}
+	if !a.NoError(yaml.Unmarshal(openAPI, &doc)) {
+		return
+	}
+
+	e := echo.New()
+	Srv{router: e}.apiRoutes(e.Group("/api/v1"))
+	param := regexp.MustCompile(`:(\w+)`)
+	routes := make(map[string]bool)
+	for _, r := range e.Routes() {
+		path := param.ReplaceAllString(strings.TrimPrefix(r.Path, "/api/v1"), "{$1}")
+		method := strings.ToLower(r.Method)
+		routes[method+" "+path] = true
+		a.Contains(doc.Paths[path], method, "route %s %s is not documented", r.Method, r.Path)
+	}
+	for path, ops := range doc.Paths {
+		for method := range ops {
+			a.True(routes[method+" "+path], "documented %s %s is not routed", method, path)
+		}
+	}
+	a.Equal(db.Collections, doc.Components.Schemas.Reporter.Enum)
+}
@@ -0,0 +1,227 @@
+package web
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/accuknox/rinc/internal/conf"
+	"github.com/accuknox/rinc/view"
+	"github.com/accuknox/rinc/view/layout"
+	"github.com/accuknox/rinc/view/partial"
+
+	"github.com/labstack/echo/v4"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// userKey is the key of the authenticated user in the echo context.
+const userKey = "user"
+
+// authenticator is a method of authenticating the users of the web server.
+// Users are remembered in the session cookie once authenticated, so that the
+// method is only involved in logging them in.
+type authenticator interface {
+	// login returns the user whose credentials are in the request, if any.
+	login(c echo.Context) (user string, ok bool)
+	// challenge responds to a request of a user that isn't logged in, e.g.,
+	// by redirecting them to the login page.
+	challenge(c echo.Context) error
+	// routes registers the routes the method needs, e.g., a login callback,
+	// in the `/auth` group.
+	routes(g *echo.Group)
+	// known reports whether user, who logged in earlier, is still allowed
+	// in, e.g., hasn't been removed from the configuration since.
+	known(user string) bool
+	// logout responds to a request of a user whose session was cleared.
+	logout(c echo.Context) error
+}
+
+// auth authenticates the requests with the configured method.
+type auth struct {
+	method  authenticator
+	cookies cookies
+	// maxAge is the duration after which users must log in again.
+	maxAge time.Duration
+}
+
+// newAuth returns the authentication middleware of the configured method, or
+// nil if users aren't authenticated.
+func newAuth(c conf.Auth) (*auth, error) {
+	a := &auth{
+		cookies: cookies{
+			key:      []byte(c.Session.Secret),
+			insecure: c.Session.Insecure,
+		},
+		maxAge: c.Session.MaxAge,
+	}
+	switch c.Method {
+	case "":
+		return nil, nil
+	case conf.AuthBasic:
+		a.method = newBasicAuth(c.Basic)
+	case conf.AuthOIDC:
+		method, err := newOIDCAuth(c.OIDC, a)
+		if err != nil {
+			return nil, err
+		}
+		a.method = method
+	default:
+		return nil, fmt.Errorf("unknown auth method %q", c.Method)
+	}
+	return a, nil
+}
+
+// exempt reports whether requests to route are served without
+// authentication, i.e., static files, probes, metrics and the login routes.
+func exempt(route string) bool {
+	return strings.HasPrefix(route, "/static") ||
+		strings.HasPrefix(route, "/auth/") ||
+		route == "/healthz" ||
+		route == "/readyz" ||
+		route == "/metrics"
+}
+
+// middleware serves the requests of logged in users, and challenges the
+// other ones. Users whose requests carry credentials are logged in.
+func (a *auth) middleware(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		if exempt(c.Path()) {
+			return next(c)
+		}
+		var s session
+		if err := a.cookies.get(c, sessionCookie, &s); err == nil {
+			if a.method.known(s.User) {
+				c.Set(userKey, s.User)
+				return next(c)
+			}
+			a.cookies.clear(c, sessionCookie)
+		}
+		user, ok := a.method.login(c)
+		if !ok {
+			return a.method.challenge(c)
+		}
+		if err := a.startSession(c, user); err != nil {
+			return err
+		}
+		return next(c)
+	}
+}
+
+// startSession logs user in, i.e., sets their session cookie.
+func (a *auth) startSession(c echo.Context, user string) error {
+	err := a.cookies.set(c, sessionCookie, session{User: user}, a.maxAge)
+	if err != nil {
+		return fmt.Errorf("starting session: %w", err)
+	}
+	c.Set(userKey, user)
+	slog.LogAttrs(
+		c.Request().Context(),
+		slog.LevelInfo,
+		"user logged in",
+		slog.String("user", user),
+	)
+	return nil
+}
+
+// routes registers the routes of the configured method, and the logout
+// route.
+func (a *auth) routes(g *echo.Group) {
+	a.method.routes(g)
+	g.GET("/logout", a.logout)
+}
+
+// logout clears the session cookie, and lets the configured method respond.
+func (a *auth) logout(c echo.Context) error {
+	a.cookies.clear(c, sessionCookie)
+	return a.method.logout(c)
+}
+
+// authError responds with err, as JSON to API requests and as an error page
+// otherwise.
+func authError(c echo.Context, status int, err error) error {
+	if strings.HasPrefix(c.Request().URL.Path, "/api/") {
+		return apiError(c, status, err)
+	}
+	// the status of the page matters to clients, e.g., to prompt for basic
+	// auth credentials, unlike those of the htmx partials rendered by render
+	page := layout.Base(
+		http.StatusText(status),
+		partial.Navbar(false),
+		view.Error(err.Error(), status),
+	)
+	c.Response().Header().Set(echo.HeaderContentType, echo.MIMETextHTMLCharsetUTF8)
+	c.Response().WriteHeader(status)
+	return page.Render(c.Request().Context(), c.Response())
+}
+
+var (
+	errUnauthorized = errors.New("authentication required")
+	errLoggedOut    = errors.New("logged out")
+)
+
+// basicAuth authenticates the users with a username and password, see RFC
+// 7617.
+type basicAuth struct {
+	// users maps the usernames to the bcrypt hashes of their passwords.
+	users map[string][]byte
+}
+
+// dummyHash is compared to the passwords of unknown users, so that they take
+// as long to reject as the wrong passwords of known users.
+var dummyHash, _ = bcrypt.GenerateFromPassword([]byte("rinc"), bcrypt.DefaultCost)
+
+func newBasicAuth(c conf.BasicAuth) basicAuth {
+	users := make(map[string][]byte, len(c.Users))
+	for _, u := range c.Users {
+		users[u.Username] = []byte(u.PasswordHash)
+	}
+	return basicAuth{users: users}
+}
+
+func (b basicAuth) login(c echo.Context) (string, bool) {
+	user, password, ok := c.Request().BasicAuth()
+	if !ok {
+		return "", false
+	}
+	hash, known := b.users[user]
+	if !known {
+		hash = dummyHash
+	}
+	err := bcrypt.CompareHashAndPassword(hash, []byte(password))
+	if err != nil || !known {
+		slog.LogAttrs(
+			c.Request().Context(),
+			slog.LevelWarn,
+			"invalid basic auth credentials",
+			slog.String("user", user),
+		)
+		return "", false
+	}
+	return user, true
+}
+
+func (b basicAuth) challenge(c echo.Context) error {
+	return b.unauthorized(c, "rinc", errUnauthorized)
+}
+
+func (b basicAuth) routes(*echo.Group) {}
+
+func (b basicAuth) known(user string) bool {
+	_, ok := b.users[user]
+	return ok
+}
+
+// logout challenges the browser with a realm it has no credentials for, so
+// that it forgets those of the user instead of logging them in again on the
+// next request.
+func (b basicAuth) logout(c echo.Context) error {
+	return b.unauthorized(c, "rinc-"+randomString()[:8], errLoggedOut)
+}
+
+func (b basicAuth) unauthorized(c echo.Context, realm string, err error) error {
+	c.Response().Header().Set(echo.HeaderWWWAuthenticate, fmt.Sprintf(`Basic realm=%q, charset="UTF-8"`, realm))
+	return authError(c, http.StatusUnauthorized, err)
+}
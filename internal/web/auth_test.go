@@ -0,0 +1,215 @@
+package web
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/crypto/bcrypt"
+)
+
+func TestBasicAuth(t *testing.T) {
+	hash, err := bcrypt.GenerateFromPassword([]byte("secret"), bcrypt.MinCost)
+	if !assert.NoError(t, err) {
+		return
+	}
+	a := &auth{
+		method:  basicAuth{users: map[string][]byte{"alice": hash}},
+		cookies: cookies{key: []byte("0123456789abcdef0123456789abcdef")},
+		maxAge:  time.Hour,
+	}
+	e := echo.New()
+	e.Use(a.middleware)
+	ok := func(c echo.Context) error {
+		user, _ := c.Get(userKey).(string)
+		return c.String(http.StatusOK, user)
+	}
+	e.GET("/static/*", ok)
+	e.GET("/healthz", ok)
+	e.GET("/metrics", ok)
+	e.GET("/api/v1/runs", ok)
+	e.GET("/:id", ok)
+
+	// logs in to get a session cookie
+	req := httptest.NewRequest(http.MethodGet, "/20241018120000", nil)
+	req.SetBasicAuth("alice", "secret")
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	cookies := rec.Result().Cookies()
+	if !assert.Len(t, cookies, 1) {
+		return
+	}
+	sessionCookie := cookies[0]
+
+	// a session of a user who was removed since
+	removed, err := a.cookies.encode(sessionCookie.Name, session{User: "mallory"}, time.Now().Add(time.Hour))
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	tests := []struct {
+		name       string
+		target     string
+		user       string
+		password   string
+		session    string
+		wantStatus int
+		wantBody   string
+	}{
+		{name: "static", target: "/static/style.css", wantStatus: http.StatusOK},
+		{name: "healthz", target: "/healthz", wantStatus: http.StatusOK},
+		{name: "metrics", target: "/metrics", wantStatus: http.StatusOK},
+		{name: "anonymous", target: "/20241018120000", wantStatus: http.StatusUnauthorized},
+		{
+			name:       "anonymous api",
+			target:     "/api/v1/runs",
+			wantStatus: http.StatusUnauthorized,
+			wantBody:   `{"error":"authentication required"}` + "\n",
+		},
+		{
+			name:       "valid credentials",
+			target:     "/20241018120000",
+			user:       "alice",
+			password:   "secret",
+			wantStatus: http.StatusOK,
+			wantBody:   "alice",
+		},
+		{
+			name:       "wrong password",
+			target:     "/20241018120000",
+			user:       "alice",
+			password:   "guess",
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:       "unknown user",
+			target:     "/20241018120000",
+			user:       "bob",
+			password:   "secret",
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:       "session",
+			target:     "/api/v1/runs",
+			session:    sessionCookie.Value,
+			wantStatus: http.StatusOK,
+			wantBody:   "alice",
+		},
+		{
+			name:       "session of removed user",
+			target:     "/api/v1/runs",
+			session:    removed,
+			wantStatus: http.StatusUnauthorized,
+		},
+	}
+	for _, tt := range tests {
+		a := assert.New(t)
+		req := httptest.NewRequest(http.MethodGet, tt.target, nil)
+		if tt.user != "" {
+			req.SetBasicAuth(tt.user, tt.password)
+		}
+		if tt.session != "" {
+			req.AddCookie(&http.Cookie{Name: sessionCookie.Name, Value: tt.session})
+		}
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		a.Equal(tt.wantStatus, rec.Code, tt.name)
+		if tt.wantStatus == http.StatusUnauthorized {
+			a.Contains(rec.Header().Get(echo.HeaderWWWAuthenticate), "Basic", tt.name)
+		}
+		if tt.wantBody != "" {
+			a.Equal(tt.wantBody, rec.Body.String(), tt.name)
+		}
+	}
+}
+
+func TestBasicAuthLogout(t *testing.T) {
+	a := assert.New(t)
+	auth := &auth{
+		method:  basicAuth{},
+		cookies: cookies{key: []byte("0123456789abcdef0123456789abcdef")},
+	}
+	e := echo.New()
+	rec := httptest.NewRecorder()
+	c := e.NewContext(httptest.NewRequest(http.MethodGet, "/auth/logout", nil), rec)
+	a.NoError(auth.logout(c))
+	a.Equal(http.StatusUnauthorized, rec.Code)
+
+	// the realm differs from the one the credentials were sent for
+	challenge := rec.Header().Get(echo.HeaderWWWAuthenticate)
+	a.True(strings.HasPrefix(challenge, `Basic realm="rinc-`), challenge)
+	cookies := rec.Result().Cookies()
+	if a.Len(cookies, 1) {
+		a.Equal(sessionCookie, cookies[0].Name)
+		a.Negative(cookies[0].MaxAge)
+	}
+}
+
+func TestOIDCChallenge(t *testing.T) {
+	e := echo.New()
+	o := &oidcAuth{}
+	tests := []struct {
+		method       string
+		target       string
+		wantStatus   int
+		wantLocation string
+	}{
+		{
+			method:       http.MethodGet,
+			target:       "/20241018120000/ceph?tab=osd",
+			wantStatus:   http.StatusFound,
+			wantLocation: "/auth/login?redirect=%2F20241018120000%2Fceph%3Ftab%3Dosd",
+		},
+		{method: http.MethodGet, target: "/api/v1/runs", wantStatus: http.StatusUnauthorized},
+		{method: http.MethodPost, target: "/history/search", wantStatus: http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		a := assert.New(t)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(httptest.NewRequest(tt.method, tt.target, nil), rec)
+		a.NoError(o.challenge(c), tt.target)
+		a.Equal(tt.wantStatus, rec.Code, tt.target)
+		a.Equal(tt.wantLocation, rec.Header().Get(echo.HeaderLocation), tt.target)
+	}
+}
+
+func TestAllowed(t *testing.T) {
+	tests := []struct {
+		claim         any
+		allowedGroups []string
+		want          bool
+	}{
+		{claim: nil, want: true},
+		{claim: []any{"dev"}, want: true},
+		{claim: []any{"dev", "sre"}, allowedGroups: []string{"sre", "admin"}, want: true},
+		{claim: "sre", allowedGroups: []string{"sre"}, want: true},
+		{claim: []any{"dev"}, allowedGroups: []string{"sre"}, want: false},
+		{claim: nil, allowedGroups: []string{"sre"}, want: false},
+		{claim: []any{1.0, true}, allowedGroups: []string{"sre"}, want: false},
+	}
+	for _, tt := range tests {
+		got := allowed(stringsClaim(tt.claim), tt.allowedGroups)
+		assert.Equal(t, tt.want, got, "claim=%v allowedGroups=%v", tt.claim, tt.allowedGroups)
+	}
+}
+
+func TestLocalRedirect(t *testing.T) {
+	tests := []struct {
+		redirect string
+		want     string
+	}{
+		{redirect: "", want: "/"},
+		{redirect: "/20241018120000/ceph?tab=osd", want: "/20241018120000/ceph?tab=osd"},
+		{redirect: "https://evil.example.com", want: "/"},
+		{redirect: "//evil.example.com", want: "/"},
+		{redirect: "/\\evil.example.com", want: "/"},
+		{redirect: "status", want: "/"},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, localRedirect(tt.redirect), "redirect=%q", tt.redirect)
+	}
+}
@@ -0,0 +1,124 @@
+package web
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/accuknox/rinc/internal/db"
+
+	"github.com/labstack/echo/v4"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+	"go.mongodb.org/mongo-driver/v2/mongo/readpref"
+)
+
+// readinessTimeout bounds the time spent querying MongoDB on a readiness
+// check, which must be shorter than the timeout of the probe.
+const readinessTimeout = 3 * time.Second
+
+// Healthz responds whether the web server is alive, i.e., whether it can
+// serve requests, regardless of its dependencies.
+func (s Srv) Healthz(c echo.Context) error {
+	return c.JSON(http.StatusOK, map[string]string{"status": "ok"})
+}
+
+// readiness is the body of the readiness response.
+type readiness struct {
+	Ready bool `json:"ready"`
+	// Errors are the reasons the web server isn't ready.
+	Errors []string `json:"errors,omitempty"`
+	// LatestScrape is the time of the latest scrape, if any.
+	LatestScrape *time.Time `json:"latestScrape,omitempty"`
+	// ScrapeAge is the age of the latest scrape, e.g., 1h2m3s.
+	ScrapeAge string `json:"scrapeAge,omitempty"`
+	// MaxScrapeAge is the age after which the latest scrape is stale, if
+	// checked.
+	MaxScrapeAge string `json:"maxScrapeAge,omitempty"`
+}
+
+// Readyz responds whether the web server is ready, i.e., whether MongoDB is
+// reachable and, if configured, the latest scrape isn't stale.
+func (s Srv) Readyz(c echo.Context) error {
+	ctx, cancel := context.WithTimeout(c.Request().Context(), readinessTimeout)
+	defer cancel()
+
+	var latest time.Time
+	err := s.mongo.Ping(ctx, readpref.Primary())
+	if err != nil {
+		err = fmt.Errorf("pinging mongodb: %w", err)
+	} else {
+		latest, err = s.latestScrape(ctx)
+	}
+	r := newReadiness(time.Now(), latest, err, s.config().Readiness.MaxScrapeAge)
+	if !r.Ready {
+		slog.LogAttrs(
+			ctx,
+			slog.LevelWarn,
+			"not ready",
+			slog.Any("errors", r.Errors),
+		)
+		return c.JSON(http.StatusServiceUnavailable, r)
+	}
+	return c.JSON(http.StatusOK, r)
+}
+
+// newReadiness returns the readiness at now, given the time of the latest
+// scrape, which is zero if there was none, and the error querying MongoDB.
+// The latest scrape is stale if it is older than maxAge, unless maxAge is
+// zero.
+func newReadiness(now, latest time.Time, mongoErr error, maxAge time.Duration) readiness {
+	var r readiness
+	if mongoErr != nil {
+		r.Errors = append(r.Errors, mongoErr.Error())
+	}
+	if maxAge != 0 {
+		r.MaxScrapeAge = maxAge.String()
+	}
+	if !latest.IsZero() {
+		age := now.Sub(latest).Truncate(time.Second)
+		r.LatestScrape = &latest
+		r.ScrapeAge = age.String()
+		if maxAge != 0 && age > maxAge {
+			r.Errors = append(r.Errors, fmt.Sprintf("latest scrape is older than %s", maxAge))
+		}
+	} else if mongoErr == nil && maxAge != 0 {
+		r.Errors = append(r.Errors, "no scrape found")
+	}
+	r.Ready = len(r.Errors) == 0
+	return r
+}
+
+// latestScrape returns the time of the latest report of any reporter, or the
+// zero time if there is none.
+func (s Srv) latestScrape(ctx context.Context) (time.Time, error) {
+	var latest time.Time
+	opts := options.
+		FindOne().
+		SetSort(bson.D{{Key: "timestamp", Value: -1}}).
+		SetProjection(bson.M{"timestamp": 1})
+	for _, coll := range db.Collections {
+		var doc struct {
+			Timestamp time.Time `bson:"timestamp"`
+		}
+		err := db.
+			Database(s.mongo).
+			Collection(coll).
+			FindOne(ctx, bson.M{}, opts).
+			Decode(&doc)
+		if err != nil {
+			if errors.Is(err, mongo.ErrNoDocuments) {
+				continue
+			}
+			return time.Time{}, fmt.Errorf("fetching latest %s report: %w", coll, err)
+		}
+		if doc.Timestamp.After(latest) {
+			latest = doc.Timestamp
+		}
+	}
+	return latest, nil
+}
@@ -0,0 +1,84 @@
+package web
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHealthz(t *testing.T) {
+	a := assert.New(t)
+	c := newContext("/healthz")
+	a.NoError(Srv{}.Healthz(c))
+	a.Equal(http.StatusOK, c.Response().Status)
+}
+
+func TestNewReadiness(t *testing.T) {
+	now := time.Date(2024, 10, 18, 12, 0, 0, 0, time.UTC)
+	latest := now.Add(-2*time.Hour - 30*time.Minute)
+	tests := []struct {
+		name     string
+		latest   time.Time
+		mongoErr error
+		maxAge   time.Duration
+		want     readiness
+	}{
+		{
+			name:   "unchecked age",
+			latest: latest,
+			want:   readiness{Ready: true, LatestScrape: &latest, ScrapeAge: "2h30m0s"},
+		},
+		{
+			name:   "fresh",
+			latest: latest,
+			maxAge: 9 * time.Hour,
+			want: readiness{
+				Ready:        true,
+				LatestScrape: &latest,
+				ScrapeAge:    "2h30m0s",
+				MaxScrapeAge: "9h0m0s",
+			},
+		},
+		{
+			name:   "stale",
+			latest: latest,
+			maxAge: time.Hour,
+			want: readiness{
+				Errors:       []string{"latest scrape is older than 1h0m0s"},
+				LatestScrape: &latest,
+				ScrapeAge:    "2h30m0s",
+				MaxScrapeAge: "1h0m0s",
+			},
+		},
+		{
+			name:   "no scrape",
+			maxAge: time.Hour,
+			want: readiness{
+				Errors:       []string{"no scrape found"},
+				MaxScrapeAge: "1h0m0s",
+			},
+		},
+		{
+			name: "no scrape unchecked",
+			want: readiness{Ready: true},
+		},
+		{
+			name:     "mongodb unreachable",
+			mongoErr: errors.New("pinging mongodb: server selection timeout"),
+			maxAge:   time.Hour,
+			want: readiness{
+				Errors:       []string{"pinging mongodb: server selection timeout"},
+				MaxScrapeAge: "1h0m0s",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := assert.New(t)
+			a.Equal(tt.want, newReadiness(now, tt.latest, tt.mongoErr, tt.maxAge))
+		})
+	}
+}
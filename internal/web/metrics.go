@@ -0,0 +1,24 @@
+package web
+
+import (
+	"github.com/accuknox/rinc/internal/exporter"
+	"github.com/accuknox/rinc/internal/telemetry"
+
+	"github.com/labstack/echo/v4"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// metricsHandler returns the handler of the Prometheus metrics built from the
+// latest reports, along with the metrics of the web server process itself.
+func (s Srv) metricsHandler() echo.HandlerFunc {
+	reg := prometheus.NewRegistry()
+	reg.MustRegister(
+		exporter.NewCollector(exporter.MongoSource{Client: s.mongo}, s.config),
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+	)
+	gatherers := prometheus.Gatherers{reg, telemetry.Registry}
+	return echo.WrapHandler(promhttp.HandlerFor(gatherers, promhttp.HandlerOpts{}))
+}
@@ -0,0 +1,243 @@
+package web
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/accuknox/rinc/internal/conf"
+
+	"github.com/coreos/go-oidc/v3/oidc"
+	"github.com/labstack/echo/v4"
+	"golang.org/x/oauth2"
+)
+
+const (
+	// discoveryTimeout bounds the time spent fetching the configuration of
+	// the OpenID provider on startup.
+	discoveryTimeout = 30 * time.Second
+	// loginTimeout is the time users have to log in with the OpenID provider.
+	loginTimeout = 10 * time.Minute
+)
+
+// oidcAuth authenticates the users with an OpenID provider, using the
+// authorization code flow with PKCE. Users must be members of one of the
+// allowed groups, if any.
+type oidcAuth struct {
+	auth     *auth
+	oauth    oauth2.Config
+	verifier *oidc.IDTokenVerifier
+	conf     conf.OIDC
+}
+
+// oidcState is the value of the state cookie, which ties the login callback
+// to the browser that started the login.
+type oidcState struct {
+	State    string `json:"state"`
+	Nonce    string `json:"nonce"`
+	Verifier string `json:"verifier"`
+	// Redirect is the path users are redirected to once logged in.
+	Redirect string `json:"redirect"`
+}
+
+// newOIDCAuth discovers the configuration of the OpenID provider. Logged in
+// users are remembered by a.
+func newOIDCAuth(c conf.OIDC, a *auth) (*oidcAuth, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), discoveryTimeout)
+	defer cancel()
+	provider, err := oidc.NewProvider(ctx, c.IssuerURL)
+	if err != nil {
+		return nil, fmt.Errorf("discovering openid provider %q: %w", c.IssuerURL, err)
+	}
+	return &oidcAuth{
+		auth: a,
+		oauth: oauth2.Config{
+			ClientID:     c.ClientID,
+			ClientSecret: c.ClientSecret,
+			Endpoint:     provider.Endpoint(),
+			RedirectURL:  c.RedirectURL,
+			Scopes:       append([]string{oidc.ScopeOpenID}, c.Scopes...),
+		},
+		verifier: provider.Verifier(&oidc.Config{ClientID: c.ClientID}),
+		conf:     c,
+	}, nil
+}
+
+// login always fails, as the users log in with the OpenID provider.
+func (o *oidcAuth) login(echo.Context) (string, bool) {
+	return "", false
+}
+
+// challenge redirects the page requests to the login route, which returns to
+// the requested page. The other requests, e.g., API requests, are rejected.
+func (o *oidcAuth) challenge(c echo.Context) error {
+	req := c.Request()
+	if req.Method != http.MethodGet || strings.HasPrefix(req.URL.Path, "/api/") {
+		return authError(c, http.StatusUnauthorized, errUnauthorized)
+	}
+	query := url.Values{"redirect": {req.URL.RequestURI()}}
+	return c.Redirect(http.StatusFound, "/auth/login?"+query.Encode())
+}
+
+func (o *oidcAuth) routes(g *echo.Group) {
+	g.GET("/login", o.startLogin)
+	g.GET("/callback", o.callback)
+}
+
+// known always succeeds, as the groups of the users are only known when they
+// log in. Their sessions expire after the configured max age.
+func (o *oidcAuth) known(string) bool {
+	return true
+}
+
+// logout redirects to the history page, which redirects to the login page.
+func (o *oidcAuth) logout(c echo.Context) error {
+	return c.Redirect(http.StatusFound, "/")
+}
+
+// startLogin redirects to the login page of the OpenID provider.
+func (o *oidcAuth) startLogin(c echo.Context) error {
+	state := oidcState{
+		State:    randomString(),
+		Nonce:    randomString(),
+		Verifier: oauth2.GenerateVerifier(),
+		Redirect: localRedirect(c.QueryParam("redirect")),
+	}
+	if err := o.auth.cookies.set(c, stateCookie, state, loginTimeout); err != nil {
+		return authError(c, http.StatusInternalServerError, err)
+	}
+	authURL := o.oauth.AuthCodeURL(
+		state.State,
+		oidc.Nonce(state.Nonce),
+		oauth2.S256ChallengeOption(state.Verifier),
+	)
+	return c.Redirect(http.StatusFound, authURL)
+}
+
+// callback logs in the user the OpenID provider redirected back with an
+// authorization code, if they are a member of an allowed group.
+func (o *oidcAuth) callback(c echo.Context) error {
+	ctx := c.Request().Context()
+	var state oidcState
+	if err := o.auth.cookies.get(c, stateCookie, &state); err != nil {
+		return authError(c, http.StatusBadRequest, fmt.Errorf("login expired, please try again: %w", err))
+	}
+	o.auth.cookies.clear(c, stateCookie)
+	if errCode := c.QueryParam("error"); errCode != "" {
+		return authError(c, http.StatusUnauthorized, fmt.Errorf("login failed: %s: %s", errCode, c.QueryParam("error_description")))
+	}
+	if c.QueryParam("state") != state.State {
+		return authError(c, http.StatusBadRequest, errors.New("login state mismatch, please try again"))
+	}
+
+	token, err := o.oauth.Exchange(ctx, c.QueryParam("code"), oauth2.VerifierOption(state.Verifier))
+	if err != nil {
+		return authError(c, http.StatusBadGateway, fmt.Errorf("exchanging authorization code: %w", err))
+	}
+	rawIDToken, ok := token.Extra("id_token").(string)
+	if !ok {
+		return authError(c, http.StatusBadGateway, errors.New("token response has no id_token"))
+	}
+	idToken, err := o.verifier.Verify(ctx, rawIDToken)
+	if err != nil {
+		return authError(c, http.StatusUnauthorized, fmt.Errorf("verifying id token: %w", err))
+	}
+	if idToken.Nonce != state.Nonce {
+		return authError(c, http.StatusUnauthorized, errors.New("id token nonce mismatch"))
+	}
+	claims := make(map[string]any)
+	if err := idToken.Claims(&claims); err != nil {
+		return authError(c, http.StatusBadGateway, fmt.Errorf("decoding id token claims: %w", err))
+	}
+
+	user := username(claims, idToken.Subject)
+	groups := stringsClaim(claims[o.conf.GroupsClaim])
+	if !allowed(groups, o.conf.AllowedGroups) {
+		slog.LogAttrs(
+			ctx,
+			slog.LevelWarn,
+			"user isn't a member of an allowed group",
+			slog.String("user", user),
+			slog.Any("groups", groups),
+		)
+		return authError(c, http.StatusForbidden, fmt.Errorf("%s isn't a member of an allowed group", user))
+	}
+	if err := o.auth.startSession(c, user); err != nil {
+		return authError(c, http.StatusInternalServerError, err)
+	}
+	return c.Redirect(http.StatusFound, state.Redirect)
+}
+
+// username returns the most readable identifier of the user in the claims of
+// an ID token, falling back to its subject.
+func username(claims map[string]any, subject string) string {
+	for _, claim := range []string{"email", "preferred_username", "name"} {
+		if s, ok := claims[claim].(string); ok && s != "" {
+			return s
+		}
+	}
+	return subject
+}
+
+// stringsClaim returns the values of a claim that is either a string or a
+// list of strings.
+func stringsClaim(claim any) []string {
+	switch v := claim.(type) {
+	case string:
+		return []string{v}
+	case []any:
+		values := make([]string, 0, len(v))
+		for _, item := range v {
+			if s, ok := item.(string); ok {
+				values = append(values, s)
+			}
+		}
+		return values
+	}
+	return nil
+}
+
+// allowed reports whether a member of groups is allowed to log in, i.e.,
+// whether any group is allowed, or all groups are if none is.
+func allowed(groups, allowedGroups []string) bool {
+	if len(allowedGroups) == 0 {
+		return true
+	}
+	for _, g := range groups {
+		if slices.Contains(allowedGroups, g) {
+			return true
+		}
+	}
+	return false
+}
+
+// localRedirect returns redirect if it is a path of the web server, so that
+// the login can't redirect to another site, or the history page otherwise.
+func localRedirect(redirect string) string {
+	u, err := url.Parse(redirect)
+	if err != nil ||
+		u.Scheme != "" ||
+		u.Host != "" ||
+		!strings.HasPrefix(redirect, "/") ||
+		strings.HasPrefix(redirect, "//") ||
+		strings.HasPrefix(redirect, "/\\") {
+		return "/"
+	}
+	return redirect
+}
+
+// randomString returns a random URL-safe string with 256 bits of entropy.
+func randomString() string {
+	b := make([]byte, 32)
+	// never returns an error, see crypto/rand.Read
+	_, _ = rand.Read(b)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
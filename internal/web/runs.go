@@ -0,0 +1,101 @@
+package web
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/accuknox/rinc/internal/db"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+)
+
+// runStore looks up the runs, i.e., the distinct timestamps of the stored
+// reports.
+type runStore interface {
+	// runs returns a page of the runs whose reports match filter, newest
+	// first, and the number of runs on all pages.
+	runs(ctx context.Context, filter bson.M, p pagination) ([]storedRun, int, error)
+}
+
+// storedRun is a run and the collections of the reports generated in it.
+type storedRun struct {
+	Timestamp time.Time `bson:"_id"`
+	Reporters []string  `bson:"reporters"`
+}
+
+// mongoRuns looks up the runs in MongoDB.
+type mongoRuns struct {
+	mongo *mongo.Client
+}
+
+// runs satisfies the runStore interface by grouping the reports of all
+// collections by timestamp, and paginating the groups in MongoDB.
+func (m mongoRuns) runs(ctx context.Context, filter bson.M, p pagination) ([]storedRun, int, error) {
+	cursor, err := db.
+		Database(m.mongo).
+		Collection(db.Collections[0]).
+		Aggregate(ctx, runsPipeline(filter, p))
+	if err != nil {
+		return nil, 0, fmt.Errorf("aggregating runs: %w", err)
+	}
+	var results []struct {
+		Total []struct {
+			Count int `bson:"count"`
+		} `bson:"total"`
+		Items []storedRun `bson:"items"`
+	}
+	err = cursor.All(ctx, &results)
+	if err != nil {
+		return nil, 0, fmt.Errorf("decoding runs: %w", err)
+	}
+	if len(results) == 0 || len(results[0].Total) == 0 {
+		return nil, 0, nil
+	}
+	runs := results[0].Items
+	for _, r := range runs {
+		// the reporters are grouped as a set, which has no order
+		slices.SortFunc(r.Reporters, func(a, b string) int {
+			return slices.Index(db.Collections, a) - slices.Index(db.Collections, b)
+		})
+	}
+	return runs, results[0].Total[0].Count, nil
+}
+
+// runsPipeline returns the aggregation of the reports of all collections
+// matching filter into a page of runs, which runs on the first collection.
+func runsPipeline(filter bson.M, p pagination) mongo.Pipeline {
+	reports := func(coll string) mongo.Pipeline {
+		return mongo.Pipeline{
+			{{Key: "$match", Value: filter}},
+			{{Key: "$project", Value: bson.M{
+				"_id":       0,
+				"timestamp": 1,
+				"from":      bson.M{"$literal": coll},
+			}}},
+		}
+	}
+	pipeline := reports(db.Collections[0])
+	for _, coll := range db.Collections[1:] {
+		pipeline = append(pipeline, bson.D{{Key: "$unionWith", Value: bson.M{
+			"coll":     coll,
+			"pipeline": reports(coll),
+		}}})
+	}
+	return append(pipeline,
+		bson.D{{Key: "$group", Value: bson.M{
+			"_id":       "$timestamp",
+			"reporters": bson.M{"$addToSet": "$from"},
+		}}},
+		bson.D{{Key: "$sort", Value: bson.M{"_id": -1}}},
+		bson.D{{Key: "$facet", Value: bson.M{
+			"total": bson.A{bson.M{"$count": "count"}},
+			"items": bson.A{
+				bson.M{"$skip": p.offset},
+				bson.M{"$limit": p.limit},
+			},
+		}}},
+	)
+}
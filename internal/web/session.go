@@ -0,0 +1,138 @@
+package web
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	// sessionCookie is the name of the cookie of an authenticated user.
+	sessionCookie = "rinc_session"
+	// stateCookie is the name of the cookie of an OpenID Connect login in
+	// progress.
+	stateCookie = "rinc_oidc_state"
+)
+
+var (
+	errInvalidCookie = errors.New("invalid cookie signature")
+	errExpiredCookie = errors.New("expired cookie")
+)
+
+// session is the value of the session cookie.
+type session struct {
+	User string `json:"user"`
+}
+
+// cookies signs and verifies cookies with a HMAC-SHA256 key, so that their
+// values can't be forged by clients. The values aren't encrypted.
+type cookies struct {
+	key []byte
+	// insecure allows the cookies to be sent over plain HTTP.
+	insecure bool
+}
+
+// signedValue is the signed payload of a cookie.
+type signedValue struct {
+	// Expires is the unix time after which the value is rejected.
+	Expires int64           `json:"exp"`
+	Value   json.RawMessage `json:"val"`
+}
+
+// set sets the cookie named name to v, which expires after maxAge.
+func (k cookies) set(c echo.Context, name string, v any, maxAge time.Duration) error {
+	value, err := k.encode(name, v, time.Now().Add(maxAge))
+	if err != nil {
+		return err
+	}
+	c.SetCookie(k.cookie(name, value, int(maxAge.Seconds())))
+	return nil
+}
+
+// get decodes the cookie named name into v. It fails if the cookie is
+// missing, forged or expired.
+func (k cookies) get(c echo.Context, name string, v any) error {
+	cookie, err := c.Cookie(name)
+	if err != nil {
+		return fmt.Errorf("getting cookie %q: %w", name, err)
+	}
+	return k.decode(name, cookie.Value, v, time.Now())
+}
+
+// clear deletes the cookie named name.
+func (k cookies) clear(c echo.Context, name string) {
+	c.SetCookie(k.cookie(name, "", -1))
+}
+
+func (k cookies) cookie(name, value string, maxAge int) *http.Cookie {
+	return &http.Cookie{
+		Name:     name,
+		Value:    value,
+		Path:     "/",
+		MaxAge:   maxAge,
+		Secure:   !k.insecure,
+		HttpOnly: true,
+		// sent on top-level navigations, e.g., the redirect back from the
+		// OpenID provider, but not on cross-site form submissions
+		SameSite: http.SameSiteLaxMode,
+	}
+}
+
+// encode returns the signed value of the cookie named name holding v until
+// expires, i.e., `<base64 payload>.<base64 signature>`. The name is signed
+// too, so that the value of a cookie isn't accepted as another one.
+func (k cookies) encode(name string, v any, expires time.Time) (string, error) {
+	value, err := json.Marshal(v)
+	if err != nil {
+		return "", fmt.Errorf("marshalling cookie %q: %w", name, err)
+	}
+	payload, err := json.Marshal(signedValue{Expires: expires.Unix(), Value: value})
+	if err != nil {
+		return "", fmt.Errorf("marshalling cookie %q: %w", name, err)
+	}
+	encoded := base64.RawURLEncoding.EncodeToString(payload)
+	sig := base64.RawURLEncoding.EncodeToString(k.sign(name, encoded))
+	return encoded + "." + sig, nil
+}
+
+// decode verifies the value of the cookie named name at now, and decodes it
+// into v.
+func (k cookies) decode(name, value string, v any, now time.Time) error {
+	encoded, sig, ok := strings.Cut(value, ".")
+	if !ok {
+		return errInvalidCookie
+	}
+	got, err := base64.RawURLEncoding.DecodeString(sig)
+	if err != nil || !hmac.Equal(got, k.sign(name, encoded)) {
+		return errInvalidCookie
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return fmt.Errorf("decoding cookie %q: %w", name, err)
+	}
+	var signed signedValue
+	if err := json.Unmarshal(payload, &signed); err != nil {
+		return fmt.Errorf("unmarshalling cookie %q: %w", name, err)
+	}
+	if now.Unix() >= signed.Expires {
+		return errExpiredCookie
+	}
+	if err := json.Unmarshal(signed.Value, v); err != nil {
+		return fmt.Errorf("unmarshalling cookie %q: %w", name, err)
+	}
+	return nil
+}
+
+func (k cookies) sign(name, encoded string) []byte {
+	mac := hmac.New(sha256.New, k.key)
+	mac.Write([]byte(name + "=" + encoded))
+	return mac.Sum(nil)
+}
@@ -0,0 +1,55 @@
+package web
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCookies(t *testing.T) {
+	now := time.Date(2024, 10, 18, 12, 0, 0, 0, time.UTC)
+	k := cookies{key: []byte("0123456789abcdef0123456789abcdef")}
+	value, err := k.encode(sessionCookie, session{User: "alice"}, now.Add(time.Hour))
+	if !assert.NoError(t, err) {
+		return
+	}
+	forged, err := cookies{key: []byte("fedcba9876543210fedcba9876543210")}.
+		encode(sessionCookie, session{User: "alice"}, now.Add(time.Hour))
+	if !assert.NoError(t, err) {
+		return
+	}
+	tampered, err := k.encode(sessionCookie, session{User: "mallory"}, now.Add(time.Hour))
+	if !assert.NoError(t, err) {
+		return
+	}
+	// the payload of another user with the signature of alice
+	tampered = tampered[:len(tampered)-43] + value[len(value)-43:]
+
+	tests := []struct {
+		name    string
+		cookie  string
+		value   string
+		now     time.Time
+		want    session
+		wantErr error
+	}{
+		{name: "valid", cookie: sessionCookie, value: value, now: now, want: session{User: "alice"}},
+		{name: "expired", cookie: sessionCookie, value: value, now: now.Add(time.Hour), wantErr: errExpiredCookie},
+		{name: "forged", cookie: sessionCookie, value: forged, now: now, wantErr: errInvalidCookie},
+		{name: "tampered", cookie: sessionCookie, value: tampered, now: now, wantErr: errInvalidCookie},
+		{name: "other cookie", cookie: stateCookie, value: value, now: now, wantErr: errInvalidCookie},
+		{name: "unsigned", cookie: sessionCookie, value: "e30", now: now, wantErr: errInvalidCookie},
+	}
+	for _, tt := range tests {
+		a := assert.New(t)
+		var got session
+		err := k.decode(tt.cookie, tt.value, &got, tt.now)
+		if tt.wantErr != nil {
+			a.ErrorIs(err, tt.wantErr, tt.name)
+			continue
+		}
+		a.NoError(err, tt.name)
+		a.Equal(tt.want, got, tt.name)
+	}
+}
@@ -0,0 +1,12 @@
+package web
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+// Status responds with the configuration reload status.
+func (s Srv) Status(c echo.Context) error {
+	return c.JSON(http.StatusOK, s.conf.Status())
+}
@@ -0,0 +1,57 @@
+package web
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/accuknox/rinc/internal/telemetry"
+
+	"github.com/labstack/echo/v4"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// tracing is a middleware that traces every request, except for static
+// files and probes, with a span named after its route, e.g., `GET /:id`. The
+// trace of the caller is continued, if any.
+func tracing(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		req := c.Request()
+		route := c.Path()
+		if strings.HasPrefix(route, "/static") || route == "/healthz" || route == "/readyz" {
+			return next(c)
+		}
+
+		ctx := otel.GetTextMapPropagator().Extract(req.Context(), propagation.HeaderCarrier(req.Header))
+		ctx, span := telemetry.Start(ctx, req.Method+" "+route,
+			trace.WithSpanKind(trace.SpanKindServer),
+			trace.WithAttributes(
+				semconv.HTTPRequestMethodKey.String(req.Method),
+				semconv.HTTPRoute(route),
+				semconv.URLPath(req.URL.Path),
+			),
+		)
+		c.SetRequest(req.WithContext(ctx))
+
+		err := next(c)
+		if err != nil {
+			// sets the status of the response
+			c.Error(err)
+		}
+		status := c.Response().Status
+		span.SetAttributes(semconv.HTTPResponseStatusCode(status))
+		// client errors don't fail the span of a server
+		var spanErr error
+		if status >= http.StatusInternalServerError {
+			spanErr = err
+			if spanErr == nil {
+				spanErr = fmt.Errorf("%d %s", status, http.StatusText(status))
+			}
+		}
+		telemetry.End(span, spanErr)
+		return err
+	}
+}
@@ -0,0 +1,96 @@
+package web
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/accuknox/rinc/internal/conf"
+	"github.com/accuknox/rinc/internal/telemetry"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestTracing(t *testing.T) {
+	// sets up the propagation without exporting
+	_, err := telemetry.SetupTracing(context.Background(), conf.Tracing{})
+	if !assert.NoError(t, err) {
+		return
+	}
+	recorder := tracetest.NewSpanRecorder()
+	prev := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	defer otel.SetTracerProvider(prev)
+
+	e := echo.New()
+	e.Use(tracing)
+	e.GET("/static/*", func(c echo.Context) error {
+		return c.NoContent(http.StatusOK)
+	})
+	e.GET("/:id", func(c echo.Context) error {
+		if c.Param("id") == "latest" {
+			return echo.NewHTTPError(http.StatusBadRequest)
+		}
+		return c.NoContent(http.StatusOK)
+	})
+	e.GET("/:id/ceph", func(c echo.Context) error {
+		return echo.NewHTTPError(http.StatusInternalServerError)
+	})
+
+	traceID := "4bf92f3577b34da6a3ce929d0e0e4736"
+	tests := []struct {
+		target      string
+		traceparent string
+		wantName    string
+		wantStatus  int
+		wantCode    codes.Code
+	}{
+		{target: "/static/style.css"},
+		{
+			target:      "/20241018120000",
+			traceparent: "00-" + traceID + "-00f067aa0ba902b7-01",
+			wantName:    "GET /:id",
+			wantStatus:  http.StatusOK,
+		},
+		{target: "/latest", wantName: "GET /:id", wantStatus: http.StatusBadRequest},
+		{
+			target:     "/20241018120000/ceph",
+			wantName:   "GET /:id/ceph",
+			wantStatus: http.StatusInternalServerError,
+			wantCode:   codes.Error,
+		},
+	}
+	for _, tt := range tests {
+		a := assert.New(t)
+		recorder.Reset()
+		req := httptest.NewRequest(http.MethodGet, tt.target, nil)
+		if tt.traceparent != "" {
+			req.Header.Set("traceparent", tt.traceparent)
+		}
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		spans := recorder.Ended()
+		if tt.wantName == "" {
+			a.Empty(spans, "target=%s", tt.target)
+			continue
+		}
+		if !a.Len(spans, 1, "target=%s", tt.target) {
+			continue
+		}
+		span := spans[0]
+		a.Equal(tt.wantName, span.Name(), "target=%s", tt.target)
+		a.Equal(trace.SpanKindServer, span.SpanKind(), "target=%s", tt.target)
+		a.Equal(tt.wantCode, span.Status().Code, "target=%s", tt.target)
+		a.Equal(tt.wantStatus, rec.Code, "target=%s", tt.target)
+		if tt.traceparent != "" {
+			a.Equal(traceID, span.SpanContext().TraceID().String(), "target=%s", tt.target)
+		}
+	}
+}
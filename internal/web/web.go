@@ -3,6 +3,7 @@ package web
 import (
 	"context"
 	"errors"
+	"fmt"
 	"log/slog"
 	"net/http"
 	"os"
@@ -20,28 +21,61 @@ import (
 )
 
 type Srv struct {
-	conf   conf.C
+	conf   *conf.Reloader
 	router *echo.Echo
 	mongo  *mongo.Client
+	// runs looks up the runs of the API.
+	runs runStore
+	// auth authenticates the users, if configured.
+	auth *auth
 }
 
-func NewSrv(c conf.C, mongo *mongo.Client) (*Srv, error) {
+// NewSrv creates the web server. The users are authenticated with the
+// configured method, which changes only on restart.
+func NewSrv(c *conf.Reloader, mongo *mongo.Client) (*Srv, error) {
 	r := echo.New()
 	r.Pre(echoMiddleware.RemoveTrailingSlash()) // trim trailing slash
+	r.Use(tracing)
+	a, err := newAuth(c.Current().Auth)
+	if err != nil {
+		return nil, fmt.Errorf("setting up authentication: %w", err)
+	}
+	if a != nil {
+		r.Use(a.middleware)
+	}
 	return &Srv{
 		conf:   c,
 		router: r,
 		mongo:  mongo,
+		runs:   mongoRuns{mongo: mongo},
+		auth:   a,
 	}, nil
 }
 
+// config returns the current configuration, which changes when the
+// configuration files are reloaded.
+func (s Srv) config() *conf.C {
+	return s.conf.Current()
+}
+
 func (s Srv) Run(ctx context.Context) {
 	// configure logger
-	slog.SetDefault(util.NewLogger(s.conf.Log))
+	slog.SetDefault(util.NewLogger(s.config().Log))
+	s.conf.OnReload(func(c *conf.C) {
+		slog.SetDefault(util.NewLogger(c.Log))
+	})
 
 	// setup routes
+	if s.auth != nil {
+		s.auth.routes(s.router.Group("/auth"))
+	}
 	s.router.Static("/static", filepath.Join("static"))
 	s.router.GET("/", s.HistoryPage)
+	s.router.GET("/status", s.Status)
+	s.router.GET("/healthz", s.Healthz)
+	s.router.GET("/readyz", s.Readyz)
+	s.apiRoutes(s.router.Group("/api/v1"))
+	s.router.GET("/metrics", s.metricsHandler())
 	s.router.POST("/history/search", s.HistorySearch)
 	s.router.GET("/:id", s.Overview)
 	s.router.GET("/:id/rabbitmq", s.RabbitMQ)
@@ -58,6 +92,20 @@ func (s Srv) Run(ctx context.Context) {
 	defer stop()
 
 	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		err := s.conf.Watch(ctx)
+		if err != nil {
+			slog.LogAttrs(
+				ctx,
+				slog.LevelError,
+				"configuration hot reload disabled",
+				slog.String("error", err.Error()),
+			)
+		}
+	}()
+
 	wg.Add(1)
 	go func() {
 		defer wg.Done()
@@ -82,8 +130,8 @@ func (s Srv) Run(ctx context.Context) {
 
 	// graceful termination
 	ctx, cancel := context.WithCancel(context.Background())
-	if s.conf.TerminationGracePeriod != 0 {
-		ctx, cancel = context.WithTimeout(ctx, s.conf.TerminationGracePeriod)
+	if s.config().TerminationGracePeriod != 0 {
+		ctx, cancel = context.WithTimeout(ctx, s.config().TerminationGracePeriod)
 	}
 	defer cancel()
 	if err := s.router.Shutdown(ctx); err != nil {
@@ -18,74 +18,119 @@ type PV struct {
 
 type PVs []PV
 
-func (pvs PVs) AppendCapacity(pvc, ns string, cap float64) PVs {
-	var exists bool
-	for idx, pv := range pvs {
-		if pv.PVC == pvc && pv.PVCNamespace == ns {
-			exists = true
-			pvs[idx].Capacity = cap
-		}
+// Builder builds PVs from samples of individual metrics, merging the samples
+// of the same PVC into a single PV. PVs are looked up by namespace and name,
+// and are kept in the order their first sample was added.
+type Builder struct {
+	pvs   PVs
+	index map[key]int
+}
+
+type key struct {
+	namespace string
+	pvc       string
+}
+
+// NewBuilder creates an empty builder.
+func NewBuilder() *Builder {
+	return &Builder{
+		pvs:   make(PVs, 0),
+		index: make(map[key]int),
 	}
-	if !exists {
-		pvs = append(pvs, PV{
+}
+
+// SetCapacity sets the capacity of the PVC pvc in the namespace ns.
+func (b *Builder) SetCapacity(pvc, ns string, cap float64) {
+	b.get(pvc, ns).Capacity = cap
+}
+
+// SetUsed sets the used space of the PVC pvc in the namespace ns.
+func (b *Builder) SetUsed(pvc, ns string, used float64) {
+	b.get(pvc, ns).Used = used
+}
+
+// SetAvailable sets the available space of the PVC pvc in the namespace ns.
+func (b *Builder) SetAvailable(pvc, ns string, available float64) {
+	b.get(pvc, ns).Available = available
+}
+
+// SetUtilization sets the utilization percentage of the PVC pvc in the
+// namespace ns.
+func (b *Builder) SetUtilization(pvc, ns string, utilization float64) {
+	b.get(pvc, ns).UtilizationPercent = utilization
+}
+
+// PVs returns the PVs built so far.
+func (b *Builder) PVs() PVs {
+	return b.pvs
+}
+
+func (b *Builder) get(pvc, ns string) *PV {
+	k := key{namespace: ns, pvc: pvc}
+	idx, ok := b.index[k]
+	if !ok {
+		idx = len(b.pvs)
+		b.index[k] = idx
+		b.pvs = append(b.pvs, PV{
 			PVC:          pvc,
 			PVCNamespace: ns,
-			Capacity:     cap,
 		})
 	}
-	return pvs
+	return &b.pvs[idx]
 }
 
+// AppendCapacity sets the capacity of the PVC pvc in the namespace ns,
+// appending a PV for it if there is none.
+//
+// Deprecated: use Builder. Each call scans pvs, so building n PVs with the
+// Append methods costs O(n²).
+func (pvs PVs) AppendCapacity(pvc, ns string, cap float64) PVs {
+	return pvs.set(pvc, ns, func(pv *PV) { pv.Capacity = cap })
+}
+
+// AppendUsed sets the used space of the PVC pvc in the namespace ns,
+// appending a PV for it if there is none.
+//
+// Deprecated: use Builder. Each call scans pvs, so building n PVs with the
+// Append methods costs O(n²).
 func (pvs PVs) AppendUsed(pvc, ns string, used float64) PVs {
-	var exists bool
-	for idx, pv := range pvs {
-		if pv.PVC == pvc && pv.PVCNamespace == ns {
-			exists = true
-			pvs[idx].Used = used
-		}
-	}
-	if !exists {
-		pvs = append(pvs, PV{
-			PVC:          pvc,
-			PVCNamespace: ns,
-			Used:         used,
-		})
-	}
-	return pvs
+	return pvs.set(pvc, ns, func(pv *PV) { pv.Used = used })
 }
 
+// AppendAvailable sets the available space of the PVC pvc in the namespace
+// ns, appending a PV for it if there is none.
+//
+// Deprecated: use Builder. Each call scans pvs, so building n PVs with the
+// Append methods costs O(n²).
 func (pvs PVs) AppendAvailable(pvc, ns string, available float64) PVs {
-	var exists bool
-	for idx, pv := range pvs {
-		if pv.PVC == pvc && pv.PVCNamespace == ns {
-			exists = true
-			pvs[idx].Available = available
-		}
-	}
-	if !exists {
-		pvs = append(pvs, PV{
-			PVC:          pvc,
-			PVCNamespace: ns,
-			Available:    available,
-		})
-	}
-	return pvs
+	return pvs.set(pvc, ns, func(pv *PV) { pv.Available = available })
 }
 
+// AppendUtilization sets the utilization percentage of the PVC pvc in the
+// namespace ns, appending a PV for it if there is none.
+//
+// Deprecated: use Builder. Each call scans pvs, so building n PVs with the
+// Append methods costs O(n²).
 func (pvs PVs) AppendUtilization(pvc, ns string, utilization float64) PVs {
+	return pvs.set(pvc, ns, func(pv *PV) { pv.UtilizationPercent = utilization })
+}
+
+// set updates the PVs of the PVC pvc in the namespace ns with update,
+// appending a PV for it if there is none.
+func (pvs PVs) set(pvc, ns string, update func(pv *PV)) PVs {
 	var exists bool
 	for idx, pv := range pvs {
 		if pv.PVC == pvc && pv.PVCNamespace == ns {
 			exists = true
-			pvs[idx].UtilizationPercent = utilization
+			update(&pvs[idx])
 		}
 	}
 	if !exists {
 		pvs = append(pvs, PV{
-			PVC:                pvc,
-			PVCNamespace:       ns,
-			UtilizationPercent: utilization,
+			PVC:          pvc,
+			PVCNamespace: ns,
 		})
+		update(&pvs[len(pvs)-1])
 	}
 	return pvs
 }
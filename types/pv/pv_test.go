@@ -0,0 +1,100 @@
+package pv
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuilder(t *testing.T) {
+	a := assert.New(t)
+	b := NewBuilder()
+	b.SetCapacity("data", "accuknox", 100)
+	b.SetCapacity("data", "monitoring", 50)
+	b.SetUsed("data", "accuknox", 40)
+	b.SetUsed("logs", "accuknox", 5)
+	b.SetAvailable("data", "accuknox", 60)
+	b.SetUtilization("data", "accuknox", 40)
+	b.SetUtilization("data", "accuknox", 42)
+
+	a.Equal(PVs{
+		{
+			PVC:                "data",
+			PVCNamespace:       "accuknox",
+			Capacity:           100,
+			Used:               40,
+			Available:          60,
+			UtilizationPercent: 42,
+		},
+		{PVC: "data", PVCNamespace: "monitoring", Capacity: 50},
+		{PVC: "logs", PVCNamespace: "accuknox", Used: 5},
+	}, b.PVs())
+}
+
+func TestBuilderEmpty(t *testing.T) {
+	a := assert.New(t)
+	pvs := NewBuilder().PVs()
+	a.NotNil(pvs)
+	a.Empty(pvs)
+}
+
+func TestAppend(t *testing.T) {
+	a := assert.New(t)
+	var pvs PVs
+	pvs = pvs.AppendCapacity("data", "accuknox", 100)
+	pvs = pvs.AppendCapacity("data", "monitoring", 50)
+	pvs = pvs.AppendUsed("data", "accuknox", 40)
+	pvs = pvs.AppendUsed("logs", "accuknox", 5)
+	pvs = pvs.AppendAvailable("data", "accuknox", 60)
+	pvs = pvs.AppendUtilization("data", "accuknox", 42)
+
+	a.Equal(PVs{
+		{
+			PVC:                "data",
+			PVCNamespace:       "accuknox",
+			Capacity:           100,
+			Used:               40,
+			Available:          60,
+			UtilizationPercent: 42,
+		},
+		{PVC: "data", PVCNamespace: "monitoring", Capacity: 50},
+		{PVC: "logs", PVCNamespace: "accuknox", Used: 5},
+	}, pvs)
+}
+
+func BenchmarkBuilder(b *testing.B) {
+	for _, n := range []int{100, 1000, 5000} {
+		pvcs := make([]string, n)
+		for idx := range pvcs {
+			pvcs[idx] = fmt.Sprintf("pvc-%d", idx)
+		}
+		b.Run(fmt.Sprintf("map/%d", n), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				pvs := NewBuilder()
+				for _, pvc := range pvcs {
+					pvs.SetCapacity(pvc, "accuknox", 100)
+				}
+				for _, pvc := range pvcs {
+					pvs.SetUsed(pvc, "accuknox", 40)
+					pvs.SetAvailable(pvc, "accuknox", 60)
+					pvs.SetUtilization(pvc, "accuknox", 40)
+				}
+			}
+		})
+		// the scan of the deprecated Append* methods
+		b.Run(fmt.Sprintf("scan/%d", n), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				var pvs PVs
+				for _, pvc := range pvcs {
+					pvs = pvs.set(pvc, "accuknox", func(pv *PV) { pv.Capacity = 100 })
+				}
+				for _, pvc := range pvcs {
+					pvs = pvs.set(pvc, "accuknox", func(pv *PV) { pv.Used = 40 })
+					pvs = pvs.set(pvc, "accuknox", func(pv *PV) { pv.Available = 60 })
+					pvs = pvs.set(pvc, "accuknox", func(pv *PV) { pv.UtilizationPercent = 40 })
+				}
+			}
+		})
+	}
+}
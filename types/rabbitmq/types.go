@@ -43,6 +43,7 @@ type Queue struct {
 	UnacknowledgedMessages uint   `json:"messages_unacknowledged,omitempty" bson:"messages_unacknowledged,omitempty"`
 	ReadyMessages          uint   `json:"messages_ready,omitempty" bson:"messages_ready,omitempty"`
 	Name                   string `json:"name,omitempty" bson:"name,omitempty"`
+	Vhost                  string `json:"vhost,omitempty" bson:"vhost,omitempty"`
 	State                  string `json:"state,omitempty" bson:"state,omitempty"`
 }
 